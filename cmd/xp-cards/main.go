@@ -0,0 +1,150 @@
+// Command xp-cards exports and imports the static cards catalog.
+//
+//	xp-cards [-config path] export [-format json|csv|yaml] [-out file]
+//	xp-cards [-config path] import [-format json|csv|yaml] [-mode create|upsert] [-dry-run] file
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+	"github.com/Andrei-Raev/xp-loyalty/internal/repository/mongo"
+	"github.com/Andrei-Raev/xp-loyalty/internal/service"
+	"github.com/Andrei-Raev/xp-loyalty/pkg/config"
+	"github.com/Andrei-Raev/xp-loyalty/pkg/mongo_client"
+)
+
+func main() {
+	ex, err := os.Executable()
+	if err != nil {
+		log.Fatal(err)
+	}
+	configPath := path.Join(filepath.Dir(ex), "config/config.json")
+
+	var cfgPath string
+	flag.StringVar(&cfgPath, "config", configPath, "path to config")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.New(cfgPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	db, err := mongo_client.New(cfg.Mongo.URI, cfg.Mongo.Name)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cardsRepo := mongo.NewCardsRepository(db)
+	goalsRepo := mongo.NewGoalsRepository(db)
+	cardsService := service.NewCardsStaticService(cardsRepo, mongo.NewAwardsRepository(db), goalsRepo, mongo.NewChainsRepository(db), mongo.NewSeasonsRepository(db), mongo.NewUserRepository(db), service.UniformSelector{}, cfg.User)
+
+	ctx := context.Background()
+	switch cmd, args := flag.Arg(0), flag.Args()[1:]; cmd {
+	case "export":
+		err = export(ctx, cardsService, args)
+	case "import":
+		err = importCards(ctx, cardsService, args)
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), `usage:
+  %[1]s [-config path] export [-format json|csv|yaml] [-out file]
+  %[1]s [-config path] import [-format json|csv|yaml] [-mode create|upsert] [-dry-run] file
+`, filepath.Base(os.Args[0]))
+	flag.PrintDefaults()
+}
+
+func export(ctx context.Context, cardsService *service.CardsService, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "", "json, csv or yaml, by the -out extension if empty")
+	out := fs.String("out", "", "file to write, stdout if empty")
+	_ = fs.Parse(args)
+
+	if *out == "" {
+		if *format == "" {
+			*format = model.FormatJSON
+		}
+		return cardsService.ExportStatic(ctx, os.Stdout, *format)
+	}
+
+	if *format == "" {
+		f, err := model.FormatByName(*out)
+		if err != nil {
+			return err
+		}
+		*format = f
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := cardsService.ExportStatic(ctx, f, *format); err != nil {
+		_ = f.Close()
+		return err
+	}
+	// the file is complete only if it was flushed on close
+	return f.Close()
+}
+
+// importCards prints the report as json, invalid cards fail the command.
+func importCards(ctx context.Context, cardsService *service.CardsService, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	format := fs.String("format", "", "json, csv or yaml, by the file extension if empty")
+	mode := fs.String("mode", model.ImportCreate, "create or upsert, cards are matched by external_id")
+	dryRun := fs.Bool("dry-run", false, "only validate and report")
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return errors.New("import needs exactly one file")
+	}
+	name := fs.Arg(0)
+
+	if *format == "" {
+		f, err := model.FormatByName(name)
+		if err != nil {
+			return err
+		}
+		*format = f
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	report, err := cardsService.ImportStatic(ctx, f, *format, *mode, *dryRun)
+	if err != nil && !errors.Is(err, model.ErrInvalidImport) {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	return err
+}
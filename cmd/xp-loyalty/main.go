@@ -2,9 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"log"
-	"math/rand"
 	"os"
 	"path"
 	"path/filepath"
@@ -58,8 +58,6 @@ func main() {
 		log.Fatal(err)
 	}
 
-	rand.Seed(time.Now().UnixNano())
-
 	// images
 	imageRepo := mongo.NewImagesRepository(db)
 	imageService := service.NewImagesService(imageRepo)
@@ -75,22 +73,88 @@ func main() {
 
 	// cards
 	cardsRepo := mongo.NewCardsRepository(db)
-	cardsService := service.NewCardsStaticService(cardsRepo, awardsRepo)
+	if err := cardsRepo.CreateIndexes(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+	goalsRepo := mongo.NewGoalsRepository(db)
+	goalsService := service.NewGoalsService(goalsRepo, cardsRepo)
+	goalsHandler := handler.NewGoalsHandler(goalsService)
+	if err := goalsService.SeedDefaults(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+	chainsRepo := mongo.NewChainsRepository(db)
+	chainsService := service.NewChainsService(chainsRepo, cardsRepo)
+	chainsHandler := handler.NewChainsHandler(chainsService)
+	seasonsRepo := mongo.NewSeasonsRepository(db)
+	seasonsService := service.NewSeasonsService(seasonsRepo, cardsRepo)
+	seasonsHandler := handler.NewSeasonsHandler(seasonsService)
+	dailySelector, err := service.NewDailySelector(cfg.User.DailySelection)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := cfg.User.Location(); err != nil {
+		log.Fatal(err)
+	}
+	if cfg.User.ResetHour < 0 || cfg.User.ResetHour > 23 {
+		log.Fatal("user.reset_hour must be from 0 to 23")
+	}
+	if _, err := cfg.User.WeeklyReset(); err != nil {
+		log.Fatal(err)
+	}
+	// without the secret anyone could replay the draws of every user
+	if cfg.User.DailySelection.SeedSecret == "" {
+		log.Fatal("user.daily_selection.seed_secret must be set")
+	}
+	cardsService := service.NewCardsStaticService(cardsRepo, awardsRepo, goalsRepo, chainsRepo, seasonsRepo, userRepo, dailySelector, cfg.User)
 	cardsHandler := handler.NewCardsStaticHandler(cardsService, userService)
 
 	// admin
 	adminRepo := mongo.NewAdminsRepository(db)
 	adminService := service.NewAdminsService(adminRepo)
 
-	// auth
-	authRepo := mongo.NewCredentialsRepository(db)
-	authService := service.NewAuthService(authRepo, cfg.ModeratorUsername, cfg.ModeratorPassword)
-	authHandler := handler.NewAuthHandler(authService, userService, adminService)
-
 	// metrics
 	metricService := service.NewServiceMetrics()
 	metricsHandler := handler.NewMetricsHandler(metricService)
 
+	// auth
+	authRepo := mongo.NewCredentialsRepository(db)
+	sessionsRepo := mongo.NewSessionsRepository(db)
+	resetsRepo := mongo.NewPasswordResetsRepository(db)
+	authService, err := service.NewAuthService(authRepo, sessionsRepo, resetsRepo, cfg.Auth)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	moderatorID, err := authService.SeedModerator(context.Background(), cfg.ModeratorUsername, cfg.ModeratorPassword)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if moderatorID != "" {
+		moderator := model.Admin{
+			CredentialsSecure: model.CredentialsSecure{
+				ID:       moderatorID,
+				Username: cfg.ModeratorUsername,
+				Role:     model.RoleModerator,
+			},
+		}
+		if err := adminService.Create(context.Background(), moderator); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	loginAttemptsRepo := mongo.NewLoginAttemptsRepository(db)
+	throttleService := service.NewLoginThrottleService(loginAttemptsRepo, metricService, cfg.Auth.Lockout)
+	apiKeysRepo := mongo.NewAPIKeysRepository(db)
+	apiKeysService := service.NewAPIKeysService(apiKeysRepo)
+	apiKeysHandler := handler.NewAPIKeysHandler(apiKeysService)
+	authHandler := handler.NewAuthHandler(authService, userService, adminService, throttleService, apiKeysService)
+
+	oidcService, err := service.NewOIDCService(cfg.Auth.OIDC)
+	if err != nil {
+		log.Fatal(err)
+	}
+	oidcHandler := handler.NewOIDCHandler(oidcService, authService, adminService)
+
 	go func() {
 		for {
 			users, err := userService.GetAll(context.Background())
@@ -100,22 +164,18 @@ func main() {
 			if err := cardsService.UpdateConstCards(context.Background(), users); err != nil {
 				log.Fatal(err)
 			}
-			t, updatedUsers, err := cardsService.UpdateDailyCards(
-				context.Background(),
-				users,
-				cfg.User.DailyCardsNum,
-				cfg.User.UniqueGoals,
-			)
-			if err == model.ErrNoRandomCards {
-				time.Sleep(20 * time.Second)
-				continue
+			if err := cardsService.UpdateSeasonCards(context.Background(), users); err != nil {
+				log.Fatal(err)
 			}
-			if err != nil {
+			// pools with nothing to draw are skipped, the rotated users are saved anyway
+			updatedUsers, err := cardsService.RotatePools(context.Background(), users)
+			if errors.Is(err, model.ErrNoRandomCards) {
+				log.Println(err)
+			} else if err != nil {
 				log.Fatal(err)
 			}
-			for _, i := range updatedUsers {
-				users[i].LastDailyCardsUpdate = t
-				err := userService.Update(context.Background(), users[i])
+			for _, u := range updatedUsers {
+				err := userService.Update(context.Background(), u)
 				if err != nil {
 					log.Fatal(err)
 				}
@@ -125,6 +185,10 @@ func main() {
 	}()
 
 	router := gin.Default()
+	// the sign in lockout is per client IP, so only known proxies may set it
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatal(err)
+	}
 
 	router.Use(
 		gin.Recovery(),
@@ -134,35 +198,74 @@ func main() {
 	docs.SwaggerInfo.BasePath = "/"
 
 	api := router.Group("/api", metricsHandler.WithMetrics())
-	apiUser := router.Group("/api", authHandler.WithAuth(model.RoleUser))
-	apiAdmin := router.Group("/api", authHandler.WithAuth(model.RoleAdmin))
-	apiModerator := router.Group("/api", authHandler.WithAuth(model.RoleModerator))
+	apiAuth := router.Group("/api")
+	withAuth := authHandler.WithAuth
 	{
 		// auth
 		api.POST("/auth/sign-in", authHandler.SignIn)
 		api.POST("/auth/sign-up-user", authHandler.SignUpUser)
-		apiModerator.POST("/auth/sign-up-admin", authHandler.SignUpAdmin)
+		api.POST("/auth/refresh", authHandler.Refresh)
+		api.GET("/auth/oidc/login", oidcHandler.Login)
+		api.GET("/auth/oidc/callback", oidcHandler.Callback)
+		api.POST("/auth/2fa/setup", authHandler.SetupSecondFactor)
+		api.POST("/auth/2fa/verify", authHandler.VerifySecondFactor)
+		apiAuth.POST("/auth/2fa/enroll", withAuth(), authHandler.EnrollSecondFactor)
+		apiAuth.POST("/auth/2fa/enable", withAuth(), authHandler.EnableSecondFactor)
+		apiAuth.POST("/auth/2fa/disable", withAuth(), authHandler.DisableSecondFactor)
+		apiAuth.POST("/auth/logout", withAuth(), authHandler.SignOut)
+		apiAuth.POST("/auth/password", withAuth(), authHandler.ChangePassword)
+		apiAuth.POST("/auth/password-reset", withAuth(model.PermPasswordsReset), authHandler.IssuePasswordReset)
+		api.POST("/auth/password-reset/confirm", authHandler.ResetPassword)
+		apiAuth.POST("/auth/sign-up-admin", withAuth(model.PermAdminsWrite), authHandler.SignUpAdmin)
+
+		// api keys
+		apiAuth.POST("/api-keys", withAuth(model.PermAPIKeysWrite), apiKeysHandler.Create)
+		apiAuth.GET("/api-keys", withAuth(model.PermAPIKeysWrite), apiKeysHandler.GetAll)
+		apiAuth.DELETE("/api-keys/:id", withAuth(model.PermAPIKeysWrite), apiKeysHandler.Revoke)
 
 		// cards
-		apiAdmin.GET("/cards", cardsHandler.GetAllStatic)
-		apiAdmin.POST("/cards", cardsHandler.CreateStatic)
-		apiAdmin.DELETE("/cards", cardsHandler.DeleteStatic)
-		apiAdmin.POST("/cards/done", cardsHandler.UpdateCard)
-		apiAdmin.GET("/cards/:username", cardsHandler.GetUserCards)
-		apiUser.GET("/cards/profile", cardsHandler.GetProfileCards)
-		apiUser.POST("/cards/view", cardsHandler.ViewCard)
+		apiAuth.GET("/cards", withAuth(model.PermCardsStaticRead), cardsHandler.GetAllStatic)
+		apiAuth.POST("/cards", withAuth(model.PermCardsStaticWrite), cardsHandler.CreateStatic)
+		apiAuth.PUT("/cards/:id", withAuth(model.PermCardsStaticWrite), cardsHandler.UpdateStatic)
+		apiAuth.GET("/cards/versions/:id", withAuth(model.PermCardsStaticRead), cardsHandler.GetStaticVersions)
+		apiAuth.GET("/cards/replay/:username", withAuth(model.PermCardsStaticRead), cardsHandler.ReplayDailyCards)
+		apiAuth.GET("/cards/export", withAuth(model.PermCardsStaticRead), cardsHandler.ExportStatic)
+		apiAuth.POST("/cards/import", withAuth(model.PermCardsStaticWrite), cardsHandler.ImportStatic)
+		apiAuth.DELETE("/cards", withAuth(model.PermCardsStaticWrite), cardsHandler.ArchiveStatic)
+		apiAuth.POST("/cards/unarchive", withAuth(model.PermCardsStaticWrite), cardsHandler.UnarchiveStatic)
+		apiAuth.POST("/cards/done", withAuth(model.PermCardsComplete), cardsHandler.UpdateCard)
+		apiAuth.GET("/cards/:username", withAuth(model.PermCardsRead), cardsHandler.GetUserCards)
+		apiAuth.GET("/cards/profile", withAuth(model.PermProfileRead), cardsHandler.GetProfileCards)
+		apiAuth.POST("/cards/view", withAuth(model.PermCardsView), cardsHandler.ViewCard)
+
+		// goals
+		apiAuth.GET("/goals", withAuth(model.PermCardsStaticRead), goalsHandler.GetAll)
+		apiAuth.POST("/goals", withAuth(model.PermCardsStaticWrite), goalsHandler.Create)
+		apiAuth.PUT("/goals/:name", withAuth(model.PermCardsStaticWrite), goalsHandler.Update)
+		apiAuth.DELETE("/goals/:name", withAuth(model.PermCardsStaticWrite), goalsHandler.Delete)
+
+		// chains
+		apiAuth.GET("/chains", withAuth(model.PermCardsStaticRead), chainsHandler.GetAll)
+		apiAuth.PUT("/chains/:name", withAuth(model.PermCardsStaticWrite), chainsHandler.Set)
+		apiAuth.DELETE("/chains/:name", withAuth(model.PermCardsStaticWrite), chainsHandler.Delete)
+
+		// seasons
+		apiAuth.GET("/seasons", withAuth(model.PermCardsStaticRead), seasonsHandler.GetAll)
+		apiAuth.PUT("/seasons/:name", withAuth(model.PermCardsStaticWrite), seasonsHandler.Set)
+		apiAuth.DELETE("/seasons/:name", withAuth(model.PermCardsStaticWrite), seasonsHandler.Delete)
 
 		// users
-		apiAdmin.GET("/users/:username", userHandler.Get)
-		apiUser.GET("/users/profile", userHandler.Profile)
+		apiAuth.GET("/users/:username", withAuth(model.PermUsersRead), userHandler.Get)
+		apiAuth.GET("/users/profile", withAuth(model.PermProfileRead), userHandler.Profile)
+		apiAuth.PUT("/users/profile/timezone", withAuth(), userHandler.SetTimezone)
 
 		// images
 		api.GET("/images/avatar", imageHandler.GetAvatarImages)
 		api.GET("/images/prize", imageHandler.GetPrizeImages)
 		api.GET("/images/card-background", imageHandler.GetCardsBackgrounds)
-		apiAdmin.POST("/images/upload/avatar", imageHandler.UploadAvatarImage)
-		apiAdmin.POST("/images/upload/prize", imageHandler.UploadPrizeImage)
-		apiAdmin.POST("/images/upload/card-background", imageHandler.UploadCardsBackground)
+		apiAuth.POST("/images/upload/avatar", withAuth(model.PermImagesUpload), imageHandler.UploadAvatarImage)
+		apiAuth.POST("/images/upload/prize", withAuth(model.PermImagesUpload), imageHandler.UploadPrizeImage)
+		apiAuth.POST("/images/upload/card-background", withAuth(model.PermImagesUpload), imageHandler.UploadCardsBackground)
 		// static
 		router.Static("/static/images", "./static/images")
 
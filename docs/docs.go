@@ -16,52 +16,573 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/api/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "get all api keys",
+                "responses": {}
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "create api key, the key is shown only in this response",
+                "parameters": [
+                    {
+                        "description": "name and permissions of the key",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.createAPIKeyInput"
+                        }
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/api-keys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "revoke api key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "api key id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/auth/2fa/disable": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "disable two-factor authentication of the current user",
+                "parameters": [
+                    {
+                        "description": "TOTP or recovery code",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.secondFactorCodeInput"
+                        }
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/auth/2fa/enable": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "confirm two-factor enrollment of the current user, returns recovery codes",
+                "parameters": [
+                    {
+                        "description": "TOTP code",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.secondFactorCodeInput"
+                        }
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/auth/2fa/enroll": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "start two-factor enrollment of the current user",
+                "responses": {}
+            }
+        },
+        "/api/auth/2fa/setup": {
+            "post": {
+                "tags": [
+                    "auth"
+                ],
+                "summary": "start two-factor enrollment required by the role, with the challenge token of sign in",
+                "parameters": [
+                    {
+                        "description": "challenge token",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.challengeInput"
+                        }
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/auth/2fa/verify": {
+            "post": {
+                "tags": [
+                    "auth"
+                ],
+                "summary": "complete sign in with a TOTP or recovery code, confirms the enrollment if it is not done yet",
+                "parameters": [
+                    {
+                        "description": "challenge token and code",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.verifySecondFactorInput"
+                        }
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/auth/logout": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "log out, revokes access and refresh tokens of the current session",
+                "responses": {}
+            }
+        },
+        "/api/auth/oidc/callback": {
+            "get": {
+                "tags": [
+                    "auth"
+                ],
+                "summary": "callback of the identity provider, returns access and refresh tokens",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "authorization code",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "state",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/auth/oidc/login": {
+            "get": {
+                "tags": [
+                    "auth"
+                ],
+                "summary": "staff sign in with the identity provider, redirects to it",
+                "responses": {}
+            }
+        },
+        "/api/auth/password": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "change password of the current user",
+                "parameters": [
+                    {
+                        "description": "old and new password",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.changePasswordInput"
+                        }
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/auth/password-reset": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "issue a single-use password reset token for an account of a lower role",
+                "parameters": [
+                    {
+                        "description": "username",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.issuePasswordResetInput"
+                        }
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/auth/password-reset/confirm": {
+            "post": {
+                "tags": [
+                    "auth"
+                ],
+                "summary": "set a new password with a password reset token",
+                "parameters": [
+                    {
+                        "description": "reset token and new password",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.resetPasswordInput"
+                        }
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/auth/refresh": {
+            "post": {
+                "tags": [
+                    "auth"
+                ],
+                "summary": "refresh access token",
+                "parameters": [
+                    {
+                        "description": "refresh token",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.refreshInput"
+                        }
+                    }
+                ],
+                "responses": {}
+            }
+        },
         "/api/auth/sign-in": {
             "post": {
+                "description": "accounts with two-factor authentication get a challenge_token instead, it is completed with /api/auth/2fa/verify",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "sign in, answers 429 with Retry-After after too many failed attempts",
+                "parameters": [
+                    {
+                        "description": "login credentials",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.signInInput"
+                        }
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/auth/sign-up-admin": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "sign up admin or cashier, role defaults to admin",
+                "parameters": [
+                    {
+                        "description": "sign up info",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.signUpAdminInput"
+                        }
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/auth/sign-up-user": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "sign up user, timezone defaults to the venue one",
+                "parameters": [
+                    {
+                        "description": "sign up info",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.signUpUserInput"
+                        }
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/cards": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "cards"
+                ],
+                "summary": "get all static cards",
+                "responses": {}
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "cards"
+                ],
+                "summary": "create static card",
+                "parameters": [
+                    {
+                        "description": "create static card input",
+                        "name": "input",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.createStaticCardInput"
+                        }
+                    }
+                ],
+                "responses": {}
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "cards"
+                ],
+                "summary": "archive cards, they are not issued anymore but stay queryable",
+                "parameters": [
+                    {
+                        "description": "ids, cancel_pending also removes issued cards which are not done",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.archiveCardStaticsInput"
+                        }
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/cards/done": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "cards"
+                ],
+                "summary": "update card, answers 409 for cards which can't be completed now, with Retry-After during a cooldown",
+                "parameters": [
+                    {
+                        "description": "update card input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.updateCardInput"
+                        }
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/cards/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "cards"
+                ],
+                "summary": "export every static card as a file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "json (default), csv or yaml",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/cards/import": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "tags": [
-                    "auth"
+                    "cards"
                 ],
-                "summary": "sign in",
+                "summary": "import static cards from a file, nothing is imported if any card is invalid",
                 "parameters": [
                     {
-                        "description": "login credentials",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.signInInput"
-                        }
+                        "type": "file",
+                        "description": "json, csv or yaml file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "file format, by the file extension if empty",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "create (default) or upsert, cards are matched by external_id",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only validate and report",
+                        "name": "dry_run",
+                        "in": "query"
                     }
                 ],
                 "responses": {}
             }
         },
-        "/api/auth/sign-up-admin": {
-            "post": {
+        "/api/cards/profile": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
                 "tags": [
-                    "auth"
+                    "cards"
                 ],
-                "summary": "sign up admin",
+                "summary": "get all user cards by token",
+                "responses": {}
+            }
+        },
+        "/api/cards/replay/{username}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "cards"
+                ],
+                "summary": "draw the daily cards of a user on a day again, along with the cards issued that day",
                 "parameters": [
                     {
-                        "description": "sign up info",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.signUpAdminInput"
-                        }
+                        "type": "string",
+                        "description": "username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "day as 2006-01-02 in the timezone of the user, today if empty",
+                        "name": "date",
+                        "in": "query"
                     }
                 ],
                 "responses": {}
             }
         },
-        "/api/auth/sign-up-user": {
+        "/api/cards/unarchive": {
             "post": {
                 "security": [
                     {
@@ -69,24 +590,24 @@ const docTemplate = `{
                     }
                 ],
                 "tags": [
-                    "auth"
+                    "cards"
                 ],
-                "summary": "sign up user",
+                "summary": "unarchive cards",
                 "parameters": [
                     {
-                        "description": "sign up info",
+                        "description": "unarchive static cards input",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.signUpUserInput"
+                            "$ref": "#/definitions/handler.unarchiveCardStaticsInput"
                         }
                     }
                 ],
                 "responses": {}
             }
         },
-        "/api/cards": {
+        "/api/cards/versions/{id}": {
             "get": {
                 "security": [
                     {
@@ -96,9 +617,20 @@ const docTemplate = `{
                 "tags": [
                     "cards"
                 ],
-                "summary": "get all static cards",
+                "summary": "get all versions of a static card, the oldest first",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "static card id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {}
-            },
+            }
+        },
+        "/api/cards/view": {
             "post": {
                 "security": [
                     {
@@ -108,20 +640,23 @@ const docTemplate = `{
                 "tags": [
                     "cards"
                 ],
-                "summary": "create static card",
+                "summary": "view card",
                 "parameters": [
                     {
-                        "description": "create static card input",
+                        "description": "view card",
                         "name": "input",
                         "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.createStaticCardInput"
+                            "$ref": "#/definitions/handler.viewCardInput"
                         }
                     }
                 ],
                 "responses": {}
-            },
-            "delete": {
+            }
+        },
+        "/api/cards/{id}": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -130,23 +665,30 @@ const docTemplate = `{
                 "tags": [
                     "cards"
                 ],
-                "summary": "delete cards",
+                "summary": "edit static card, creates its next version",
                 "parameters": [
                     {
-                        "description": "delete static cards input",
+                        "type": "string",
+                        "description": "static card id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "new version of the static card",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.deleteCardStaticsInput"
+                            "$ref": "#/definitions/handler.createStaticCardInput"
                         }
                     }
                 ],
                 "responses": {}
             }
         },
-        "/api/cards/done": {
-            "post": {
+        "/api/cards/{username}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -155,22 +697,87 @@ const docTemplate = `{
                 "tags": [
                     "cards"
                 ],
-                "summary": "update card",
+                "summary": "get all user cards by username",
                 "parameters": [
                     {
-                        "description": "update card input",
+                        "type": "string",
+                        "description": "username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/chains": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "chains"
+                ],
+                "summary": "get unlock requirements of all const chains",
+                "responses": {}
+            }
+        },
+        "/api/chains/{name}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "chains"
+                ],
+                "summary": "set unlock requirements of a const chain, answers 422 for unknown chains and cycles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "chain name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "chains to finish first and minimum user level",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.updateCardInput"
+                            "$ref": "#/definitions/handler.setChainInput"
                         }
                     }
                 ],
                 "responses": {}
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "chains"
+                ],
+                "summary": "delete unlock requirements of a const chain",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "chain name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {}
             }
         },
-        "/api/cards/profile": {
+        "/api/goals": {
             "get": {
                 "security": [
                     {
@@ -178,13 +785,11 @@ const docTemplate = `{
                     }
                 ],
                 "tags": [
-                    "cards"
+                    "goals"
                 ],
-                "summary": "get all user cards by token",
+                "summary": "get all goals",
                 "responses": {}
-            }
-        },
-        "/api/cards/view": {
+            },
             "post": {
                 "security": [
                     {
@@ -192,39 +797,69 @@ const docTemplate = `{
                     }
                 ],
                 "tags": [
-                    "cards"
+                    "goals"
                 ],
-                "summary": "view card",
+                "summary": "create goal",
                 "parameters": [
                     {
-                        "description": "view card",
+                        "description": "name cards refer to and title",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.viewCardInput"
+                            "$ref": "#/definitions/handler.createGoalInput"
                         }
                     }
                 ],
                 "responses": {}
             }
         },
-        "/api/cards/{username}": {
-            "get": {
+        "/api/goals/{name}": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
                 "tags": [
-                    "cards"
+                    "goals"
                 ],
-                "summary": "get all user cards by username",
+                "summary": "change goal title, the name stays",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "username",
-                        "name": "username",
+                        "description": "goal name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "new title",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.updateGoalInput"
+                        }
+                    }
+                ],
+                "responses": {}
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "goals"
+                ],
+                "summary": "delete goal no card refers to",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "goal name",
+                        "name": "name",
                         "in": "path",
                         "required": true
                     }
@@ -290,62 +925,129 @@ const docTemplate = `{
                 "summary": "upload avatar image",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "avatar image",
-                        "name": "image",
-                        "in": "formData",
-                        "required": true
+                        "type": "file",
+                        "description": "avatar image",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/images/upload/card-background": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "tags": [
+                    "images"
+                ],
+                "summary": "upload card background image",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "prize image",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/images/upload/prize": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "tags": [
+                    "images"
+                ],
+                "summary": "upload prize image",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "prize image",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/seasons": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
                     }
                 ],
+                "tags": [
+                    "seasons"
+                ],
+                "summary": "get all seasons",
                 "responses": {}
             }
         },
-        "/api/images/upload/card-background": {
-            "post": {
+        "/api/seasons/{name}": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "consumes": [
-                    "multipart/form-data"
-                ],
                 "tags": [
-                    "images"
+                    "seasons"
                 ],
-                "summary": "upload card background image",
+                "summary": "create a season or change its dates, answers 422 for invalid dates",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "prize image",
-                        "name": "image",
-                        "in": "formData",
+                        "type": "string",
+                        "description": "season name",
+                        "name": "name",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "when the season starts and ends, RFC 3339",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.setSeasonInput"
+                        }
                     }
                 ],
                 "responses": {}
-            }
-        },
-        "/api/images/upload/prize": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "consumes": [
-                    "multipart/form-data"
-                ],
                 "tags": [
-                    "images"
+                    "seasons"
                 ],
-                "summary": "upload prize image",
+                "summary": "delete a season, answers 409 while cards belong to it",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "prize image",
-                        "name": "image",
-                        "in": "formData",
+                        "type": "string",
+                        "description": "season name",
+                        "name": "name",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -366,6 +1068,31 @@ const docTemplate = `{
                 "responses": {}
             }
         },
+        "/api/users/profile/timezone": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "set timezone of the current user, cards days start at the reset hour in it, empty timezone stands for the venue one",
+                "parameters": [
+                    {
+                        "description": "IANA timezone",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.setTimezoneInput"
+                        }
+                    }
+                ],
+                "responses": {}
+            }
+        },
         "/api/users/{username}": {
             "get": {
                 "security": [
@@ -391,9 +1118,70 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "handler.archiveCardStaticsInput": {
+            "type": "object",
+            "properties": {
+                "cancel_pending": {
+                    "type": "boolean"
+                },
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handler.challengeInput": {
+            "type": "object",
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.changePasswordInput": {
+            "type": "object",
+            "properties": {
+                "new_password": {
+                    "type": "string"
+                },
+                "old_password": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.createAPIKeyInput": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handler.createGoalInput": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
         "handler.createStaticCardInput": {
             "type": "object",
             "properties": {
+                "availability": {
+                    "$ref": "#/definitions/model.Availability"
+                },
                 "background_url": {
                     "type": "string"
                 },
@@ -403,6 +1191,9 @@ const docTemplate = `{
                 "chain_order": {
                     "type": "integer"
                 },
+                "checklist_settings": {
+                    "$ref": "#/definitions/model.ChecklistSettings"
+                },
                 "goal": {
                     "type": "string"
                 },
@@ -421,21 +1212,71 @@ const docTemplate = `{
                 "progress_settings": {
                     "$ref": "#/definitions/model.PrgSettings"
                 },
+                "repeat_settings": {
+                    "$ref": "#/definitions/model.RepeatSettings"
+                },
+                "season": {
+                    "type": "string"
+                },
                 "short_description": {
                     "type": "string"
                 },
+                "streak_settings": {
+                    "$ref": "#/definitions/model.StreakSettings"
+                },
                 "title": {
                     "type": "string"
                 },
                 "type": {
                     "type": "string"
+                },
+                "weight": {
+                    "type": "integer"
                 }
             }
         },
-        "handler.deleteCardStaticsInput": {
+        "handler.issuePasswordResetInput": {
             "type": "object",
             "properties": {
-                "ids": {
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.refreshInput": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.resetPasswordInput": {
+            "type": "object",
+            "properties": {
+                "new_password": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.secondFactorCodeInput": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.setChainInput": {
+            "type": "object",
+            "properties": {
+                "min_level": {
+                    "type": "integer"
+                },
+                "requires": {
                     "type": "array",
                     "items": {
                         "type": "string"
@@ -443,6 +1284,25 @@ const docTemplate = `{
                 }
             }
         },
+        "handler.setSeasonInput": {
+            "type": "object",
+            "properties": {
+                "ends_at": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.setTimezoneInput": {
+            "type": "object",
+            "properties": {
+                "timezone": {
+                    "type": "string"
+                }
+            }
+        },
         "handler.signInInput": {
             "type": "object",
             "properties": {
@@ -460,6 +1320,9 @@ const docTemplate = `{
                 "password": {
                     "type": "string"
                 },
+                "role": {
+                    "type": "integer"
+                },
                 "username": {
                     "type": "string"
                 }
@@ -477,11 +1340,25 @@ const docTemplate = `{
                 "password": {
                     "type": "string"
                 },
+                "timezone": {
+                    "type": "string"
+                },
                 "username": {
                     "type": "string"
                 }
             }
         },
+        "handler.unarchiveCardStaticsInput": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "handler.updateCardInput": {
             "type": "object",
             "properties": {
@@ -493,6 +1370,28 @@ const docTemplate = `{
                 },
                 "progress": {
                     "type": "integer"
+                },
+                "step_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.updateGoalInput": {
+            "type": "object",
+            "properties": {
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.verifySecondFactorInput": {
+            "type": "object",
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
                 }
             }
         },
@@ -504,6 +1403,29 @@ const docTemplate = `{
                 }
             }
         },
+        "model.Availability": {
+            "type": "object",
+            "properties": {
+                "ends_at": {
+                    "type": "string"
+                },
+                "from_hour": {
+                    "type": "integer"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "to_hour": {
+                    "type": "integer"
+                },
+                "weekdays": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
         "model.Award": {
             "type": "object",
             "properties": {
@@ -518,6 +1440,31 @@ const docTemplate = `{
                 }
             }
         },
+        "model.ChecklistSettings": {
+            "type": "object",
+            "properties": {
+                "award": {
+                    "$ref": "#/definitions/model.Award"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Step"
+                    }
+                }
+            }
+        },
+        "model.Milestone": {
+            "type": "object",
+            "properties": {
+                "award": {
+                    "$ref": "#/definitions/model.Award"
+                },
+                "day": {
+                    "type": "integer"
+                }
+            }
+        },
         "model.OptSettings": {
             "type": "object",
             "properties": {
@@ -553,6 +1500,46 @@ const docTemplate = `{
                     "type": "integer"
                 }
             }
+        },
+        "model.RepeatSettings": {
+            "type": "object",
+            "properties": {
+                "cooldown": {
+                    "type": "string",
+                    "example": "4h"
+                },
+                "max_completions": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Step": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.StreakSettings": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "integer"
+                },
+                "grace_days": {
+                    "type": "integer"
+                },
+                "milestones": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Milestone"
+                    }
+                }
+            }
         }
     },
     "securityDefinitions": {
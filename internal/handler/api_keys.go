@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type APIKeysService interface {
+	Create(ctx context.Context, creator model.Credentials, name string, permissions []model.Permission) (string, model.APIKey, error)
+	GetAll(ctx context.Context) ([]model.APIKey, error)
+	Revoke(ctx context.Context, id string) error
+}
+
+type APIKeysHandler struct {
+	apiKeysService APIKeysService
+}
+
+func NewAPIKeysHandler(apiKeysService APIKeysService) *APIKeysHandler {
+	return &APIKeysHandler{apiKeysService: apiKeysService}
+}
+
+type createAPIKeyInput struct {
+	Name        string             `json:"name"`
+	Permissions []model.Permission `json:"permissions"`
+}
+
+type createAPIKeyResponse struct {
+	Key    string       `json:"key"`
+	APIKey model.APIKey `json:"api_key"`
+}
+
+// @Summary create api key, the key is shown only in this response
+// @Tags api-keys
+// @Param input body createAPIKeyInput true "name and permissions of the key"
+// @Router /api/api-keys [post]
+// @Security ApiKeyAuth
+func (h APIKeysHandler) Create(ctx *gin.Context) {
+	inp := new(createAPIKeyInput)
+	if err := ctx.BindJSON(inp); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+
+	c, ok := ctx.Get(model.CtxCredentialsKey)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, M("user does not exist"))
+		return
+	}
+
+	credentials, ok := c.(model.Credentials)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, M("wrong token"))
+		return
+	}
+
+	key, apiKey, err := h.apiKeysService.Create(ctx.Request.Context(), credentials, inp.Name, inp.Permissions)
+	if err != nil {
+		if errors.Is(err, model.ErrEmptyAPIKeyName) || errors.Is(err, model.ErrWrongPermission) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, createAPIKeyResponse{Key: key, APIKey: apiKey})
+}
+
+// @Summary get all api keys
+// @Tags api-keys
+// @Router /api/api-keys [get]
+// @Security ApiKeyAuth
+func (h APIKeysHandler) GetAll(ctx *gin.Context) {
+	keys, err := h.apiKeysService.GetAll(ctx.Request.Context())
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, keys)
+}
+
+// @Summary revoke api key
+// @Tags api-keys
+// @Param id path string true "api key id"
+// @Router /api/api-keys/{id} [delete]
+// @Security ApiKeyAuth
+func (h APIKeysHandler) Revoke(ctx *gin.Context) {
+	id, err := ParsePath(ctx, "id")
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+
+	if err := h.apiKeysService.Revoke(ctx.Request.Context(), id); err != nil {
+		if errors.Is(err, model.ErrAPIKeyNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, E(err))
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, M("ok"))
+}
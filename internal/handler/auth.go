@@ -3,7 +3,12 @@ package handler
 import (
 	"context"
 	"errors"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -12,38 +17,63 @@ import (
 
 type AuthService interface {
 	SignUp(ctx context.Context, credentials model.Credentials) (string, error)
-	SignIn(ctx context.Context, username, password string) (string, error)
-	CheckAccess(ctx context.Context, token string, role model.Role) (model.Credentials, error)
+	SignIn(ctx context.Context, username, password string) (model.Tokens, *model.Challenge, error)
+	ParseChallenge(challengeToken string) (model.Challenge, error)
+	VerifySecondFactor(ctx context.Context, challenge model.Challenge, code string) (model.Tokens, []string, error)
+	EnrollSecondFactor(ctx context.Context, username string) (string, string, error)
+	EnableSecondFactor(ctx context.Context, username, code string) ([]string, error)
+	DisableSecondFactor(ctx context.Context, username, code string) error
+	Refresh(ctx context.Context, refreshToken string) (model.Tokens, error)
+	SignOut(ctx context.Context, sessionID string) error
+	ChangePassword(ctx context.Context, username, oldPassword, newPassword string) error
+	IssuePasswordReset(ctx context.Context, issuer model.Credentials, username string) (string, time.Time, error)
+	ResetPassword(ctx context.Context, token, newPassword string) error
+	CheckAccess(ctx context.Context, token string, permissions ...model.Permission) (model.Credentials, error)
 }
 
 type AuthUserService interface {
-	Create(ctx context.Context, id, username, avatarURL, nickname string, role model.Role) error
+	Create(ctx context.Context, id, username, avatarURL, nickname, timezone string, role model.Role) error
 }
 
 type AuthAdminService interface {
 	Create(ctx context.Context, admin model.Admin) error
 }
 
+type AuthAPIKeysService interface {
+	CheckAccess(ctx context.Context, key string, permissions ...model.Permission) (model.Credentials, error)
+}
+
+type AuthThrottleService interface {
+	Check(ctx context.Context, username, ip string) (time.Duration, error)
+	Fail(ctx context.Context, username, ip string) error
+	Succeed(ctx context.Context, username string) error
+}
+
 type AuthHandler struct {
-	authService  AuthService
-	userService  AuthUserService
-	adminService AuthAdminService
+	authService     AuthService
+	userService     AuthUserService
+	adminService    AuthAdminService
+	throttleService AuthThrottleService
+	apiKeysService  AuthAPIKeysService
 }
 
-func NewAuthHandler(authService AuthService, userService AuthUserService, adminService AuthAdminService) *AuthHandler {
+func NewAuthHandler(authService AuthService, userService AuthUserService, adminService AuthAdminService, throttleService AuthThrottleService, apiKeysService AuthAPIKeysService) *AuthHandler {
 	return &AuthHandler{
-		authService:  authService,
-		userService:  userService,
-		adminService: adminService,
+		authService:     authService,
+		userService:     userService,
+		adminService:    adminService,
+		throttleService: throttleService,
+		apiKeysService:  apiKeysService,
 	}
 }
 
 type signUpAdminInput struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username string     `json:"username"`
+	Password string     `json:"password"`
+	Role     model.Role `json:"role"`
 }
 
-// @Summary sign up admin
+// @Summary sign up admin or cashier, role defaults to admin
 // @Tags auth
 // @Param input body signUpAdminInput true "sign up info"
 // @Router /api/auth/sign-up-admin [post]
@@ -55,9 +85,17 @@ func (h AuthHandler) SignUpAdmin(ctx *gin.Context) {
 		return
 	}
 
+	if inp.Role == 0 {
+		inp.Role = model.RoleAdmin
+	}
+	if inp.Role != model.RoleAdmin && inp.Role != model.RoleCashier {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(model.ErrWrongRole))
+		return
+	}
+
 	credentials := model.Credentials{
 		CredentialsSecure: model.CredentialsSecure{
-			Role:     model.RoleAdmin,
+			Role:     inp.Role,
 			Username: inp.Username,
 		},
 		Password: inp.Password,
@@ -72,7 +110,7 @@ func (h AuthHandler) SignUpAdmin(ctx *gin.Context) {
 		CredentialsSecure: model.CredentialsSecure{
 			ID:       id,
 			Username: inp.Username,
-			Role:     model.RoleAdmin,
+			Role:     inp.Role,
 		},
 	}
 	if err := h.adminService.Create(ctx.Request.Context(), admin); err != nil {
@@ -87,9 +125,10 @@ type signUpUserInput struct {
 	Password  string `json:"password"`
 	Nickname  string `json:"nickname"`
 	AvatarURL string `json:"avatar_url"`
+	Timezone  string `json:"timezone"`
 }
 
-// @Summary sign up user
+// @Summary sign up user, timezone defaults to the venue one
 // @Tags auth
 // @Param input body signUpUserInput true "sign up info"
 // @Router /api/auth/sign-up-user [post]
@@ -100,6 +139,10 @@ func (h AuthHandler) SignUpUser(ctx *gin.Context) {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
 		return
 	}
+	if err := model.ValidateTimezone(inp.Timezone); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
 
 	credentials := model.Credentials{
 		CredentialsSecure: model.CredentialsSecure{
@@ -114,7 +157,7 @@ func (h AuthHandler) SignUpUser(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.userService.Create(ctx.Request.Context(), id, inp.Username, inp.AvatarURL, inp.Nickname, model.RoleUser); err != nil {
+	if err := h.userService.Create(ctx.Request.Context(), id, inp.Username, inp.AvatarURL, inp.Nickname, inp.Timezone, model.RoleUser); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
 	}
 
@@ -127,10 +170,12 @@ type signInInput struct {
 }
 
 type signInResponse struct {
-	Token string `json:"token"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
 }
 
-// @Summary sign in
+// @Summary sign in, answers 429 with Retry-After after too many failed attempts
+// @Description accounts with two-factor authentication get a challenge_token instead, it is completed with /api/auth/2fa/verify
 // @Tags auth
 // @Param input body signInInput true "login credentials"
 // @Router /api/auth/sign-in [post]
@@ -141,39 +186,501 @@ func (h AuthHandler) SignIn(ctx *gin.Context) {
 		return
 	}
 
-	token, err := h.authService.SignIn(ctx.Request.Context(), inp.Username, inp.Password)
+	ip := ctx.ClientIP()
+	retryAfter, err := h.throttleService.Check(ctx.Request.Context(), inp.Username, ip)
+	if err != nil {
+		if errors.Is(err, model.ErrTooManyAttempts) {
+			ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, E(err))
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	tokens, challenge, err := h.authService.SignIn(ctx.Request.Context(), inp.Username, inp.Password)
 	if err != nil {
 		if errors.Is(err, model.ErrWrongPassword) {
+			if err := h.throttleService.Fail(ctx.Request.Context(), inp.Username, ip); err != nil {
+				log.Println(err)
+			}
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, E(err))
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	// failures are kept until the second factor is verified too,
+	// so a known password doesn't allow unlimited code guesses
+	if challenge != nil {
+		ctx.JSON(http.StatusOK, challenge)
+		return
+	}
+
+	if err := h.throttleService.Succeed(ctx.Request.Context(), inp.Username); err != nil {
+		log.Println(err)
+	}
+
+	ctx.JSON(http.StatusOK, signInResponse{Token: tokens.AccessToken, RefreshToken: tokens.RefreshToken})
+}
+
+type challengeInput struct {
+	ChallengeToken string `json:"challenge_token"`
+}
+
+type secondFactorSecretResponse struct {
+	Secret string `json:"secret"`
+	URL    string `json:"url"`
+}
+
+// @Summary start two-factor enrollment required by the role, with the challenge token of sign in
+// @Tags auth
+// @Param input body challengeInput true "challenge token"
+// @Router /api/auth/2fa/setup [post]
+func (h AuthHandler) SetupSecondFactor(ctx *gin.Context) {
+	inp := new(challengeInput)
+	if err := ctx.BindJSON(inp); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+
+	challenge, err := h.authService.ParseChallenge(inp.ChallengeToken)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, E(err))
+		return
+	}
+
+	secret, url, err := h.authService.EnrollSecondFactor(ctx.Request.Context(), challenge.Username)
+	if err != nil {
+		if errors.Is(err, model.ErrSecondFactorEnabled) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, E(err))
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, secondFactorSecretResponse{Secret: secret, URL: url})
+}
+
+type verifySecondFactorInput struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+}
+
+type verifySecondFactorResponse struct {
+	Token         string   `json:"token"`
+	RefreshToken  string   `json:"refresh_token"`
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+// @Summary complete sign in with a TOTP or recovery code, confirms the enrollment if it is not done yet
+// @Tags auth
+// @Param input body verifySecondFactorInput true "challenge token and code"
+// @Router /api/auth/2fa/verify [post]
+func (h AuthHandler) VerifySecondFactor(ctx *gin.Context) {
+	inp := new(verifySecondFactorInput)
+	if err := ctx.BindJSON(inp); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+
+	challenge, err := h.authService.ParseChallenge(inp.ChallengeToken)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, E(err))
+		return
+	}
+
+	ip := ctx.ClientIP()
+	retryAfter, err := h.throttleService.Check(ctx.Request.Context(), challenge.Username, ip)
+	if err != nil {
+		if errors.Is(err, model.ErrTooManyAttempts) {
+			ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, E(err))
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	tokens, recoveryCodes, err := h.authService.VerifySecondFactor(ctx.Request.Context(), challenge, inp.Code)
+	if err != nil {
+		if errors.Is(err, model.ErrWrongCode) {
+			if err := h.throttleService.Fail(ctx.Request.Context(), challenge.Username, ip); err != nil {
+				log.Println(err)
+			}
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, E(err))
+			return
+		}
+
+		if errors.Is(err, model.ErrSecondFactorNotEnrolled) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, E(err))
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	if err := h.throttleService.Succeed(ctx.Request.Context(), challenge.Username); err != nil {
+		log.Println(err)
+	}
+
+	ctx.JSON(http.StatusOK, verifySecondFactorResponse{
+		Token:         tokens.AccessToken,
+		RefreshToken:  tokens.RefreshToken,
+		RecoveryCodes: recoveryCodes,
+	})
+}
+
+// @Summary start two-factor enrollment of the current user
+// @Tags auth
+// @Router /api/auth/2fa/enroll [post]
+// @Security ApiKeyAuth
+func (h AuthHandler) EnrollSecondFactor(ctx *gin.Context) {
+	c, ok := ctx.Get(model.CtxCredentialsKey)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, M("user does not exist"))
+		return
+	}
+
+	credentials, ok := c.(model.Credentials)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, M("wrong token"))
+		return
+	}
+
+	secret, url, err := h.authService.EnrollSecondFactor(ctx.Request.Context(), credentials.Username)
+	if err != nil {
+		if errors.Is(err, model.ErrSecondFactorEnabled) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, E(err))
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, secondFactorSecretResponse{Secret: secret, URL: url})
+}
+
+type secondFactorCodeInput struct {
+	Code string `json:"code"`
+}
+
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// @Summary confirm two-factor enrollment of the current user, returns recovery codes
+// @Tags auth
+// @Param input body secondFactorCodeInput true "TOTP code"
+// @Router /api/auth/2fa/enable [post]
+// @Security ApiKeyAuth
+func (h AuthHandler) EnableSecondFactor(ctx *gin.Context) {
+	inp := new(secondFactorCodeInput)
+	if err := ctx.BindJSON(inp); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+
+	c, ok := ctx.Get(model.CtxCredentialsKey)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, M("user does not exist"))
+		return
+	}
+
+	credentials, ok := c.(model.Credentials)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, M("wrong token"))
+		return
+	}
+
+	recoveryCodes, err := h.authService.EnableSecondFactor(ctx.Request.Context(), credentials.Username, inp.Code)
+	if err != nil {
+		if errors.Is(err, model.ErrWrongCode) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, E(err))
+			return
+		}
+
+		if errors.Is(err, model.ErrSecondFactorEnabled) || errors.Is(err, model.ErrSecondFactorNotEnrolled) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, E(err))
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, recoveryCodesResponse{RecoveryCodes: recoveryCodes})
+}
+
+// @Summary disable two-factor authentication of the current user
+// @Tags auth
+// @Param input body secondFactorCodeInput true "TOTP or recovery code"
+// @Router /api/auth/2fa/disable [post]
+// @Security ApiKeyAuth
+func (h AuthHandler) DisableSecondFactor(ctx *gin.Context) {
+	inp := new(secondFactorCodeInput)
+	if err := ctx.BindJSON(inp); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+
+	c, ok := ctx.Get(model.CtxCredentialsKey)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, M("user does not exist"))
+		return
+	}
+
+	credentials, ok := c.(model.Credentials)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, M("wrong token"))
+		return
+	}
+
+	if err := h.authService.DisableSecondFactor(ctx.Request.Context(), credentials.Username, inp.Code); err != nil {
+		if errors.Is(err, model.ErrWrongCode) || errors.Is(err, model.ErrSecondFactorRequired) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, E(err))
+			return
+		}
+
+		if errors.Is(err, model.ErrSecondFactorNotEnrolled) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, E(err))
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, M("ok"))
+}
+
+type refreshInput struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// @Summary refresh access token
+// @Tags auth
+// @Param input body refreshInput true "refresh token"
+// @Router /api/auth/refresh [post]
+func (h AuthHandler) Refresh(ctx *gin.Context) {
+	inp := new(refreshInput)
+	if err := ctx.BindJSON(inp); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+
+	tokens, err := h.authService.Refresh(ctx.Request.Context(), inp.RefreshToken)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidRefreshToken) || errors.Is(err, model.ErrUserNotFound) {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, E(err))
 			return
 		}
 
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, signInResponse{Token: tokens.AccessToken, RefreshToken: tokens.RefreshToken})
+}
+
+// @Summary log out, revokes access and refresh tokens of the current session
+// @Tags auth
+// @Router /api/auth/logout [post]
+// @Security ApiKeyAuth
+func (h AuthHandler) SignOut(ctx *gin.Context) {
+	c, ok := ctx.Get(model.CtxCredentialsKey)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, M("user does not exist"))
+		return
+	}
+
+	credentials, ok := c.(model.Credentials)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, M("wrong token"))
+		return
+	}
+
+	if err := h.authService.SignOut(ctx.Request.Context(), credentials.SessionID); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, M("ok"))
+}
+
+type changePasswordInput struct {
+	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password"`
+}
+
+// @Summary change password of the current user
+// @Tags auth
+// @Param input body changePasswordInput true "old and new password"
+// @Router /api/auth/password [post]
+// @Security ApiKeyAuth
+func (h AuthHandler) ChangePassword(ctx *gin.Context) {
+	inp := new(changePasswordInput)
+	if err := ctx.BindJSON(inp); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+
+	c, ok := ctx.Get(model.CtxCredentialsKey)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, M("user does not exist"))
+		return
+	}
+
+	credentials, ok := c.(model.Credentials)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, M("wrong token"))
+		return
+	}
+
+	err := h.authService.ChangePassword(ctx.Request.Context(), credentials.Username, inp.OldPassword, inp.NewPassword)
+	if err != nil {
+		if errors.Is(err, model.ErrWrongPassword) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, E(err))
+			return
+		}
+
+		if errors.Is(err, model.ErrEmptyPassword) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, M("ok"))
+}
+
+type issuePasswordResetInput struct {
+	Username string `json:"username"`
+}
+
+type issuePasswordResetResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// @Summary issue a single-use password reset token for an account of a lower role
+// @Tags auth
+// @Param input body issuePasswordResetInput true "username"
+// @Router /api/auth/password-reset [post]
+// @Security ApiKeyAuth
+func (h AuthHandler) IssuePasswordReset(ctx *gin.Context) {
+	inp := new(issuePasswordResetInput)
+	if err := ctx.BindJSON(inp); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+
+	c, ok := ctx.Get(model.CtxCredentialsKey)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, M("user does not exist"))
+		return
+	}
+
+	credentials, ok := c.(model.Credentials)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, M("wrong token"))
+		return
+	}
+
+	token, expiresAt, err := h.authService.IssuePasswordReset(ctx.Request.Context(), credentials, inp.Username)
+	if err != nil {
 		if errors.Is(err, model.ErrUserNotFound) {
 			ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, E(err))
 			return
 		}
 
+		if errors.Is(err, model.ErrWrongRole) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, E(err))
+			return
+		}
+
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, signInResponse{Token: token})
+	ctx.JSON(http.StatusOK, issuePasswordResetResponse{Token: token, ExpiresAt: expiresAt})
+}
+
+type resetPasswordInput struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
 }
 
-// auth Middleware
-func (m AuthHandler) WithAuth(role model.Role) gin.HandlerFunc {
+// @Summary set a new password with a password reset token
+// @Tags auth
+// @Param input body resetPasswordInput true "reset token and new password"
+// @Router /api/auth/password-reset/confirm [post]
+func (h AuthHandler) ResetPassword(ctx *gin.Context) {
+	inp := new(resetPasswordInput)
+	if err := ctx.BindJSON(inp); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+
+	if err := h.authService.ResetPassword(ctx.Request.Context(), inp.Token, inp.NewPassword); err != nil {
+		if errors.Is(err, model.ErrInvalidResetToken) {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, E(err))
+			return
+		}
+
+		if errors.Is(err, model.ErrEmptyPassword) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, M("ok"))
+}
+
+// auth Middleware, the role of the token must have all of the permissions.
+// Instead of a Bearer token an "ApiKey <key>" header with a key that has the
+// permissions is accepted too.
+func (m AuthHandler) WithAuth(permissions ...model.Permission) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		token := ctx.GetHeader("Authorization")
-		credentials, err := m.authService.CheckAccess(ctx.Request.Context(), token, role)
+
+		var credentials model.Credentials
+		var err error
+		if key := strings.TrimPrefix(token, "ApiKey "); key != token {
+			credentials, err = m.apiKeysService.CheckAccess(ctx.Request.Context(), key, permissions...)
+		} else {
+			credentials, err = m.authService.CheckAccess(ctx.Request.Context(), token, permissions...)
+		}
 		if err != nil {
 			if errors.Is(err, model.ErrInvalidAccessToken) {
 				ctx.AbortWithStatusJSON(http.StatusUnauthorized, E(err))
 				return
 			}
+			if errors.Is(err, model.ErrWrongRole) {
+				ctx.AbortWithStatusJSON(http.StatusForbidden, E(err))
+				return
+			}
 			ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
 			return
 		}
 
+		if credentials.APIKeyID != "" {
+			ctx.Set(model.CtxAPIKeyIDKey, credentials.APIKeyID)
+		}
+
 		ctx.Set(model.CtxCredentialsKey, credentials)
 	}
 }
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type apiKeysServiceStub struct{}
+
+func (apiKeysServiceStub) CheckAccess(_ context.Context, key string, _ ...model.Permission) (model.Credentials, error) {
+	if key != "secret" {
+		return model.Credentials{}, model.ErrInvalidAccessToken
+	}
+	return model.Credentials{CredentialsSecure: model.CredentialsSecure{Username: "kiosk"}, APIKeyID: "key-1"}, nil
+}
+
+// cardsServiceStub records the api key of card updates, other methods are
+// not implemented.
+type cardsServiceStub struct {
+	CardsService
+	apiKeyID string
+}
+
+func (s *cardsServiceStub) Update(_ context.Context, _ string, _ int, _ float32, _, apiKeyID string) (string, int, error) {
+	s.apiKeyID = apiKeyID
+	return "user", 10, nil
+}
+
+type cardsUserServiceStub struct{}
+
+func (cardsUserServiceStub) GetByUsername(_ context.Context, username string) (model.User, error) {
+	return model.User{CredentialsSecure: model.CredentialsSecure{Username: username}}, nil
+}
+
+func (cardsUserServiceStub) Update(context.Context, model.User) error {
+	return nil
+}
+
+func TestAuthHandler_WithAuthAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cardsService := new(cardsServiceStub)
+	authHandler := NewAuthHandler(nil, nil, nil, nil, apiKeysServiceStub{})
+	cardsHandler := NewCardsStaticHandler(cardsService, cardsUserServiceStub{})
+
+	router := gin.New()
+	router.POST("/api/cards/done", authHandler.WithAuth(model.PermCardsComplete), cardsHandler.UpdateCard)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/cards/done", strings.NewReader(`{"card_id": "1"}`))
+	req.Header.Set("Authorization", "ApiKey secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "key-1", cardsService.apiKeyID)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/cards/done", strings.NewReader(`{"card_id": "1"}`))
+	req.Header.Set("Authorization", "ApiKey wrong")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -1,8 +1,15 @@
 package handler
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -12,9 +19,15 @@ import (
 type CardsService interface {
 	GetStatic(ctx context.Context, ids []string) (model.CardsStatic, error)
 	CreateStatic(ctx context.Context, card model.CardStatic) error
-	DeleteStatic(ctx context.Context, ids []string) (err error)
-	Update(ctx context.Context, id string, progress int, doneOption float32) (string, int, error)
-	GetFormattedCards(ctx context.Context, ownerUsername string) (model.Cards, model.Cards, error)
+	UpdateStatic(ctx context.Context, card model.CardStatic) (model.CardStatic, error)
+	GetStaticVersions(ctx context.Context, id string) (model.CardsStatic, error)
+	ExportStatic(ctx context.Context, w io.Writer, format string) error
+	ImportStatic(ctx context.Context, r io.Reader, format, mode string, dryRun bool) (model.ImportReport, error)
+	ArchiveStatic(ctx context.Context, ids []string, cancelPending bool) error
+	UnarchiveStatic(ctx context.Context, ids []string) error
+	Update(ctx context.Context, id string, progress int, doneOption float32, stepID, apiKeyID string) (string, int, error)
+	GetFormattedCards(ctx context.Context, user model.User) ([]model.PoolCards, []model.LockedChain, error)
+	ReplayDailyCards(ctx context.Context, user model.User, date time.Time) (model.DailyReplay, error)
 	ViewCard(ctx context.Context, id string) error
 }
 
@@ -33,18 +46,29 @@ func NewCardsStaticHandler(cardsService CardsService, userService CardsUserServi
 }
 
 type createStaticCardInput struct {
-	Title            string             `json:"title"`
-	ShortDescription string             `json:"short_description"`
-	LongDescription  string             `json:"long_description"`
-	Goal             string             `json:"goal"`
-	Type             string             `json:"type"`
-	Pool             string             `json:"pool"`
-	ChainName        string             `json:"chain_name"`
-	BackgroundURL    string             `json:"background_url"`
-	ChainOrder       int                `json:"chain_order"`
-	OrdSettings      *model.OrdSettings `json:"ordinary_settings"`
-	PrgSettings      *model.PrgSettings `json:"progress_settings"`
-	OptSettings      *model.OptSettings `json:"options_settings"`
+	Title             string                   `json:"title"`
+	ShortDescription  string                   `json:"short_description"`
+	LongDescription   string                   `json:"long_description"`
+	Goal              string                   `json:"goal"`
+	Type              string                   `json:"type"`
+	Pool              string                   `json:"pool"`
+	ChainName         string                   `json:"chain_name"`
+	BackgroundURL     string                   `json:"background_url"`
+	ChainOrder        int                      `json:"chain_order"`
+	Season            string                   `json:"season"`
+	OrdSettings       *model.OrdSettings       `json:"ordinary_settings"`
+	PrgSettings       *model.PrgSettings       `json:"progress_settings"`
+	OptSettings       *model.OptSettings       `json:"options_settings"`
+	StreakSettings    *model.StreakSettings    `json:"streak_settings"`
+	ChecklistSettings *model.ChecklistSettings `json:"checklist_settings"`
+	Availability      *model.Availability      `json:"availability"`
+	Repeat            *model.RepeatSettings    `json:"repeat_settings"`
+	Weight            int                      `json:"weight"`
+}
+
+// newCreateStaticCardInput marks chain_order as not given, 0 is a valid order.
+func newCreateStaticCardInput() *createStaticCardInput {
+	return &createStaticCardInput{ChainOrder: -1}
 }
 
 // @Summary create static card
@@ -53,28 +77,40 @@ type createStaticCardInput struct {
 // @Router /api/cards [post]
 // @Security ApiKeyAuth
 func (h CardsHandler) CreateStatic(ctx *gin.Context) {
-	inp := new(createStaticCardInput)
+	inp := newCreateStaticCardInput()
 	if err := ctx.BindJSON(inp); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
 		return
 	}
 
 	card := model.CardStatic{
-		Title:            inp.Title,
-		ShortDescription: inp.ShortDescription,
-		LongDescription:  inp.LongDescription,
-		Goal:             inp.Goal,
-		Type:             inp.Type,
-		Pool:             inp.Pool,
-		ChainName:        inp.ChainName,
-		ChainOrder:       inp.ChainOrder,
-		BackgroundURL:    inp.BackgroundURL,
-		OrdSettings:      inp.OrdSettings,
-		PrgSettings:      inp.PrgSettings,
-		OptSettings:      inp.OptSettings,
+		Title:             inp.Title,
+		ShortDescription:  inp.ShortDescription,
+		LongDescription:   inp.LongDescription,
+		Goal:              inp.Goal,
+		Type:              inp.Type,
+		Pool:              inp.Pool,
+		ChainName:         inp.ChainName,
+		ChainOrder:        inp.ChainOrder,
+		Season:            inp.Season,
+		BackgroundURL:     inp.BackgroundURL,
+		OrdSettings:       inp.OrdSettings,
+		PrgSettings:       inp.PrgSettings,
+		OptSettings:       inp.OptSettings,
+		StreakSettings:    inp.StreakSettings,
+		ChecklistSettings: inp.ChecklistSettings,
+		Availability:      inp.Availability,
+		Repeat:            inp.Repeat,
+		Weight:            inp.Weight,
 	}
 
 	if err := h.cardsService.CreateStatic(ctx.Request.Context(), card); err != nil {
+		var verr *model.ValidationError
+		if errors.As(err, &verr) {
+			ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, V(verr))
+			return
+		}
+
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
 		return
 	}
@@ -82,23 +118,265 @@ func (h CardsHandler) CreateStatic(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, M("ok"))
 }
 
-type deleteCardStaticsInput struct {
-	IDs []string `json:"ids"`
+// @Summary edit static card, creates its next version
+// @Tags cards
+// @Param id path string true "static card id"
+// @Param input body createStaticCardInput true "new version of the static card"
+// @Router /api/cards/{id} [put]
+// @Security ApiKeyAuth
+func (h CardsHandler) UpdateStatic(ctx *gin.Context) {
+	id, err := ParsePath(ctx, "id")
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+
+	inp := newCreateStaticCardInput()
+	if err := ctx.BindJSON(inp); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+
+	card := model.CardStatic{
+		ID:                id,
+		Title:             inp.Title,
+		ShortDescription:  inp.ShortDescription,
+		LongDescription:   inp.LongDescription,
+		Goal:              inp.Goal,
+		Type:              inp.Type,
+		Pool:              inp.Pool,
+		ChainName:         inp.ChainName,
+		ChainOrder:        inp.ChainOrder,
+		Season:            inp.Season,
+		BackgroundURL:     inp.BackgroundURL,
+		OrdSettings:       inp.OrdSettings,
+		PrgSettings:       inp.PrgSettings,
+		OptSettings:       inp.OptSettings,
+		StreakSettings:    inp.StreakSettings,
+		ChecklistSettings: inp.ChecklistSettings,
+		Availability:      inp.Availability,
+		Repeat:            inp.Repeat,
+		Weight:            inp.Weight,
+	}
+
+	card, err = h.cardsService.UpdateStatic(ctx.Request.Context(), card)
+	if err != nil {
+		if errors.Is(err, model.ErrNoSuchCard) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, E(err))
+			return
+		}
+		if errors.Is(err, model.ErrCardChanged) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, E(err))
+			return
+		}
+
+		var verr *model.ValidationError
+		if errors.As(err, &verr) {
+			ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, V(verr))
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &card)
+}
+
+// @Summary get all versions of a static card, the oldest first
+// @Tags cards
+// @Param id path string true "static card id"
+// @Router /api/cards/versions/{id} [get]
+// @Security ApiKeyAuth
+func (h CardsHandler) GetStaticVersions(ctx *gin.Context) {
+	id, err := ParsePath(ctx, "id")
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+
+	cards, err := h.cardsService.GetStaticVersions(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, model.ErrNoSuchCard) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, E(err))
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, getStaticCardsResponse{Cards: cards})
+}
+
+// @Summary draw the daily cards of a user on a day again, along with the cards issued that day
+// @Tags cards
+// @Param username path string true "username"
+// @Param date query string false "day as 2006-01-02 in the timezone of the user, today if empty"
+// @Router /api/cards/replay/{username} [get]
+// @Security ApiKeyAuth
+func (h CardsHandler) ReplayDailyCards(ctx *gin.Context) {
+	username, err := ParsePath(ctx, "username")
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+
+	var day time.Time
+	if date := ctx.Query("date"); date != "" {
+		if day, err = time.Parse("2006-01-02", date); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, M("date must be formatted as 2006-01-02"))
+			return
+		}
+	}
+
+	user, err := h.userService.GetByUsername(ctx.Request.Context(), username)
+	if err != nil {
+		if errors.Is(err, model.ErrUserNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, E(err))
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	replay, err := h.cardsService.ReplayDailyCards(ctx.Request.Context(), user, day)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, replay)
+}
+
+// exportContentTypes are the content types of the export formats.
+var exportContentTypes = map[string]string{
+	model.FormatJSON: "application/json",
+	model.FormatCSV:  "text/csv",
+	model.FormatYAML: "application/yaml",
 }
 
-// @Summary delete cards
+// @Summary export every static card as a file
 // @Tags cards
-// @Param input body deleteCardStaticsInput true "delete static cards input"
+// @Param format query string false "json (default), csv or yaml"
+// @Router /api/cards/export [get]
+// @Security ApiKeyAuth
+func (h CardsHandler) ExportStatic(ctx *gin.Context) {
+	format := ctx.DefaultQuery("format", model.FormatJSON)
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(model.ErrUnknownFormat))
+		return
+	}
+
+	buf := new(bytes.Buffer)
+	if err := h.cardsService.ExportStatic(ctx.Request.Context(), buf, format); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=cards.%s", format))
+	ctx.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// @Summary import static cards from a file, nothing is imported if any card is invalid
+// @Tags cards
+// @Accept multipart/form-data
+// @Param file formData file true "json, csv or yaml file"
+// @Param format query string false "file format, by the file extension if empty"
+// @Param mode query string false "create (default) or upsert, cards are matched by external_id"
+// @Param dry_run query bool false "only validate and report"
+// @Router /api/cards/import [post]
+// @Security ApiKeyAuth
+func (h CardsHandler) ImportStatic(ctx *gin.Context) {
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+
+	format := ctx.Query("format")
+	if format == "" {
+		if format, err = model.FormatByName(file.Filename); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+			return
+		}
+	}
+	mode := ctx.DefaultQuery("mode", model.ImportCreate)
+	dryRun, _ := strconv.ParseBool(ctx.Query("dry_run"))
+
+	f, err := file.Open()
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+	defer f.Close()
+
+	report, err := h.cardsService.ImportStatic(ctx.Request.Context(), f, format, mode, dryRun)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidImport) {
+			ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, report)
+			return
+		}
+		if errors.Is(err, model.ErrUnknownFormat) || errors.Is(err, model.ErrUnknownImportMode) ||
+			errors.Is(err, model.ErrInvalidImportFile) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+			return
+		}
+		if errors.Is(err, model.ErrCardChanged) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, E(err))
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+type archiveCardStaticsInput struct {
+	IDs           []string `json:"ids"`
+	CancelPending bool     `json:"cancel_pending"`
+}
+
+// @Summary archive cards, they are not issued anymore but stay queryable
+// @Tags cards
+// @Param input body archiveCardStaticsInput true "ids, cancel_pending also removes issued cards which are not done"
 // @Router /api/cards [delete]
 // @Security ApiKeyAuth
-func (h CardsHandler) DeleteStatic(ctx *gin.Context) {
-	inp := new(deleteCardStaticsInput)
+func (h CardsHandler) ArchiveStatic(ctx *gin.Context) {
+	inp := new(archiveCardStaticsInput)
 	if err := ctx.BindJSON(inp); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
 		return
 	}
 
-	if err := h.cardsService.DeleteStatic(ctx.Request.Context(), inp.IDs); err != nil {
+	if err := h.cardsService.ArchiveStatic(ctx.Request.Context(), inp.IDs, inp.CancelPending); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, M("ok"))
+}
+
+type unarchiveCardStaticsInput struct {
+	IDs []string `json:"ids"`
+}
+
+// @Summary unarchive cards
+// @Tags cards
+// @Param input body unarchiveCardStaticsInput true "unarchive static cards input"
+// @Router /api/cards/unarchive [post]
+// @Security ApiKeyAuth
+func (h CardsHandler) UnarchiveStatic(ctx *gin.Context) {
+	inp := new(unarchiveCardStaticsInput)
+	if err := ctx.BindJSON(inp); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+
+	if err := h.cardsService.UnarchiveStatic(ctx.Request.Context(), inp.IDs); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
 		return
 	}
@@ -110,9 +388,10 @@ type updateCardInput struct {
 	ID         string  `json:"card_id"`
 	Progress   int     `json:"progress"`
 	DoneOption float32 `json:"done_option"`
+	StepID     string  `json:"step_id"`
 }
 
-// @Summary update card
+// @Summary update card, answers 409 for cards which can't be completed now, with Retry-After during a cooldown
 // @Tags cards
 // @Param input body updateCardInput true "update card input"
 // @Router /api/cards/done [post]
@@ -124,8 +403,28 @@ func (h CardsHandler) UpdateCard(ctx *gin.Context) {
 		return
 	}
 
-	username, xpoints, err := h.cardsService.Update(ctx.Request.Context(), inp.ID, inp.Progress, inp.DoneOption)
+	username, xpoints, err := h.cardsService.Update(ctx.Request.Context(), inp.ID, inp.Progress, inp.DoneOption, inp.StepID, ctx.GetString(model.CtxAPIKeyIDKey))
 	if err != nil {
+		if errors.Is(err, model.ErrNoSuchStep) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+			return
+		}
+		var limitErr *model.CompletionLimitError
+		if errors.As(err, &limitErr) {
+			if !limitErr.RetryAt.IsZero() {
+				retryAfter := time.Until(limitErr.RetryAt)
+				ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			}
+			ctx.AbortWithStatusJSON(http.StatusConflict, E(err))
+			return
+		}
+		if errors.Is(err, model.ErrStreakDoneToday) || errors.Is(err, model.ErrStepChecked) ||
+			errors.Is(err, model.ErrCardNotAvailable) || errors.Is(err, model.ErrSeasonEnded) ||
+			errors.Is(err, model.ErrChainLocked) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, E(err))
+			return
+		}
+
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
 		return
 	}
@@ -145,9 +444,13 @@ func (h CardsHandler) UpdateCard(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, M("ok"))
 }
 
+// getUserCardsResponse lists the cards of all pools in pending_cards and
+// done_cards, pools has them grouped by pool with the expired season cards.
 type getUserCardsResponse struct {
-	PendingCards []model.Card `json:"pending_cards"`
-	DoneCards    []model.Card `json:"done_cards"`
+	PendingCards []model.Card        `json:"pending_cards"`
+	DoneCards    []model.Card        `json:"done_cards"`
+	Pools        []model.PoolCards   `json:"pools"`
+	LockedChains []model.LockedChain `json:"locked_chains"`
 }
 
 // @Summary get all user cards by username
@@ -159,13 +462,9 @@ func (h CardsHandler) GetUserCards(ctx *gin.Context) {
 	username, err := ParsePath(ctx, "username")
 	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
-	}
-	pending, done, err := h.cardsService.GetFormattedCards(ctx.Request.Context(), username)
-	if err != nil {
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
 		return
 	}
-	ctx.JSON(http.StatusOK, getUserCardsResponse{PendingCards: pending, DoneCards: done})
+	h.userCards(ctx, username, false)
 }
 
 // @Summary get all user cards by token
@@ -185,12 +484,38 @@ func (h CardsHandler) GetProfileCards(ctx *gin.Context) {
 		return
 	}
 
-	pending, done, err := h.cardsService.GetFormattedCards(ctx.Request.Context(), credentials.Username)
+	h.userCards(ctx, credentials.Username, true)
+}
+
+// userCards answers with the cards of the user, locked chains are listed with
+// the requirements left. Staff asking for their own profile have no user
+// record, they are treated as a new user in the venue timezone.
+func (h CardsHandler) userCards(ctx *gin.Context, username string, profile bool) {
+	user, err := h.userService.GetByUsername(ctx.Request.Context(), username)
+	switch {
+	case err == nil:
+	case errors.Is(err, model.ErrUserNotFound) && profile:
+		user = model.User{CredentialsSecure: model.CredentialsSecure{Username: username}}
+	case errors.Is(err, model.ErrUserNotFound):
+		ctx.AbortWithStatusJSON(http.StatusNotFound, E(err))
+		return
+	default:
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	pools, locked, err := h.cardsService.GetFormattedCards(ctx.Request.Context(), user)
 	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
 		return
 	}
-	ctx.JSON(http.StatusOK, getUserCardsResponse{PendingCards: pending, DoneCards: done})
+
+	resp := getUserCardsResponse{PendingCards: []model.Card{}, DoneCards: []model.Card{}, Pools: pools, LockedChains: locked}
+	for _, p := range pools {
+		resp.PendingCards = append(resp.PendingCards, p.Pending...)
+		resp.DoneCards = append(resp.DoneCards, p.Done...)
+	}
+	ctx.JSON(http.StatusOK, resp)
 }
 
 type getStaticCardsResponse struct {
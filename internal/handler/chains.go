@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type ChainsService interface {
+	GetAll(ctx context.Context) (model.Chains, error)
+	Set(ctx context.Context, chain model.Chain) (model.Chain, error)
+	Delete(ctx context.Context, name string) error
+}
+
+type ChainsHandler struct {
+	chainsService ChainsService
+}
+
+func NewChainsHandler(chainsService ChainsService) *ChainsHandler {
+	return &ChainsHandler{chainsService: chainsService}
+}
+
+// @Summary get unlock requirements of all const chains
+// @Tags chains
+// @Router /api/chains [get]
+// @Security ApiKeyAuth
+func (h ChainsHandler) GetAll(ctx *gin.Context) {
+	chains, err := h.chainsService.GetAll(ctx.Request.Context())
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, chains)
+}
+
+type setChainInput struct {
+	Requires []string `json:"requires"`
+	MinLevel int      `json:"min_level"`
+}
+
+// @Summary set unlock requirements of a const chain, answers 422 for unknown chains and cycles
+// @Tags chains
+// @Param name path string true "chain name"
+// @Param input body setChainInput true "chains to finish first and minimum user level"
+// @Router /api/chains/{name} [put]
+// @Security ApiKeyAuth
+func (h ChainsHandler) Set(ctx *gin.Context) {
+	name, err := ParsePath(ctx, "name")
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+
+	inp := new(setChainInput)
+	if err := ctx.BindJSON(inp); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+
+	chain, err := h.chainsService.Set(ctx.Request.Context(), model.Chain{Name: name, Requires: inp.Requires, MinLevel: inp.MinLevel})
+	if err != nil {
+		var verr *model.ValidationError
+		if errors.As(err, &verr) {
+			ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, V(verr))
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, chain)
+}
+
+// @Summary delete unlock requirements of a const chain
+// @Tags chains
+// @Param name path string true "chain name"
+// @Router /api/chains/{name} [delete]
+// @Security ApiKeyAuth
+func (h ChainsHandler) Delete(ctx *gin.Context) {
+	name, err := ParsePath(ctx, "name")
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+
+	if err := h.chainsService.Delete(ctx.Request.Context(), name); err != nil {
+		if errors.Is(err, model.ErrChainNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, E(err))
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, M("ok"))
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
 )
 
 type Msg struct {
@@ -14,12 +16,24 @@ type Err struct {
 	Err string `json:"error"`
 }
 
+type ValidationErr struct {
+	Err        string            `json:"error"`
+	Violations []model.Violation `json:"violations"`
+}
+
 func E(err error) *Err {
 	return &Err{
 		Err: err.Error(),
 	}
 }
 
+func V(err *model.ValidationError) *ValidationErr {
+	return &ValidationErr{
+		Err:        model.ErrInvalidCard.Error(),
+		Violations: err.Violations,
+	}
+}
+
 func M(msg string) *Msg {
 	return &Msg{
 		Msg: msg,
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type GoalsService interface {
+	Create(ctx context.Context, goal model.Goal) (model.Goal, error)
+	GetAll(ctx context.Context) (model.Goals, error)
+	UpdateTitle(ctx context.Context, name, title string) error
+	Delete(ctx context.Context, name string) error
+}
+
+type GoalsHandler struct {
+	goalsService GoalsService
+}
+
+func NewGoalsHandler(goalsService GoalsService) *GoalsHandler {
+	return &GoalsHandler{goalsService: goalsService}
+}
+
+type createGoalInput struct {
+	Name  string `json:"name"`
+	Title string `json:"title"`
+}
+
+// @Summary create goal
+// @Tags goals
+// @Param input body createGoalInput true "name cards refer to and title"
+// @Router /api/goals [post]
+// @Security ApiKeyAuth
+func (h GoalsHandler) Create(ctx *gin.Context) {
+	inp := new(createGoalInput)
+	if err := ctx.BindJSON(inp); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+
+	goal, err := h.goalsService.Create(ctx.Request.Context(), model.Goal{Name: inp.Name, Title: inp.Title})
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidGoalName) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+			return
+		}
+		if errors.Is(err, model.ErrGoalExists) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, E(err))
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, goal)
+}
+
+// @Summary get all goals
+// @Tags goals
+// @Router /api/goals [get]
+// @Security ApiKeyAuth
+func (h GoalsHandler) GetAll(ctx *gin.Context) {
+	goals, err := h.goalsService.GetAll(ctx.Request.Context())
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, goals)
+}
+
+type updateGoalInput struct {
+	Title string `json:"title"`
+}
+
+// @Summary change goal title, the name stays
+// @Tags goals
+// @Param name path string true "goal name"
+// @Param input body updateGoalInput true "new title"
+// @Router /api/goals/{name} [put]
+// @Security ApiKeyAuth
+func (h GoalsHandler) Update(ctx *gin.Context) {
+	name, err := ParsePath(ctx, "name")
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+
+	inp := new(updateGoalInput)
+	if err := ctx.BindJSON(inp); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+
+	if err := h.goalsService.UpdateTitle(ctx.Request.Context(), name, inp.Title); err != nil {
+		if errors.Is(err, model.ErrGoalNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, E(err))
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, M("ok"))
+}
+
+// @Summary delete goal no card refers to
+// @Tags goals
+// @Param name path string true "goal name"
+// @Router /api/goals/{name} [delete]
+// @Security ApiKeyAuth
+func (h GoalsHandler) Delete(ctx *gin.Context) {
+	name, err := ParsePath(ctx, "name")
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+
+	if err := h.goalsService.Delete(ctx.Request.Context(), name); err != nil {
+		if errors.Is(err, model.ErrGoalNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, E(err))
+			return
+		}
+		if errors.Is(err, model.ErrGoalInUse) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, E(err))
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, M("ok"))
+}
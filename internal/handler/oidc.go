@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+// oidcCookie keeps state, nonce and code verifier between the redirect to the
+// identity provider and the callback.
+const (
+	oidcCookie       = "oidc_login"
+	oidcCookiePath   = "/api/auth/oidc"
+	oidcCookieMaxAge = 10 * 60
+)
+
+type OIDCService interface {
+	Login(ctx context.Context) (model.OIDCLogin, error)
+	Exchange(ctx context.Context, code string, login model.OIDCLogin) (model.Identity, error)
+}
+
+type OIDCAuthService interface {
+	ProvisionExternal(ctx context.Context, identity model.Identity) (model.Credentials, bool, error)
+	SignInExternal(ctx context.Context, credentials model.Credentials) (model.Tokens, error)
+}
+
+type OIDCAdminService interface {
+	Save(ctx context.Context, admin model.Admin) error
+}
+
+type OIDCHandler struct {
+	oidcService  OIDCService
+	authService  OIDCAuthService
+	adminService OIDCAdminService
+}
+
+func NewOIDCHandler(oidcService OIDCService, authService OIDCAuthService, adminService OIDCAdminService) *OIDCHandler {
+	return &OIDCHandler{
+		oidcService:  oidcService,
+		authService:  authService,
+		adminService: adminService,
+	}
+}
+
+// @Summary staff sign in with the identity provider, redirects to it
+// @Tags auth
+// @Router /api/auth/oidc/login [get]
+func (h OIDCHandler) Login(ctx *gin.Context) {
+	login, err := h.oidcService.Login(ctx.Request.Context())
+	if err != nil {
+		if errors.Is(err, model.ErrOIDCDisabled) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, E(err))
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusBadGateway, E(err))
+		return
+	}
+
+	value := strings.Join([]string{login.State, login.Nonce, login.Verifier}, ".")
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(oidcCookie, value, oidcCookieMaxAge, oidcCookiePath, "", ctx.Request.TLS != nil, true)
+	ctx.Redirect(http.StatusFound, login.URL)
+}
+
+// @Summary callback of the identity provider, returns access and refresh tokens
+// @Tags auth
+// @Param code query string true "authorization code"
+// @Param state query string true "state"
+// @Router /api/auth/oidc/callback [get]
+func (h OIDCHandler) Callback(ctx *gin.Context) {
+	if e := ctx.Query("error"); e != "" {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, M(e+": "+ctx.Query("error_description")))
+		return
+	}
+
+	cookie, err := ctx.Cookie(oidcCookie)
+	ctx.SetCookie(oidcCookie, "", -1, oidcCookiePath, "", ctx.Request.TLS != nil, true)
+	parts := strings.Split(cookie, ".")
+	if err != nil || len(parts) != 3 || parts[0] != ctx.Query("state") {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, E(model.ErrInvalidOIDCState))
+		return
+	}
+	login := model.OIDCLogin{State: parts[0], Nonce: parts[1], Verifier: parts[2]}
+
+	identity, err := h.oidcService.Exchange(ctx.Request.Context(), ctx.Query("code"), login)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidIDToken) || errors.Is(err, model.ErrWrongRole) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, E(err))
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusBadGateway, E(err))
+		return
+	}
+
+	credentials, _, err := h.authService.ProvisionExternal(ctx.Request.Context(), identity)
+	if err != nil {
+		if errors.Is(err, model.ErrUserExists) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, E(err))
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	// the admin record follows the role given by the provider on every sign in
+	admin := model.Admin{CredentialsSecure: credentials.CredentialsSecure}
+	if err := h.adminService.Save(ctx.Request.Context(), admin); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	tokens, err := h.authService.SignInExternal(ctx.Request.Context(), credentials)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, signInResponse{Token: tokens.AccessToken, RefreshToken: tokens.RefreshToken})
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type SeasonsService interface {
+	GetAll(ctx context.Context) (model.Seasons, error)
+	Set(ctx context.Context, season model.Season) (model.Season, error)
+	Delete(ctx context.Context, name string) error
+}
+
+type SeasonsHandler struct {
+	seasonsService SeasonsService
+}
+
+func NewSeasonsHandler(seasonsService SeasonsService) *SeasonsHandler {
+	return &SeasonsHandler{seasonsService: seasonsService}
+}
+
+// @Summary get all seasons
+// @Tags seasons
+// @Router /api/seasons [get]
+// @Security ApiKeyAuth
+func (h SeasonsHandler) GetAll(ctx *gin.Context) {
+	seasons, err := h.seasonsService.GetAll(ctx.Request.Context())
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, seasons)
+}
+
+type setSeasonInput struct {
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+// @Summary create a season or change its dates, answers 422 for invalid dates
+// @Tags seasons
+// @Param name path string true "season name"
+// @Param input body setSeasonInput true "when the season starts and ends, RFC 3339"
+// @Router /api/seasons/{name} [put]
+// @Security ApiKeyAuth
+func (h SeasonsHandler) Set(ctx *gin.Context) {
+	name, err := ParsePath(ctx, "name")
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+
+	inp := new(setSeasonInput)
+	if err := ctx.BindJSON(inp); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+
+	season, err := h.seasonsService.Set(ctx.Request.Context(), model.Season{Name: name, StartsAt: inp.StartsAt, EndsAt: inp.EndsAt})
+	if err != nil {
+		var verr *model.ValidationError
+		if errors.As(err, &verr) {
+			ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, V(verr))
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, season)
+}
+
+// @Summary delete a season, answers 409 while cards belong to it
+// @Tags seasons
+// @Param name path string true "season name"
+// @Router /api/seasons/{name} [delete]
+// @Security ApiKeyAuth
+func (h SeasonsHandler) Delete(ctx *gin.Context) {
+	name, err := ParsePath(ctx, "name")
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+
+	if err := h.seasonsService.Delete(ctx.Request.Context(), name); err != nil {
+		if errors.Is(err, model.ErrSeasonNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, E(err))
+			return
+		}
+		if errors.Is(err, model.ErrSeasonInUse) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, E(err))
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, M("ok"))
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -12,9 +13,10 @@ import (
 )
 
 type UserService interface {
-	Create(ctx context.Context, id, username, avatarURL, nickname string, role model.Role) error
+	Create(ctx context.Context, id, username, avatarURL, nickname, timezone string, role model.Role) error
 	GetByUsername(ctx context.Context, username string) (model.User, error)
 	Prizes(ctx context.Context, username string) ([]model.UserPrize, error)
+	SetTimezone(ctx context.Context, username, timezone string) error
 }
 
 type UserHandler struct {
@@ -137,3 +139,49 @@ func (h UserHandler) Profile(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, resp)
 }
+
+type setTimezoneInput struct {
+	Timezone string `json:"timezone"`
+}
+
+// @Summary set timezone of the current user, cards days start at the reset hour in it, empty timezone stands for the venue one
+// @Tags users
+// @Param input body setTimezoneInput true "IANA timezone"
+// @Router /api/users/profile/timezone [put]
+// @Security ApiKeyAuth
+func (h UserHandler) SetTimezone(ctx *gin.Context) {
+	inp := new(setTimezoneInput)
+	if err := ctx.BindJSON(inp); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+		return
+	}
+
+	c, ok := ctx.Get(model.CtxCredentialsKey)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, M("user does not exist"))
+		return
+	}
+
+	credentials, ok := c.(model.Credentials)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, M("wrong token"))
+		return
+	}
+
+	if err := h.userService.SetTimezone(ctx.Request.Context(), credentials.Username, inp.Timezone); err != nil {
+		if errors.Is(err, model.ErrUnknownTimezone) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, E(err))
+			return
+		}
+
+		if errors.Is(err, model.ErrUserNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, E(err))
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, E(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, M("ok"))
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// APIKey is a long-lived credential of a point-of-sale terminal or an
+// integration. It has its own permissions instead of a role. Only the hash of
+// the key is stored, Prefix is kept to tell keys apart in the list.
+type APIKey struct {
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	Prefix      string       `json:"prefix"`
+	Key         string       `json:"-"`
+	Permissions []Permission `json:"permissions"`
+	CreatedBy   string       `json:"created_by"`
+	CreatedAt   time.Time    `json:"created_at"`
+	LastUsedAt  time.Time    `json:"last_used_at"`
+	Revoked     bool         `json:"revoked"`
+}
+
+func (k APIKey) Can(permission Permission) bool {
+	for _, p := range k.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,9 @@
 package model
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+)
 
 const (
 	StatusPending string = "pending"
@@ -16,10 +19,101 @@ type Card struct {
 	IsViewed      bool       `json:"is_viewed"`
 	History       []int      `json:"history"`
 	OptDoneNum    int        `json:"opt_done_num"`
+	Streak        int        `json:"streak"`
+	LastDoneAt    time.Time  `json:"last_done_at"`
+	CheckedSteps  []string   `json:"checked_steps"`
+	CompletedAt   time.Time  `json:"completed_at"`
+	Seed          int64      `json:"seed,omitempty"`
+	// APIKeyID is the api key the card was last updated with, empty for
+	// updates made with a token.
+	APIKeyID string `json:"api_key_id,omitempty"`
+}
+
+// StepChecked reports whether the step of a checklist card is checked.
+func (c Card) StepChecked(id string) bool {
+	for _, s := range c.CheckedSteps {
+		if s == id {
+			return true
+		}
+	}
+	return false
+}
+
+// StreakDays returns the number of cards days from the day of last to the day
+// of now, days start at resetHour in loc.
+func StreakDays(last, now time.Time, loc *time.Location, resetHour int) int {
+	from := DailyStart(last, loc, resetHour)
+	to := DailyStart(now, loc, resetHour)
+	return int(to.Sub(from).Hours()+12) / 24
+}
+
+// CurrentStreak returns the streak of a streak card at now, it is lost once
+// more days than allowed are missed. Days start at resetHour in loc.
+func (c Card) CurrentStreak(now time.Time, loc *time.Location, resetHour int) int {
+	if c.Static.Type != TypeStreak || c.Static.StreakSettings == nil || c.Streak == 0 {
+		return 0
+	}
+
+	if StreakDays(c.LastDoneAt, now, loc, resetHour) > 1+c.Static.StreakSettings.GraceDays {
+		return 0
+	}
+	return c.Streak
 }
 
 type Cards []Card
 
+// DailyIssue records a card of a rotating pool issued to a user, the cards
+// themselves are deleted once replaced by the next ones. Seed is the seed the
+// card was drawn with.
+type DailyIssue struct {
+	Username string    `json:"username"`
+	Pool     string    `json:"pool"`
+	StaticID string    `json:"static_id"`
+	Goal     string    `json:"goal"`
+	IssuedAt time.Time `json:"issued_at"`
+	Seed     int64     `json:"seed"`
+}
+
+// DailyStart returns the start of the daily cards day t falls in, days start
+// at resetHour in loc. A day is named by the date of its start.
+func DailyStart(t time.Time, loc *time.Location, resetHour int) time.Time {
+	t = t.In(loc)
+	start := time.Date(t.Year(), t.Month(), t.Day(), resetHour, 0, 0, 0, loc)
+	if t.Before(start) {
+		start = start.AddDate(0, 0, -1)
+	}
+	return start
+}
+
+// WeeklyStart returns the start of the week t falls in, weeks start on weekday
+// at resetHour in loc.
+func WeeklyStart(t time.Time, loc *time.Location, weekday time.Weekday, resetHour int) time.Time {
+	start := DailyStart(t, loc, resetHour)
+	return start.AddDate(0, 0, -(int(start.Weekday())-int(weekday)+7)%7)
+}
+
+// PoolCards are the cards of a user in a pool, season pools come one per
+// season. Expired are the pending cards of an ended season, their progress is
+// frozen.
+type PoolCards struct {
+	Pool    string `json:"pool"`
+	Season  string `json:"season,omitempty"`
+	Pending Cards  `json:"pending_cards"`
+	Done    Cards  `json:"done_cards"`
+	Expired Cards  `json:"expired_cards,omitempty"`
+}
+
+// DailyReplay is the daily draw of a user on Day done again. Issued are the
+// cards actually issued that day, Cards differ from them only if the daily
+// cards or the history of the user changed since.
+type DailyReplay struct {
+	Username string       `json:"username"`
+	Day      time.Time    `json:"day"`
+	Seed     int64        `json:"seed"`
+	Cards    []CardStatic `json:"cards"`
+	Issued   []DailyIssue `json:"issued"`
+}
+
 func (cards Cards) ContainsStatic(card CardStatic) bool {
 	for _, c := range cards {
 		if c.Static.ID == card.ID {
@@ -37,6 +131,24 @@ func (c *Card) MarshalJSON() ([]byte, error) {
 		progress = &c.Progress
 	}
 
+	var streak *int
+	var lastDoneAt *time.Time
+	if c.Static.Type == TypeStreak {
+		streak = &c.Streak
+		if !c.LastDoneAt.IsZero() {
+			lastDoneAt = &c.LastDoneAt
+		}
+	}
+
+	var checkedSteps *[]string
+	if c.Static.Type == TypeChecklist {
+		steps := c.CheckedSteps
+		if steps == nil {
+			steps = []string{}
+		}
+		checkedSteps = &steps
+	}
+
 	var history []int
 	if c.Static.Type == TypeOptions {
 		history = c.History
@@ -46,12 +158,18 @@ func (c *Card) MarshalJSON() ([]byte, error) {
 	}
 
 	return json.Marshal(&struct {
-		Progress *int  `json:"progress,omitempty"`
-		History  []int `json:"history"`
+		Progress     *int       `json:"progress,omitempty"`
+		History      []int      `json:"history"`
+		Streak       *int       `json:"streak,omitempty"`
+		LastDoneAt   *time.Time `json:"last_done_at,omitempty"`
+		CheckedSteps *[]string  `json:"checked_steps,omitempty"`
 		*Alias
 	}{
-		Progress: progress,
-		History:  history,
-		Alias:    (*Alias)(c),
+		Progress:     progress,
+		History:      history,
+		Streak:       streak,
+		LastDoneAt:   lastDoneAt,
+		CheckedSteps: checkedSteps,
+		Alias:        (*Alias)(c),
 	})
 }
@@ -0,0 +1,105 @@
+package model
+
+import "time"
+
+// Availability limits when a static card is live. Zero StartsAt and EndsAt
+// don't limit the period, empty Weekdays mean every day and equal FromHour and
+// ToHour mean the whole day. FromHour greater than ToHour wraps around
+// midnight, so 22 to 2 is live from 22:00 till 01:59.
+type Availability struct {
+	StartsAt time.Time      `json:"starts_at" yaml:"starts_at,omitempty"`
+	EndsAt   time.Time      `json:"ends_at" yaml:"ends_at,omitempty"`
+	Weekdays []time.Weekday `json:"weekdays" yaml:"weekdays,omitempty" swaggertype:"array,integer"`
+	FromHour int            `json:"from_hour" yaml:"from_hour,omitempty"`
+	ToHour   int            `json:"to_hour" yaml:"to_hour,omitempty"`
+}
+
+// InPeriod reports whether t is between StartsAt and EndsAt.
+func (a Availability) InPeriod(t time.Time) bool {
+	return (a.StartsAt.IsZero() || !t.Before(a.StartsAt)) && (a.EndsAt.IsZero() || t.Before(a.EndsAt))
+}
+
+// At reports whether the card is live at t. Hours are read in the location
+// of t, the weekday is the one of the cards day t falls in, days start at
+// resetHour.
+func (a Availability) At(t time.Time, resetHour int) bool {
+	day := DailyStart(t, t.Location(), resetHour)
+	return a.InPeriod(t) && a.onWeekday(day.Weekday()) && a.inHours(t.Hour())
+}
+
+// On reports whether the card is live at some moment of the cards day
+// starting at start.
+func (a Availability) On(start time.Time) bool {
+	end := start.AddDate(0, 0, 1)
+
+	if !a.StartsAt.IsZero() && !a.StartsAt.Before(end) {
+		return false
+	}
+	if !a.EndsAt.IsZero() && !a.EndsAt.After(start) {
+		return false
+	}
+	return a.onWeekday(start.Weekday())
+}
+
+func (a Availability) onWeekday(d time.Weekday) bool {
+	if len(a.Weekdays) == 0 {
+		return true
+	}
+	for _, w := range a.Weekdays {
+		if w == d {
+			return true
+		}
+	}
+	return false
+}
+
+func (a Availability) inHours(h int) bool {
+	switch {
+	case a.FromHour == a.ToHour:
+		return true
+	case a.FromHour < a.ToHour:
+		return h >= a.FromHour && h < a.ToHour
+	default:
+		return h >= a.FromHour || h < a.ToHour
+	}
+}
+
+// AvailableAt reports whether the card is live at t in loc, where cards days
+// start at resetHour. Cards without availability always are.
+func (card CardStatic) AvailableAt(t time.Time, loc *time.Location, resetHour int) bool {
+	return card.Availability == nil || card.Availability.At(t.In(loc), resetHour)
+}
+
+// AvailableOn reports whether the card is live at some moment of the cards
+// day starting at start.
+func (card CardStatic) AvailableOn(start time.Time) bool {
+	return card.Availability == nil || card.Availability.On(start)
+}
+
+// InPeriod reports whether t is in the availability period of the card.
+func (card CardStatic) InPeriod(t time.Time) bool {
+	return card.Availability == nil || card.Availability.InPeriod(t)
+}
+
+// AvailableOn returns the cards live at some moment of the cards day starting
+// at start.
+func (cards CardsStatic) AvailableOn(start time.Time) CardsStatic {
+	available := make(CardsStatic, 0, len(cards))
+	for _, c := range cards {
+		if c.AvailableOn(start) {
+			available = append(available, c)
+		}
+	}
+	return available
+}
+
+// InPeriod returns the cards whose availability period includes t.
+func (cards CardsStatic) InPeriod(t time.Time) CardsStatic {
+	available := make(CardsStatic, 0, len(cards))
+	for _, c := range cards {
+		if c.InPeriod(t) {
+			available = append(available, c)
+		}
+	}
+	return available
+}
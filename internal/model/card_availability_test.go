@@ -0,0 +1,44 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAvailability(t *testing.T) {
+	// 2026-10-23 is a friday
+	friday := func(hour int) time.Time { return time.Date(2026, 10, 23, hour, 30, 0, 0, time.UTC) }
+
+	happyHour := Availability{
+		Weekdays: []time.Weekday{time.Friday},
+		FromHour: 18,
+		ToHour:   2,
+	}
+	assert.True(t, happyHour.At(friday(19), 0))
+	assert.True(t, happyHour.At(friday(23), 0))
+	assert.False(t, happyHour.At(friday(17), 0))
+	assert.False(t, happyHour.At(friday(19).AddDate(0, 0, 1), 0))
+	// after midnight it is still friday till the cards day ends
+	assert.False(t, happyHour.At(friday(1).AddDate(0, 0, 1), 0))
+	assert.True(t, happyHour.At(friday(1).AddDate(0, 0, 1), 6))
+	assert.False(t, happyHour.At(friday(3).AddDate(0, 0, 1), 6))
+	assert.True(t, happyHour.On(friday(9)))
+	assert.False(t, happyHour.On(friday(9).AddDate(0, 0, 1)))
+
+	tournament := Availability{
+		StartsAt: time.Date(2026, 10, 21, 12, 0, 0, 0, time.UTC),
+		EndsAt:   time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC),
+	}
+	assert.False(t, tournament.At(time.Date(2026, 10, 21, 11, 0, 0, 0, time.UTC), 0))
+	assert.True(t, tournament.On(time.Date(2026, 10, 21, 11, 0, 0, 0, time.UTC)))
+	assert.True(t, tournament.At(friday(12), 0))
+	assert.False(t, tournament.On(time.Date(2026, 10, 25, 10, 0, 0, 0, time.UTC)))
+	assert.False(t, tournament.InPeriod(time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC)))
+
+	cards := CardsStatic{{ID: "1"}, {ID: "2", Availability: &happyHour}, {ID: "3", Availability: &tournament}}
+	assert.Len(t, cards.AvailableOn(friday(9)), 3)
+	assert.Len(t, cards.AvailableOn(friday(9).AddDate(0, 0, 1)), 2)
+	assert.Len(t, cards.InPeriod(friday(9).AddDate(0, 0, 7)), 2)
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Formats of static card import and export files.
+const (
+	FormatJSON string = "json"
+	FormatCSV  string = "csv"
+	FormatYAML string = "yaml"
+)
+
+const (
+	ImportCreate string = "create"
+	ImportUpsert string = "upsert"
+
+	ImportCreated string = "created"
+	ImportUpdated string = "updated"
+)
+
+// ImportRow is the outcome of one card of an import file, rows are counted
+// from 1 in the order of the file.
+type ImportRow struct {
+	Row        int         `json:"row"`
+	ExternalID string      `json:"external_id"`
+	Action     string      `json:"action,omitempty"`
+	Violations []Violation `json:"violations,omitempty"`
+}
+
+// ImportReport describes an import. Nothing is written if any row is invalid
+// or on a dry run.
+type ImportReport struct {
+	DryRun  bool        `json:"dry_run"`
+	Created int         `json:"created"`
+	Updated int         `json:"updated"`
+	Invalid int         `json:"invalid"`
+	Rows    []ImportRow `json:"rows"`
+}
+
+// FormatByName returns the format of a file by its extension.
+func FormatByName(name string) (string, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		return FormatJSON, nil
+	case ".csv":
+		return FormatCSV, nil
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	}
+	return "", ErrUnknownFormat
+}
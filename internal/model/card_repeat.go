@@ -0,0 +1,68 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// RepeatSettings limit how often a user completes a card. Zero MaxCompletions
+// doesn't cap the completions, zero Cooldown allows them back to back.
+type RepeatSettings struct {
+	MaxCompletions int      `json:"max_completions" yaml:"max_completions,omitempty"`
+	Cooldown       Duration `json:"cooldown" yaml:"cooldown,omitempty" swaggertype:"string" example:"4h"`
+}
+
+// Duration is a time.Duration encoded as a string such as "15m" or "4h".
+type Duration struct {
+	time.Duration
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Duration) UnmarshalText(b []byte) error {
+	duration, err := time.ParseDuration(string(b))
+	if err != nil {
+		return fmt.Errorf("error decoding duration: %w", err)
+	}
+
+	d.Duration = duration
+	return nil
+}
+
+// CompletionLimitError is returned for cards which can't be completed now.
+// RetryAt is zero once MaxCompletions is reached, otherwise it is the end of
+// the cooldown.
+type CompletionLimitError struct {
+	MaxCompletions int
+	RetryAt        time.Time
+}
+
+func (e *CompletionLimitError) Error() string {
+	if e.RetryAt.IsZero() {
+		return fmt.Sprintf("%s: card can be completed at most %d times", ErrCompletionLimit, e.MaxCompletions)
+	}
+	return fmt.Sprintf("%s: card can be completed again at %s", ErrCompletionLimit, e.RetryAt.Format(time.RFC3339))
+}
+
+func (e *CompletionLimitError) Unwrap() error {
+	return ErrCompletionLimit
+}
+
+// CanComplete checks the repeat settings of the card at now, it returns a
+// *CompletionLimitError if the card can't be completed.
+func (c Card) CanComplete(now time.Time) error {
+	s := c.Static.Repeat
+	if s == nil {
+		return nil
+	}
+
+	if s.MaxCompletions > 0 && c.Done >= s.MaxCompletions {
+		return &CompletionLimitError{MaxCompletions: s.MaxCompletions}
+	}
+	if retryAt := c.CompletedAt.Add(s.Cooldown.Duration); !c.CompletedAt.IsZero() && now.Before(retryAt) {
+		return &CompletionLimitError{MaxCompletions: s.MaxCompletions, RetryAt: retryAt}
+	}
+	return nil
+}
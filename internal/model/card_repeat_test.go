@@ -0,0 +1,44 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCard_CanComplete(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	card := Card{
+		Static: CardStatic{Repeat: &RepeatSettings{MaxCompletions: 2, Cooldown: Duration{4 * time.Hour}}},
+	}
+	assert.NoError(t, card.CanComplete(now))
+
+	card.Done, card.CompletedAt = 1, now.Add(-time.Hour)
+	var limitErr *CompletionLimitError
+	require.True(t, errors.As(card.CanComplete(now), &limitErr))
+	assert.Equal(t, now.Add(3*time.Hour), limitErr.RetryAt)
+	assert.ErrorIs(t, limitErr, ErrCompletionLimit)
+
+	card.CompletedAt = now.Add(-4 * time.Hour)
+	assert.NoError(t, card.CanComplete(now))
+
+	card.Done = 2
+	require.True(t, errors.As(card.CanComplete(now), &limitErr))
+	assert.True(t, limitErr.RetryAt.IsZero())
+}
+
+func TestDuration_JSON(t *testing.T) {
+	var s RepeatSettings
+	require.NoError(t, json.Unmarshal([]byte(`{"max_completions": 1, "cooldown": "1h30m"}`), &s))
+	assert.Equal(t, 90*time.Minute, s.Cooldown.Duration)
+
+	b, err := json.Marshal(s)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"max_completions": 1, "cooldown": "1h30m0s"}`, string(b))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"cooldown": "soon"}`), &s))
+}
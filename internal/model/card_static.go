@@ -7,54 +7,126 @@ import (
 )
 
 const (
-	TypeOrdinary string = "ordinary"
-	TypeProgress string = "progress"
-	TypeOptions  string = "options"
+	TypeOrdinary  string = "ordinary"
+	TypeProgress  string = "progress"
+	TypeOptions   string = "options"
+	TypeStreak    string = "streak"
+	TypeChecklist string = "checklist"
 
 	GoalBuyFood        string = "food"
 	GoalBuyDrink       string = "drink"
 	GoalPlayMore       string = "play"
 	GoalSocialActivity string = "social"
 
-	PoolDaily string = "daily"
-	PoolConst string = "const"
+	PoolDaily  string = "daily"
+	PoolWeekly string = "weekly"
+	PoolConst  string = "const"
+	PoolSeason string = "season"
+
+	// DefaultWeight is the weight of cards without one, a card with weight 10
+	// is picked ten times less often than a default one.
+	DefaultWeight = 100
 )
 
+// CardStatic is a card template. Editing it creates a new Version, user cards
+// keep a copy of the version they were issued with. Archived cards are not
+// issued anymore but stay queryable. ExternalID is the stable key imports
+// match cards by. Availability limits when the card is live, Repeat how often
+// it is completed. Weight makes daily and weekly cards rarer or more frequent,
+// zero means DefaultWeight. Cards of the season pool name their Season.
 type CardStatic struct {
-	ID               string       `json:"id"`
-	Title            string       `json:"title"`
-	ShortDescription string       `json:"short_description"`
-	LongDescription  string       `json:"long_description"`
-	Goal             string       `json:"goal"`
-	CreatedAt        time.Time    `json:"created_at"`
-	Type             string       `json:"type"`
-	Pool             string       `json:"pool"`
-	BackgroundURL    string       `json:"background_url"`
-	ChainName        string       `json:"chain_name"`
-	ChainOrder       int          `json:"chain_order"`
-	OrdSettings      *OrdSettings `json:"ordinary_settings,omitempty"`
-	PrgSettings      *PrgSettings `json:"progress_settings,omitempty"`
-	OptSettings      *OptSettings `json:"options_settings,omitempty"`
+	ID                string             `json:"id"`
+	Version           int                `json:"version"`
+	ExternalID        string             `json:"external_id,omitempty"`
+	Title             string             `json:"title"`
+	ShortDescription  string             `json:"short_description"`
+	LongDescription   string             `json:"long_description"`
+	Goal              string             `json:"goal"`
+	CreatedAt         time.Time          `json:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at"`
+	Archived          bool               `json:"archived"`
+	ArchivedAt        time.Time          `json:"archived_at"`
+	Type              string             `json:"type"`
+	Pool              string             `json:"pool"`
+	BackgroundURL     string             `json:"background_url"`
+	ChainName         string             `json:"chain_name"`
+	ChainOrder        int                `json:"chain_order"`
+	Season            string             `json:"season,omitempty"`
+	OrdSettings       *OrdSettings       `json:"ordinary_settings,omitempty"`
+	PrgSettings       *PrgSettings       `json:"progress_settings,omitempty"`
+	OptSettings       *OptSettings       `json:"options_settings,omitempty"`
+	StreakSettings    *StreakSettings    `json:"streak_settings,omitempty"`
+	ChecklistSettings *ChecklistSettings `json:"checklist_settings,omitempty"`
+	Availability      *Availability      `json:"availability,omitempty"`
+	Repeat            *RepeatSettings    `json:"repeat_settings,omitempty"`
+	Weight            int                `json:"weight,omitempty"`
 }
 
 type OrdSettings struct {
-	Award Award `json:"award"`
+	Award Award `json:"award" yaml:"award"`
 }
 
 type PrgSettings struct {
-	Award       Award `json:"award"`
-	MaxProgress int   `json:"max_progress"`
+	Award       Award `json:"award" yaml:"award"`
+	MaxProgress int   `json:"max_progress" yaml:"max_progress"`
 }
 
 type OptSettings struct {
-	Awards  []Award   `json:"awards"`
-	Options []float32 `json:"options"`
+	Awards  []Award   `json:"awards" yaml:"awards"`
+	Options []float32 `json:"options" yaml:"options"`
+}
+
+// StreakSettings are the settings of streak cards. The card is done after Days
+// consecutive days, GraceDays more days may be skipped between completions
+// without losing the streak. Every milestone gives its award when the streak
+// reaches it.
+type StreakSettings struct {
+	Days       int         `json:"days" yaml:"days"`
+	GraceDays  int         `json:"grace_days" yaml:"grace_days"`
+	Milestones []Milestone `json:"milestones" yaml:"milestones"`
+}
+
+type Milestone struct {
+	Day   int   `json:"day" yaml:"day"`
+	Award Award `json:"award" yaml:"award"`
+}
+
+// Milestone returns the milestone of the streak day.
+func (s StreakSettings) Milestone(day int) (Milestone, bool) {
+	for _, m := range s.Milestones {
+		if m.Day == day {
+			return m, true
+		}
+	}
+	return Milestone{}, false
+}
+
+// ChecklistSettings are the settings of checklist cards. Steps are checked in
+// any order, the award is given when the last one is checked.
+type ChecklistSettings struct {
+	Steps []Step `json:"steps" yaml:"steps"`
+	Award Award  `json:"award" yaml:"award"`
+}
+
+type Step struct {
+	ID    string `json:"id" yaml:"id"`
+	Title string `json:"title" yaml:"title"`
+}
+
+// Step returns the step with the id.
+func (s ChecklistSettings) Step(id string) (Step, bool) {
+	for _, step := range s.Steps {
+		if step.ID == id {
+			return step, true
+		}
+	}
+	return Step{}, false
 }
 
 type Award struct {
-	XPoints       int    `json:"XPoints"`
-	Prize         string `json:"prize"`
-	PrizeImageURL string `json:"prize_image_url"`
+	XPoints       int    `json:"XPoints" yaml:"XPoints"`
+	Prize         string `json:"prize" yaml:"prize"`
+	PrizeImageURL string `json:"prize_image_url" yaml:"prize_image_url"`
 }
 
 func (card CardStatic) Card(ownerUsername string) Card {
@@ -73,6 +145,14 @@ func (card CardStatic) Card(ownerUsername string) Card {
 
 type CardsStatic []CardStatic
 
+// SelectionWeight returns the weight the card is picked at random with.
+func (card CardStatic) SelectionWeight() int {
+	if card.Weight <= 0 {
+		return DefaultWeight
+	}
+	return card.Weight
+}
+
 func (cards CardsStatic) Unique() []CardStatic {
 	exist := make(map[string]bool)
 	unique := make([]CardStatic, 0, len(cards))
@@ -86,33 +166,88 @@ func (cards CardsStatic) Unique() []CardStatic {
 	return unique
 }
 
-func (cards CardsStatic) Random(num int, uniqueGoal bool) []CardStatic {
+// Random picks num cards at random with rnd, a card is picked with the probability of
+// its weight among the cards left. With uniqueGoal every picked card has its
+// own goal. It returns no cards when there are not enough of them.
+func (cards CardsStatic) Random(rnd *rand.Rand, num int, uniqueGoal bool) []CardStatic {
+	return cards.RandomWeighted(rnd, num, uniqueGoal, func(c CardStatic) float64 {
+		return float64(c.SelectionWeight())
+	})
+}
+
+// RandomWeighted is Random with the weights of the cards returned by weight,
+// they must be positive.
+func (cards CardsStatic) RandomWeighted(rnd *rand.Rand, num int, uniqueGoal bool, weight func(CardStatic) float64) []CardStatic {
 	if len(cards) < num {
 		return []CardStatic{}
 	}
 
-	uniqueRandomCards := make([]CardStatic, 0, num)
+	if uniqueGoal && len(cards.goals()) < num {
+		return []CardStatic{}
+	}
 
-	indexes := make(map[int]struct{})
-	goals := make(map[string]struct{})
-	for len(uniqueRandomCards) < num {
-		i := rand.Intn(len(cards))
+	left := make(CardsStatic, len(cards))
+	copy(left, cards)
+
+	randomCards := make([]CardStatic, 0, num)
+	for len(randomCards) < num {
+		if len(left) == 0 {
+			return []CardStatic{}
+		}
+
+		var total float64
+		for _, c := range left {
+			total += weight(c)
+		}
 
-		if _, ok := indexes[i]; ok {
-			continue
+		i, r := 0, rnd.Float64()*total
+		for ; i < len(left)-1 && r >= weight(left[i]); i++ {
+			r -= weight(left[i])
 		}
 
+		card := left[i]
+		randomCards = append(randomCards, card)
+		left = append(left[:i], left[i+1:]...)
+
 		if uniqueGoal {
-			if _, ok := goals[cards[i].Goal]; ok {
-				continue
-			}
-			goals[cards[i].Goal] = struct{}{}
+			left = left.withoutGoal(card.Goal)
 		}
+	}
+	return randomCards
+}
 
-		indexes[i] = struct{}{}
-		uniqueRandomCards = append(uniqueRandomCards, cards[i])
+func (cards CardsStatic) withoutGoal(goal string) CardsStatic {
+	without := cards[:0]
+	for _, c := range cards {
+		if c.Goal != goal {
+			without = append(without, c)
+		}
+	}
+	return without
+}
+
+// Replace returns the const pool with card in place of the card with its id.
+// Cards of other pools are only removed.
+func (cards CardsStatic) Replace(card CardStatic) CardsStatic {
+	replaced := make(CardsStatic, 0, len(cards)+1)
+	for _, c := range cards {
+		if c.ID != card.ID {
+			replaced = append(replaced, c)
+		}
+	}
+
+	if card.Pool == PoolConst {
+		replaced = append(replaced, card)
+	}
+	return replaced
+}
+
+func (cards CardsStatic) goals() map[string]struct{} {
+	goals := make(map[string]struct{})
+	for _, c := range cards {
+		goals[c.Goal] = struct{}{}
 	}
-	return uniqueRandomCards
+	return goals
 }
 
 func (c *CardStatic) MarshalJSON() ([]byte, error) {
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardsStatic_RandomUniqueGoal(t *testing.T) {
+	cards := CardsStatic{
+		{ID: "1", Goal: "karaoke"},
+		{ID: "2", Goal: "karaoke"},
+		{ID: "3", Goal: "merch"},
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	random := cards.Random(rnd, 2, true)
+	require.Len(t, random, 2)
+	assert.NotEqual(t, random[0].Goal, random[1].Goal)
+
+	assert.Empty(t, cards.Random(rnd, 3, true))
+	assert.Len(t, cards.Random(rnd, 3, false), 3)
+}
+
+func TestCardsStatic_RandomWeighted(t *testing.T) {
+	cards := CardsStatic{
+		{ID: "common", Goal: "karaoke"},
+		{ID: "rare", Goal: "karaoke", Weight: 1},
+		{ID: "sponsored", Goal: "merch", Weight: 1000},
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	picked := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		random := cards.Random(rnd, 1, false)
+		require.Len(t, random, 1)
+		picked[random[0].ID]++
+	}
+	assert.Greater(t, picked["sponsored"], picked["common"])
+	assert.Greater(t, picked["common"], picked["rare"])
+
+	for i := 0; i < 100; i++ {
+		random := cards.Random(rnd, 2, true)
+		require.Len(t, random, 2)
+		assert.NotEqual(t, random[0].Goal, random[1].Goal)
+	}
+	assert.Equal(t, cards.Random(rand.New(rand.NewSource(7)), 2, false), cards.Random(rand.New(rand.NewSource(7)), 2, false))
+	assert.Len(t, cards, 3, "random must not change the cards")
+	assert.Equal(t, "common", cards[0].ID)
+}
@@ -0,0 +1,189 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Violation is a single problem of a static card definition.
+type Violation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every violation found in a static card definition.
+type ValidationError struct {
+	Violations []Violation `json:"violations"`
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Field + ": " + v.Message
+	}
+	return fmt.Sprintf("%s: %s", ErrInvalidCard, strings.Join(msgs, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidCard
+}
+
+func (e *ValidationError) add(field, format string, args ...interface{}) {
+	e.Violations = append(e.Violations, Violation{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+var (
+	cardTypes = []string{TypeOrdinary, TypeProgress, TypeOptions, TypeStreak, TypeChecklist}
+	cardPools = []string{PoolDaily, PoolWeekly, PoolConst, PoolSeason}
+)
+
+// Validate checks the card definition against the configured goals and
+// seasons, chain is the const pool the card goes to and is used to find
+// colliding chain orders. A negative ChainOrder means it was not given.
+func (card CardStatic) Validate(goals Goals, chain CardsStatic, seasons Seasons) error {
+	e := new(ValidationError)
+
+	if names := goals.Names(); !contains(names, card.Goal) {
+		e.add("goal", "must be one of %s", strings.Join(names, ", "))
+	}
+
+	switch card.Pool {
+	case PoolConst:
+		if card.ChainName == "" {
+			e.add("chain_name", "is required for the %s pool", PoolConst)
+		}
+		if card.ChainOrder < 0 {
+			e.add("chain_order", "is required for the %s pool", PoolConst)
+		}
+		for _, c := range chain {
+			if c.ID != card.ID && c.Pool == PoolConst && c.ChainName == card.ChainName && c.ChainOrder == card.ChainOrder {
+				e.add("chain_order", "%d is taken by card %s in chain %q", card.ChainOrder, c.ID, card.ChainName)
+				break
+			}
+		}
+	case PoolSeason:
+		if _, ok := seasons.Get(card.Season); !ok {
+			e.add("season", "must be one of the seasons: %s", strings.Join(seasons.Names(), ", "))
+		}
+	case PoolDaily, PoolWeekly:
+	default:
+		e.add("pool", "must be one of %s", strings.Join(cardPools, ", "))
+	}
+
+	if !contains(cardTypes, card.Type) {
+		e.add("type", "must be one of %s", strings.Join(cardTypes, ", "))
+	}
+	if (card.OrdSettings != nil) != (card.Type == TypeOrdinary) {
+		e.add("ordinary_settings", "must be set only for %s cards", TypeOrdinary)
+	}
+	if (card.PrgSettings != nil) != (card.Type == TypeProgress) {
+		e.add("progress_settings", "must be set only for %s cards", TypeProgress)
+	}
+	if (card.OptSettings != nil) != (card.Type == TypeOptions) {
+		e.add("options_settings", "must be set only for %s cards", TypeOptions)
+	}
+	if (card.StreakSettings != nil) != (card.Type == TypeStreak) {
+		e.add("streak_settings", "must be set only for %s cards", TypeStreak)
+	}
+	if (card.ChecklistSettings != nil) != (card.Type == TypeChecklist) {
+		e.add("checklist_settings", "must be set only for %s cards", TypeChecklist)
+	}
+
+	if s := card.PrgSettings; s != nil && s.MaxProgress <= 0 {
+		e.add("progress_settings.max_progress", "must be positive")
+	}
+
+	if s := card.OptSettings; s != nil {
+		if len(s.Options) == 0 {
+			e.add("options_settings.options", "must not be empty")
+		}
+		for i := 1; i < len(s.Options); i++ {
+			if s.Options[i] <= s.Options[i-1] {
+				e.add("options_settings.options", "must be sorted in ascending order without duplicates")
+				break
+			}
+		}
+		if len(s.Awards) != len(s.Options) {
+			e.add("options_settings.awards", "must have one award per option, got %d awards for %d options", len(s.Awards), len(s.Options))
+		}
+	}
+
+	if card.Type == TypeStreak && card.Pool != PoolConst {
+		e.add("pool", "must be %s for %s cards, pending cards of other pools are replaced or expire", PoolConst, TypeStreak)
+	}
+
+	if s := card.StreakSettings; s != nil {
+		if s.Days <= 0 {
+			e.add("streak_settings.days", "must be positive")
+		}
+		if s.GraceDays < 0 {
+			e.add("streak_settings.grace_days", "must not be negative")
+		}
+		if len(s.Milestones) == 0 || s.Milestones[len(s.Milestones)-1].Day != s.Days {
+			e.add("streak_settings.milestones", "must end with the milestone of day %d", s.Days)
+		}
+		for i, m := range s.Milestones {
+			if m.Day <= 0 || i > 0 && m.Day <= s.Milestones[i-1].Day {
+				e.add("streak_settings.milestones", "days must be positive and ascending without duplicates")
+				break
+			}
+		}
+	}
+
+	if s := card.ChecklistSettings; s != nil {
+		if len(s.Steps) == 0 {
+			e.add("checklist_settings.steps", "must not be empty")
+		}
+		ids := make(map[string]bool, len(s.Steps))
+		for _, step := range s.Steps {
+			if step.ID == "" || step.Title == "" || ids[step.ID] {
+				e.add("checklist_settings.steps", "must have unique ids and titles")
+				break
+			}
+			ids[step.ID] = true
+		}
+	}
+
+	if a := card.Availability; a != nil {
+		if !a.StartsAt.IsZero() && !a.EndsAt.IsZero() && !a.EndsAt.After(a.StartsAt) {
+			e.add("availability.ends_at", "must be after starts_at")
+		}
+		for _, d := range a.Weekdays {
+			if d < time.Sunday || d > time.Saturday {
+				e.add("availability.weekdays", "must be from 0 (sunday) to 6 (saturday)")
+				break
+			}
+		}
+		if a.FromHour < 0 || a.FromHour > 23 || a.ToHour < 0 || a.ToHour > 23 {
+			e.add("availability.from_hour", "from_hour and to_hour must be from 0 to 23")
+		}
+	}
+
+	if s := card.Repeat; s != nil {
+		if s.MaxCompletions < 0 {
+			e.add("repeat_settings.max_completions", "must not be negative")
+		}
+		if s.Cooldown.Duration < 0 {
+			e.add("repeat_settings.cooldown", "must not be negative")
+		}
+	}
+
+	if card.Weight < 0 {
+		e.add("weight", "must not be negative")
+	}
+
+	if len(e.Violations) != 0 {
+		return e
+	}
+	return nil
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,204 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardStatic_Validate(t *testing.T) {
+	fields := func(err error) []string {
+		var verr *ValidationError
+		require.True(t, errors.As(err, &verr))
+		require.ErrorIs(t, err, ErrInvalidCard)
+
+		f := make([]string, len(verr.Violations))
+		for i, v := range verr.Violations {
+			f[i] = v.Field
+		}
+		return f
+	}
+
+	t.Run("valid options card", func(t *testing.T) {
+		card := CardStatic{
+			Goal: GoalBuyDrink,
+			Type: TypeOptions,
+			Pool: PoolDaily,
+			OptSettings: &OptSettings{
+				Options: []float32{1, 2.5, 4},
+				Awards:  []Award{{XPoints: 1}, {XPoints: 2}, {XPoints: 3}},
+			},
+		}
+		assert.NoError(t, card.Validate(DefaultGoals, nil, nil))
+	})
+
+	t.Run("every violation is listed", func(t *testing.T) {
+		card := CardStatic{
+			Goal:       "sleep",
+			Type:       TypeOptions,
+			Pool:       PoolConst,
+			ChainOrder: -1,
+			OptSettings: &OptSettings{
+				Options: []float32{3, 1},
+				Awards:  []Award{{XPoints: 1}},
+			},
+			PrgSettings: &PrgSettings{},
+		}
+		assert.Equal(t, []string{
+			"goal",
+			"chain_name",
+			"chain_order",
+			"progress_settings",
+			"progress_settings.max_progress",
+			"options_settings.options",
+			"options_settings.awards",
+		}, fields(card.Validate(DefaultGoals, nil, nil)))
+	})
+
+	t.Run("settings of another type", func(t *testing.T) {
+		card := CardStatic{Goal: GoalPlayMore, Type: TypeProgress, Pool: PoolDaily, OrdSettings: &OrdSettings{}}
+		assert.Equal(t, []string{"ordinary_settings", "progress_settings"}, fields(card.Validate(DefaultGoals, nil, nil)))
+	})
+
+	t.Run("empty options", func(t *testing.T) {
+		card := CardStatic{Goal: GoalPlayMore, Type: TypeOptions, Pool: PoolDaily, OptSettings: &OptSettings{}}
+		assert.Equal(t, []string{"options_settings.options"}, fields(card.Validate(DefaultGoals, nil, nil)))
+	})
+
+	t.Run("chain order of the card itself", func(t *testing.T) {
+		card := CardStatic{ID: "1", Goal: GoalPlayMore, Type: TypeOrdinary, Pool: PoolConst, ChainName: "c", OrdSettings: &OrdSettings{}}
+		assert.NoError(t, card.Validate(DefaultGoals, CardsStatic{card}, nil))
+	})
+
+	t.Run("streak milestones", func(t *testing.T) {
+		card := CardStatic{
+			Goal: GoalBuyDrink,
+			Type: TypeStreak,
+			Pool: PoolDaily,
+			StreakSettings: &StreakSettings{
+				Days:       7,
+				Milestones: []Milestone{{Day: 3}, {Day: 7}},
+			},
+		}
+		assert.Equal(t, []string{"pool"}, fields(card.Validate(DefaultGoals, nil, nil)))
+
+		card.Pool, card.ChainName = PoolConst, "week"
+		assert.NoError(t, card.Validate(DefaultGoals, nil, nil))
+
+		card.StreakSettings = &StreakSettings{Days: 7, GraceDays: -1, Milestones: []Milestone{{Day: 3}, {Day: 3}}}
+		assert.Equal(t, []string{
+			"streak_settings.grace_days",
+			"streak_settings.milestones",
+			"streak_settings.milestones",
+		}, fields(card.Validate(DefaultGoals, nil, nil)))
+	})
+
+	t.Run("checklist steps", func(t *testing.T) {
+		card := CardStatic{
+			Goal: GoalBuyDrink,
+			Type: TypeChecklist,
+			Pool: PoolDaily,
+			ChecklistSettings: &ChecklistSettings{
+				Steps: []Step{{ID: "mojito", Title: "Mojito"}, {ID: "negroni", Title: "Negroni"}},
+			},
+		}
+		assert.NoError(t, card.Validate(DefaultGoals, nil, nil))
+
+		card.ChecklistSettings.Steps = append(card.ChecklistSettings.Steps, Step{ID: "mojito", Title: "Mojito again"})
+		assert.Equal(t, []string{"checklist_settings.steps"}, fields(card.Validate(DefaultGoals, nil, nil)))
+
+		card.ChecklistSettings = nil
+		assert.Equal(t, []string{"checklist_settings"}, fields(card.Validate(DefaultGoals, nil, nil)))
+	})
+
+	t.Run("availability window", func(t *testing.T) {
+		now := time.Now()
+		card := CardStatic{
+			Goal:        GoalBuyDrink,
+			Type:        TypeOrdinary,
+			Pool:        PoolDaily,
+			OrdSettings: &OrdSettings{},
+			Availability: &Availability{
+				StartsAt: now,
+				EndsAt:   now.Add(-time.Hour),
+				Weekdays: []time.Weekday{7},
+				ToHour:   24,
+			},
+		}
+		assert.Equal(t, []string{
+			"availability.ends_at",
+			"availability.weekdays",
+			"availability.from_hour",
+		}, fields(card.Validate(DefaultGoals, nil, nil)))
+	})
+
+	t.Run("negative weight", func(t *testing.T) {
+		card := CardStatic{Goal: GoalBuyDrink, Type: TypeOrdinary, Pool: PoolDaily, OrdSettings: &OrdSettings{}, Weight: -1}
+		assert.Equal(t, []string{"weight"}, fields(card.Validate(DefaultGoals, nil, nil)))
+	})
+
+	t.Run("configured goal", func(t *testing.T) {
+		goals := Goals{{Name: "karaoke"}}
+		card := CardStatic{Goal: "karaoke", Type: TypeOrdinary, Pool: PoolDaily, OrdSettings: &OrdSettings{}}
+		assert.NoError(t, card.Validate(goals, nil, nil))
+
+		card.Goal = GoalBuyFood
+		assert.Equal(t, []string{"goal"}, fields(card.Validate(goals, nil, nil)))
+	})
+}
+
+func TestDailyStart(t *testing.T) {
+	yekaterinburg, err := time.LoadLocation("Asia/Yekaterinburg")
+	require.NoError(t, err)
+
+	// 01:30 in Yekaterinburg is still the day before with days starting at 06:00
+	t1 := time.Date(2026, 10, 17, 20, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 10, 17, 6, 0, 0, 0, yekaterinburg), DailyStart(t1, yekaterinburg, 6))
+	assert.Equal(t, time.Date(2026, 10, 18, 0, 0, 0, 0, yekaterinburg), DailyStart(t1, yekaterinburg, 0))
+
+	t2 := time.Date(2026, 10, 18, 1, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 10, 18, 6, 0, 0, 0, yekaterinburg), DailyStart(t2, yekaterinburg, 6))
+
+	assert.NoError(t, ValidateTimezone("Asia/Yekaterinburg"))
+	assert.NoError(t, ValidateTimezone(""))
+	assert.ErrorIs(t, ValidateTimezone("Mars/Olympus"), ErrUnknownTimezone)
+	assert.ErrorIs(t, ValidateTimezone("Local"), ErrUnknownTimezone)
+	assert.Equal(t, yekaterinburg, User{Timezone: "Asia/Yekaterinburg"}.Location(time.UTC))
+	assert.Equal(t, time.UTC, User{Timezone: "Mars/Olympus"}.Location(time.UTC))
+	assert.Equal(t, time.UTC, User{Timezone: "Local"}.Location(time.UTC))
+}
+
+func TestWeeklyStart(t *testing.T) {
+	// 2026-10-18 is a sunday, weeks start on mondays at 06:00
+	sunday := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 10, 12, 6, 0, 0, 0, time.UTC), WeeklyStart(sunday, time.UTC, time.Monday, 6))
+	assert.Equal(t, time.Date(2026, 10, 18, 6, 0, 0, 0, time.UTC), WeeklyStart(sunday, time.UTC, time.Sunday, 6))
+
+	mondayNight := time.Date(2026, 10, 19, 5, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 10, 12, 6, 0, 0, 0, time.UTC), WeeklyStart(mondayNight, time.UTC, time.Monday, 6))
+	assert.Equal(t, time.Date(2026, 10, 19, 6, 0, 0, 0, time.UTC), WeeklyStart(mondayNight.Add(time.Hour), time.UTC, time.Monday, 6))
+}
+
+func TestCard_CurrentStreak(t *testing.T) {
+	now := time.Date(2026, 10, 18, 0, 30, 0, 0, time.UTC)
+	card := Card{
+		Static: CardStatic{Type: TypeStreak, StreakSettings: &StreakSettings{Days: 7, GraceDays: 1}},
+		Streak: 4,
+	}
+
+	card.LastDoneAt = time.Date(2026, 10, 17, 23, 50, 0, 0, time.UTC)
+	assert.Equal(t, 1, StreakDays(card.LastDoneAt, now, time.UTC, 0))
+	assert.Equal(t, 4, card.CurrentStreak(now, time.UTC, 0))
+	// the same cards day when days start at 6 or in a timezone ahead
+	assert.Equal(t, 0, StreakDays(card.LastDoneAt, now, time.UTC, 6))
+	assert.Equal(t, 0, StreakDays(card.LastDoneAt, now, time.FixedZone("+05", 5*60*60), 0))
+
+	card.LastDoneAt = time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	assert.Equal(t, 4, card.CurrentStreak(now, time.UTC, 0))
+
+	card.LastDoneAt = time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC)
+	assert.Equal(t, 0, card.CurrentStreak(now, time.UTC, 0))
+}
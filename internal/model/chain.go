@@ -0,0 +1,163 @@
+package model
+
+import (
+	"sort"
+	"strings"
+)
+
+// XPointsPerLevel is how many XPoints a user level takes, users start at
+// level 1.
+const XPointsPerLevel = 1000
+
+// Level returns the level of a user with xpoints.
+func Level(xpoints int) int {
+	if xpoints < 0 {
+		return 1
+	}
+	return xpoints/XPointsPerLevel + 1
+}
+
+// Chain holds the unlock requirements of a const chain, the chain itself is
+// made of the const cards with its name. It unlocks once every chain in
+// Requires is finished and the user has reached MinLevel. Chains without
+// requirements are always unlocked.
+type Chain struct {
+	Name     string   `json:"name"`
+	Requires []string `json:"requires"`
+	MinLevel int      `json:"min_level"`
+}
+
+type Chains []Chain
+
+// LockedChain is a chain a user hasn't unlocked yet, Requires are the chains
+// left to finish.
+type LockedChain struct {
+	Name     string   `json:"name"`
+	Requires []string `json:"requires"`
+	MinLevel int      `json:"min_level"`
+}
+
+// Validate checks the requirements of chain against names, the chain names of
+// const cards, and the other chains. Requirements must not form a cycle.
+func (chain Chain) Validate(names []string, chains Chains) error {
+	e := new(ValidationError)
+
+	if !contains(names, chain.Name) {
+		e.add("name", "no const cards in chain %q", chain.Name)
+	}
+	if chain.MinLevel < 0 {
+		e.add("min_level", "must not be negative")
+	}
+	for _, r := range chain.Requires {
+		if !contains(names, r) {
+			e.add("requires", "no const cards in chain %q", r)
+		}
+	}
+	if cycle := chains.Replace(chain).cycle(chain.Name); cycle != nil {
+		e.add("requires", "chains must not require each other: %s", strings.Join(cycle, " -> "))
+	}
+
+	if len(e.Violations) != 0 {
+		return e
+	}
+	return nil
+}
+
+// Replace returns chains with chain instead of the chain with the same name.
+func (chains Chains) Replace(chain Chain) Chains {
+	replaced := make(Chains, 0, len(chains)+1)
+	for _, c := range chains {
+		if c.Name != chain.Name {
+			replaced = append(replaced, c)
+		}
+	}
+	return append(replaced, chain)
+}
+
+// cycle returns the requirements leading from the chain with name back to it,
+// or nil.
+func (chains Chains) cycle(name string) []string {
+	requires := make(map[string][]string, len(chains))
+	for _, c := range chains {
+		requires[c.Name] = c.Requires
+	}
+
+	visited := make(map[string]bool)
+	var walk func(path []string) []string
+	walk = func(path []string) []string {
+		for _, r := range requires[path[len(path)-1]] {
+			if r == name {
+				return append(path, r)
+			}
+			if visited[r] {
+				continue
+			}
+			visited[r] = true
+			if cycle := walk(append(path, r)); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+	return walk([]string{name})
+}
+
+// Locked returns the chains locked for a user with the finished chains and
+// level by name.
+func (chains Chains) Locked(finished map[string]bool, level int) map[string]LockedChain {
+	locked := make(map[string]LockedChain)
+	for _, c := range chains {
+		var left []string
+		for _, r := range c.Requires {
+			if !finished[r] {
+				left = append(left, r)
+			}
+		}
+
+		if len(left) != 0 || level < c.MinLevel {
+			locked[c.Name] = LockedChain{Name: c.Name, Requires: left, MinLevel: c.MinLevel}
+		}
+	}
+	return locked
+}
+
+// SortedLockedChains returns the locked chains sorted by name.
+func SortedLockedChains(locked map[string]LockedChain) []LockedChain {
+	sorted := make([]LockedChain, 0, len(locked))
+	for _, c := range locked {
+		sorted = append(sorted, c)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	return sorted
+}
+
+// FinishedChains returns the names of const chains in which every card is
+// done at least once.
+func (cards Cards) FinishedChains() map[string]bool {
+	finished := make(map[string]bool)
+	for _, c := range cards {
+		if c.Static.Pool != PoolConst {
+			continue
+		}
+
+		done, ok := finished[c.Static.ChainName]
+		finished[c.Static.ChainName] = (done || !ok) && c.Done > 0
+	}
+	for name, done := range finished {
+		if !done {
+			delete(finished, name)
+		}
+	}
+	return finished
+}
+
+// ChainNames returns the distinct chain names of const cards.
+func (cards CardsStatic) ChainNames() []string {
+	var names []string
+	for _, c := range cards {
+		if c.Pool == PoolConst && !contains(names, c.ChainName) {
+			names = append(names, c.ChainName)
+		}
+	}
+	return names
+}
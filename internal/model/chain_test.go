@@ -0,0 +1,58 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChain_Validate(t *testing.T) {
+	names := []string{"first steps", "regular", "veteran", "legend"}
+	chains := Chains{
+		{Name: "regular", Requires: []string{"first steps"}},
+		{Name: "veteran", Requires: []string{"regular"}},
+	}
+
+	assert.NoError(t, Chain{Name: "legend", Requires: []string{"veteran"}, MinLevel: 10}.Validate(names, chains))
+
+	err := Chain{Name: "first steps", Requires: []string{"veteran"}}.Validate(names, chains)
+	var verr *ValidationError
+	require.True(t, errors.As(err, &verr))
+	require.Len(t, verr.Violations, 1)
+	assert.Equal(t, "requires", verr.Violations[0].Field)
+	assert.Contains(t, verr.Violations[0].Message, "first steps -> veteran -> regular -> first steps")
+
+	err = Chain{Name: "legend", Requires: []string{"legend"}}.Validate(names, chains)
+	assert.ErrorIs(t, err, ErrInvalidCard)
+
+	err = Chain{Name: "bonus", Requires: []string{"unknown"}, MinLevel: -1}.Validate(names, chains)
+	require.True(t, errors.As(err, &verr))
+	assert.Len(t, verr.Violations, 3)
+}
+
+func TestChains_Locked(t *testing.T) {
+	cards := Cards{
+		{Static: CardStatic{Pool: PoolConst, ChainName: "first steps"}, Done: 1},
+		{Static: CardStatic{Pool: PoolConst, ChainName: "first steps"}, Done: 2},
+		{Static: CardStatic{Pool: PoolConst, ChainName: "regular"}, Done: 1},
+		{Static: CardStatic{Pool: PoolConst, ChainName: "regular"}},
+		{Static: CardStatic{Pool: PoolDaily}, Done: 1},
+	}
+	finished := cards.FinishedChains()
+	assert.Equal(t, map[string]bool{"first steps": true}, finished)
+
+	chains := Chains{
+		{Name: "regular", Requires: []string{"first steps"}},
+		{Name: "veteran", Requires: []string{"first steps", "regular"}},
+		{Name: "vip", MinLevel: 3},
+	}
+	locked := chains.Locked(finished, Level(1500))
+	assert.Equal(t, []LockedChain{
+		{Name: "veteran", Requires: []string{"regular"}},
+		{Name: "vip", MinLevel: 3},
+	}, SortedLockedChains(locked))
+
+	assert.Len(t, chains.Locked(finished, Level(2000)), 1)
+}
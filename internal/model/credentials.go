@@ -2,6 +2,9 @@ package model
 
 const CtxCredentialsKey = "credentials"
 
+// CtxAPIKeyIDKey holds the id of the api key a request is made with.
+const CtxAPIKeyIDKey = "api_key_id"
+
 type CredentialsSecure struct {
 	ID       string `json:"id"`
 	Username string `json:"username"`
@@ -10,5 +13,20 @@ type CredentialsSecure struct {
 
 type Credentials struct {
 	CredentialsSecure
-	Password string `json:"-"`
+	Password     string       `json:"-"`
+	SessionID    string       `json:"-"`
+	APIKeyID     string       `json:"-"`
+	Issuer       string       `json:"-"`
+	Subject      string       `json:"-"`
+	SecondFactor SecondFactor `json:"-"`
+}
+
+// SecondFactor is the TOTP enrollment of an account. Secret is set when the
+// enrollment starts and Enabled once the first code is confirmed. Only hashes
+// of the recovery codes are stored, LastStep keeps a code from being reused.
+type SecondFactor struct {
+	Secret        string
+	Enabled       bool
+	RecoveryCodes []string
+	LastStep      int64
 }
@@ -5,11 +5,49 @@ import "errors"
 var (
 	ErrUserExists              = errors.New("user exists")
 	ErrUserNotFound            = errors.New("user not found")
+	ErrUnknownTimezone         = errors.New("unknown timezone")
 	ErrInvalidAccessToken      = errors.New("access token in invalid")
 	ErrUnexpectedSigningMethod = errors.New("unexpected signing method")
+	ErrUnknownSigningKey       = errors.New("unknown signing key")
+	ErrSessionNotFound         = errors.New("session not found")
+	ErrInvalidRefreshToken     = errors.New("refresh token is invalid")
+	ErrInvalidResetToken       = errors.New("password reset token is invalid")
+	ErrTooManyAttempts         = errors.New("too many failed sign in attempts")
+	ErrInvalidChallenge        = errors.New("two-factor challenge is invalid")
+	ErrWrongCode               = errors.New("wrong one-time code")
+	ErrSecondFactorEnabled     = errors.New("two-factor authentication is already enabled")
+	ErrSecondFactorNotEnrolled = errors.New("two-factor authentication is not enrolled")
+	ErrSecondFactorRequired    = errors.New("two-factor authentication is required for this role")
+	ErrOIDCDisabled            = errors.New("oidc sign in is disabled")
+	ErrInvalidOIDCState        = errors.New("oidc state is invalid")
+	ErrInvalidIDToken          = errors.New("id token is invalid")
 	ErrWrongPassword           = errors.New("worng password")
+	ErrEmptyPassword           = errors.New("password is empty")
+	ErrAPIKeyNotFound          = errors.New("api key not found")
+	ErrEmptyAPIKeyName         = errors.New("api key name is empty")
+	ErrWrongPermission         = errors.New("permission can't be granted to api key")
 	ErrWrongRole               = errors.New("this role has no access")
 	ErrNoSuchCard              = errors.New("no such card")
+	ErrInvalidCard             = errors.New("card is invalid")
+	ErrCardChanged             = errors.New("card was changed by another request")
+	ErrUnknownFormat           = errors.New("unknown file format, use json, csv or yaml")
+	ErrUnknownImportMode       = errors.New("unknown import mode, use create or upsert")
+	ErrInvalidImportFile       = errors.New("import file can't be read")
+	ErrInvalidImport           = errors.New("import has invalid cards, nothing was imported")
+	ErrGoalNotFound            = errors.New("goal not found")
+	ErrGoalExists              = errors.New("goal exists")
+	ErrGoalInUse               = errors.New("goal is used by cards")
+	ErrInvalidGoalName         = errors.New("goal name must be 1-30 lowercase letters, digits, _ or -")
+	ErrStreakDoneToday         = errors.New("streak card is already done today")
+	ErrNoSuchStep              = errors.New("no such step")
+	ErrStepChecked             = errors.New("step is already checked")
+	ErrCardNotAvailable        = errors.New("card is not available now")
+	ErrCompletionLimit         = errors.New("card can't be completed now")
+	ErrChainNotFound           = errors.New("chain has no requirements")
+	ErrChainLocked             = errors.New("chain of the card is locked")
+	ErrSeasonNotFound          = errors.New("season not found")
+	ErrSeasonInUse             = errors.New("season is used by cards")
+	ErrSeasonEnded             = errors.New("season has ended")
 	ErrCardAlreadyInPool       = errors.New("card already exist in pool")
 	ErrInterfaceCast           = errors.New("couldn't cast interface")
 	ErrNoSuchPool              = errors.New("no such pool")
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"regexp"
+	"time"
+)
+
+// goalName keeps goal names usable as stable keys, cards refer to goals by
+// name.
+var goalName = regexp.MustCompile(`^[a-z0-9_-]{1,30}$`)
+
+// DefaultGoals are created when there are no goals yet.
+var DefaultGoals = Goals{
+	{Name: GoalBuyFood, Title: "Food"},
+	{Name: GoalBuyDrink, Title: "Drink"},
+	{Name: GoalPlayMore, Title: "Play"},
+	{Name: GoalSocialActivity, Title: "Social"},
+}
+
+// Goal is what a card asks the user to do. Daily cards can be drawn with a
+// unique goal each.
+type Goal struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (g Goal) Validate() error {
+	if !goalName.MatchString(g.Name) {
+		return ErrInvalidGoalName
+	}
+	return nil
+}
+
+type Goals []Goal
+
+func (goals Goals) Names() []string {
+	names := make([]string, len(goals))
+	for i, g := range goals {
+		names[i] = g.Name
+	}
+	return names
+}
@@ -0,0 +1,18 @@
+package model
+
+// Identity is an account authenticated by an external identity provider.
+type Identity struct {
+	Issuer   string
+	Subject  string
+	Username string
+	Role     Role
+}
+
+// OIDCLogin is what the client keeps between the redirect to the provider and
+// the callback, Verifier is the PKCE code verifier.
+type OIDCLogin struct {
+	URL      string
+	State    string
+	Nonce    string
+	Verifier string
+}
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// LoginAttempts counts failed sign ins for one username or client IP.
+type LoginAttempts struct {
+	Key         string    `json:"key"`
+	Failures    int       `json:"failures"`
+	LastFailure time.Time `json:"last_failure"`
+	LockedUntil time.Time `json:"locked_until"`
+}
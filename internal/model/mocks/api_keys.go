@@ -0,0 +1,39 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type APIKeysRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *APIKeysRepositoryMock) Create(ctx context.Context, key model.APIKey) (string, error) {
+	args := m.Called(ctx, key)
+	return args.String(0), args.Error(1)
+}
+
+func (m *APIKeysRepositoryMock) GetAll(ctx context.Context) ([]model.APIKey, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.APIKey), args.Error(1)
+}
+
+func (m *APIKeysRepositoryMock) GetByKey(ctx context.Context, key string) (model.APIKey, error) {
+	args := m.Called(ctx, key)
+	return args.Get(0).(model.APIKey), args.Error(1)
+}
+
+func (m *APIKeysRepositoryMock) Revoke(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *APIKeysRepositoryMock) UpdateLastUsed(ctx context.Context, id string, lastUsedAt time.Time) error {
+	args := m.Called(ctx, id, lastUsedAt)
+	return args.Error(0)
+}
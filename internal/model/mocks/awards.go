@@ -0,0 +1,23 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type AwardsRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *AwardsRepositoryMock) Add(ctx context.Context, award model.UserPrize) error {
+	args := m.Called(ctx, award)
+	return args.Error(0)
+}
+
+func (m *AwardsRepositoryMock) GetByUsername(ctx context.Context, username string) ([]model.UserPrize, error) {
+	args := m.Called(ctx, username)
+	return args.Get(0).([]model.UserPrize), args.Error(1)
+}
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 
@@ -22,15 +23,48 @@ func (m *CardsRepositoryMock) GetCardsByOwnerPool(ctx context.Context, ownerUser
 }
 
 func (m *CardsRepositoryMock) GetStatic(ctx context.Context, ids []string) (model.CardsStatic, error) {
-	panic("not implemented") // TODO: Implement
+	args := m.Called(ctx, ids)
+	return args.Get(0).(model.CardsStatic), args.Error(1)
 }
 
 func (m *CardsRepositoryMock) CreateStatic(ctx context.Context, card model.CardStatic) error {
-	panic("not implemented") // TODO: Implement
+	args := m.Called(ctx, card)
+	return args.Error(0)
 }
 
-func (m *CardsRepositoryMock) DeleteStatic(ctx context.Context, ids []string) (err error) {
-	panic("not implemented") // TODO: Implement
+func (m *CardsRepositoryMock) UpdateStatic(ctx context.Context, previous, card model.CardStatic) error {
+	args := m.Called(ctx, previous, card)
+	return args.Error(0)
+}
+
+func (m *CardsRepositoryMock) GetStaticVersions(ctx context.Context, id string) (model.CardsStatic, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(model.CardsStatic), args.Error(1)
+}
+
+func (m *CardsRepositoryMock) ArchiveStatic(ctx context.Context, ids []string, archived bool, at time.Time) error {
+	args := m.Called(ctx, ids, archived, at)
+	return args.Error(0)
+}
+
+func (m *CardsRepositoryMock) DeletePendingCardsByStatic(ctx context.Context, staticIDs []string) error {
+	args := m.Called(ctx, staticIDs)
+	return args.Error(0)
+}
+
+func (m *CardsRepositoryMock) GetStaticByExternalID(ctx context.Context, externalID string) (model.CardStatic, error) {
+	args := m.Called(ctx, externalID)
+	return args.Get(0).(model.CardStatic), args.Error(1)
+}
+
+func (m *CardsRepositoryMock) CountStaticByGoal(ctx context.Context, goal string) (int, error) {
+	args := m.Called(ctx, goal)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *CardsRepositoryMock) CountStaticBySeason(ctx context.Context, season string) (int, error) {
+	args := m.Called(ctx, season)
+	return args.Int(0), args.Error(1)
 }
 
 func (m *CardsRepositoryMock) GetStaticByPool(ctx context.Context, pool string) (model.CardsStatic, error) {
@@ -38,8 +72,19 @@ func (m *CardsRepositoryMock) GetStaticByPool(ctx context.Context, pool string)
 	return args.Get(0).(model.CardsStatic), args.Error(1)
 }
 
-func (m *CardsRepositoryMock) DeleteUsersPendingDailyCards(ctx context.Context, username string) error {
-	panic("not implemented") // TODO: Implement
+func (m *CardsRepositoryMock) DeleteUsersPendingCards(ctx context.Context, username, pool string) error {
+	args := m.Called(ctx, username, pool)
+	return args.Error(0)
+}
+
+func (m *CardsRepositoryMock) AddDailyIssues(ctx context.Context, issues []model.DailyIssue) error {
+	args := m.Called(ctx, issues)
+	return args.Error(0)
+}
+
+func (m *CardsRepositoryMock) GetDailyIssues(ctx context.Context, username string, since time.Time) ([]model.DailyIssue, error) {
+	args := m.Called(ctx, username, since)
+	return args.Get(0).([]model.DailyIssue), args.Error(1)
 }
 
 func (m *CardsRepositoryMock) GetCardsByOwner(ctx context.Context, ownerUsername string) (model.Cards, error) {
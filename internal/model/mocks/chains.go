@@ -0,0 +1,28 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type ChainsRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ChainsRepositoryMock) GetAll(ctx context.Context) (model.Chains, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(model.Chains), args.Error(1)
+}
+
+func (m *ChainsRepositoryMock) Set(ctx context.Context, chain model.Chain) error {
+	args := m.Called(ctx, chain)
+	return args.Error(0)
+}
+
+func (m *ChainsRepositoryMock) Delete(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
@@ -0,0 +1,43 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type CredentialsRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *CredentialsRepositoryMock) Create(ctx context.Context, credentials model.Credentials) (string, error) {
+	args := m.Called(ctx, credentials)
+	return args.String(0), args.Error(1)
+}
+
+func (m *CredentialsRepositoryMock) GetByUsername(ctx context.Context, username string) (model.Credentials, error) {
+	args := m.Called(ctx, username)
+	return args.Get(0).(model.Credentials), args.Error(1)
+}
+
+func (m *CredentialsRepositoryMock) GetByIdentity(ctx context.Context, issuer, subject string) (model.Credentials, error) {
+	args := m.Called(ctx, issuer, subject)
+	return args.Get(0).(model.Credentials), args.Error(1)
+}
+
+func (m *CredentialsRepositoryMock) UpdatePassword(ctx context.Context, id, password string) error {
+	args := m.Called(ctx, id, password)
+	return args.Error(0)
+}
+
+func (m *CredentialsRepositoryMock) UpdateRole(ctx context.Context, id string, role model.Role) error {
+	args := m.Called(ctx, id, role)
+	return args.Error(0)
+}
+
+func (m *CredentialsRepositoryMock) UpdateSecondFactor(ctx context.Context, id string, factor model.SecondFactor) error {
+	args := m.Called(ctx, id, factor)
+	return args.Error(0)
+}
@@ -0,0 +1,38 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type GoalsRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *GoalsRepositoryMock) Create(ctx context.Context, goal model.Goal) (string, error) {
+	args := m.Called(ctx, goal)
+	return args.String(0), args.Error(1)
+}
+
+func (m *GoalsRepositoryMock) GetAll(ctx context.Context) (model.Goals, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(model.Goals), args.Error(1)
+}
+
+func (m *GoalsRepositoryMock) GetByName(ctx context.Context, name string) (model.Goal, error) {
+	args := m.Called(ctx, name)
+	return args.Get(0).(model.Goal), args.Error(1)
+}
+
+func (m *GoalsRepositoryMock) UpdateTitle(ctx context.Context, name, title string) error {
+	args := m.Called(ctx, name, title)
+	return args.Error(0)
+}
+
+func (m *GoalsRepositoryMock) Delete(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
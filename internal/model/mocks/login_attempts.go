@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type LoginAttemptsRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *LoginAttemptsRepositoryMock) Get(ctx context.Context, key string) (model.LoginAttempts, error) {
+	args := m.Called(ctx, key)
+	return args.Get(0).(model.LoginAttempts), args.Error(1)
+}
+
+func (m *LoginAttemptsRepositoryMock) AddFailure(ctx context.Context, key string, now, since time.Time) (model.LoginAttempts, error) {
+	args := m.Called(ctx, key, now, since)
+	return args.Get(0).(model.LoginAttempts), args.Error(1)
+}
+
+func (m *LoginAttemptsRepositoryMock) Lock(ctx context.Context, key string, until time.Time) error {
+	args := m.Called(ctx, key, until)
+	return args.Error(0)
+}
+
+func (m *LoginAttemptsRepositoryMock) Delete(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
@@ -0,0 +1,28 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type SeasonsRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *SeasonsRepositoryMock) GetAll(ctx context.Context) (model.Seasons, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(model.Seasons), args.Error(1)
+}
+
+func (m *SeasonsRepositoryMock) Set(ctx context.Context, season model.Season) error {
+	args := m.Called(ctx, season)
+	return args.Error(0)
+}
+
+func (m *SeasonsRepositoryMock) Delete(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
@@ -0,0 +1,57 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type SessionsRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *SessionsRepositoryMock) Create(ctx context.Context, session model.Session) (string, error) {
+	args := m.Called(ctx, session)
+	return args.String(0), args.Error(1)
+}
+
+func (m *SessionsRepositoryMock) Get(ctx context.Context, id string) (model.Session, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(model.Session), args.Error(1)
+}
+
+func (m *SessionsRepositoryMock) GetByRefreshToken(ctx context.Context, refreshToken string) (model.Session, error) {
+	args := m.Called(ctx, refreshToken)
+	return args.Get(0).(model.Session), args.Error(1)
+}
+
+func (m *SessionsRepositoryMock) Update(ctx context.Context, session model.Session) error {
+	args := m.Called(ctx, session)
+	return args.Error(0)
+}
+
+func (m *SessionsRepositoryMock) RevokeByUsername(ctx context.Context, username string) error {
+	args := m.Called(ctx, username)
+	return args.Error(0)
+}
+
+type PasswordResetsRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *PasswordResetsRepositoryMock) Create(ctx context.Context, reset model.PasswordReset) error {
+	args := m.Called(ctx, reset)
+	return args.Error(0)
+}
+
+func (m *PasswordResetsRepositoryMock) GetByToken(ctx context.Context, token string) (model.PasswordReset, error) {
+	args := m.Called(ctx, token)
+	return args.Get(0).(model.PasswordReset), args.Error(1)
+}
+
+func (m *PasswordResetsRepositoryMock) Update(ctx context.Context, reset model.PasswordReset) error {
+	args := m.Called(ctx, reset)
+	return args.Error(0)
+}
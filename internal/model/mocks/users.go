@@ -0,0 +1,33 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type UsersRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *UsersRepositoryMock) Create(ctx context.Context, user model.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+func (m *UsersRepositoryMock) GetByUsername(ctx context.Context, username string) (model.User, error) {
+	args := m.Called(ctx, username)
+	return args.Get(0).(model.User), args.Error(1)
+}
+
+func (m *UsersRepositoryMock) GetAll(ctx context.Context) ([]model.User, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.User), args.Error(1)
+}
+
+func (m *UsersRepositoryMock) Update(ctx context.Context, user model.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
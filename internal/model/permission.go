@@ -0,0 +1,100 @@
+package model
+
+type Permission string
+
+const (
+	PermProfileRead      Permission = "profile.read"
+	PermCardsView        Permission = "cards.view"
+	PermCardsRead        Permission = "cards.read"
+	PermCardsComplete    Permission = "cards.complete"
+	PermCardsStaticRead  Permission = "cards.static.read"
+	PermCardsStaticWrite Permission = "cards.static.write"
+	PermUsersRead        Permission = "users.read"
+	PermImagesUpload     Permission = "images.upload"
+	PermAdminsWrite      Permission = "admins.write"
+	PermPasswordsReset   Permission = "passwords.reset"
+	PermAPIKeysWrite     Permission = "api_keys.write"
+)
+
+var (
+	userPermissions = []Permission{
+		PermProfileRead,
+		PermCardsView,
+	}
+
+	cashierPermissions = []Permission{
+		PermCardsRead,
+		PermCardsComplete,
+	}
+
+	adminPermissions = append(append([]Permission{}, userPermissions...),
+		PermCardsRead,
+		PermCardsComplete,
+		PermCardsStaticRead,
+		PermCardsStaticWrite,
+		PermUsersRead,
+		PermImagesUpload,
+		PermPasswordsReset,
+	)
+
+	moderatorPermissions = append(append([]Permission{}, adminPermissions...),
+		PermAdminsWrite,
+		PermAPIKeysWrite,
+	)
+
+	// apiKeyPermissions can be granted to api keys, account management
+	// and the endpoints of the signed in user stay with people
+	apiKeyPermissions = []Permission{
+		PermCardsRead,
+		PermCardsComplete,
+		PermCardsStaticRead,
+		PermCardsStaticWrite,
+		PermUsersRead,
+		PermImagesUpload,
+	}
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleUser:      userPermissions,
+	RoleAdmin:     adminPermissions,
+	RoleModerator: moderatorPermissions,
+	RoleCashier:   cashierPermissions,
+}
+
+// ForAPIKeys reports whether p can be granted to an api key.
+func (p Permission) ForAPIKeys() bool {
+	for _, k := range apiKeyPermissions {
+		if k == p {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Role) Permissions() []Permission {
+	return rolePermissions[r]
+}
+
+// Includes reports whether r has every permission of other.
+func (r Role) Includes(other Role) bool {
+	for _, p := range rolePermissions[other] {
+		if !r.Can(p) {
+			return false
+		}
+	}
+	return true
+}
+
+// Above reports whether r has every permission of other and some more.
+func (r Role) Above(other Role) bool {
+	return r.Includes(other) && !other.Includes(r)
+}
+
+func (r Role) Can(permission Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRole_Can(t *testing.T) {
+	// every role keeps the permissions of the roles below it in the old ladder
+	ladder := []Role{RoleUser, RoleAdmin, RoleModerator}
+	for i, lower := range ladder {
+		for _, higher := range ladder[i:] {
+			for _, p := range lower.Permissions() {
+				assert.True(t, higher.Can(p), "role %d must have %s", higher, p)
+			}
+		}
+	}
+
+	assert.False(t, RoleUser.Can(PermCardsComplete))
+	assert.False(t, RoleAdmin.Can(PermAdminsWrite))
+	assert.True(t, RoleCashier.Can(PermCardsComplete))
+	assert.False(t, RoleCashier.Can(PermCardsStaticWrite))
+
+	assert.True(t, RoleModerator.Includes(RoleAdmin))
+	assert.True(t, RoleAdmin.Includes(RoleUser))
+	assert.True(t, RoleAdmin.Includes(RoleCashier))
+	assert.False(t, RoleAdmin.Includes(RoleModerator))
+
+	assert.True(t, RoleAdmin.Above(RoleUser))
+	assert.True(t, RoleModerator.Above(RoleAdmin))
+	assert.False(t, RoleAdmin.Above(RoleAdmin))
+	assert.False(t, RoleAdmin.Above(RoleModerator))
+
+	assert.True(t, PermCardsComplete.ForAPIKeys())
+	assert.False(t, PermAdminsWrite.ForAPIKeys())
+	assert.False(t, PermProfileRead.ForAPIKeys())
+}
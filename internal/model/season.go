@@ -0,0 +1,63 @@
+package model
+
+import "time"
+
+// Season is a named card pool live from StartsAt till EndsAt. Its cards are
+// issued to every user while it is live, once it ends they expire and their
+// progress freezes.
+type Season struct {
+	Name     string    `json:"name"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+type Seasons []Season
+
+// Validate checks the name and the dates of the season.
+func (s Season) Validate() error {
+	e := new(ValidationError)
+
+	if s.Name == "" {
+		e.add("name", "is required")
+	}
+	if s.StartsAt.IsZero() {
+		e.add("starts_at", "is required")
+	}
+	if !s.EndsAt.After(s.StartsAt) {
+		e.add("ends_at", "must be after starts_at")
+	}
+
+	if len(e.Violations) != 0 {
+		return e
+	}
+	return nil
+}
+
+// Active reports whether the season is live at t.
+func (s Season) Active(t time.Time) bool {
+	return !t.Before(s.StartsAt) && t.Before(s.EndsAt)
+}
+
+// Ended reports whether the season is over at t.
+func (s Season) Ended(t time.Time) bool {
+	return !t.Before(s.EndsAt)
+}
+
+// Get returns the season with the name.
+func (seasons Seasons) Get(name string) (Season, bool) {
+	for _, s := range seasons {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Season{}, false
+}
+
+// Names returns the names of the seasons.
+func (seasons Seasons) Names() []string {
+	names := make([]string, len(seasons))
+	for i, s := range seasons {
+		names[i] = s.Name
+	}
+	return names
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeason_Validate(t *testing.T) {
+	start := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, Season{Name: "winter", StartsAt: start, EndsAt: start.AddDate(0, 3, 0)}.Validate())
+
+	var verr *ValidationError
+	require.True(t, errors.As(Season{EndsAt: start}.Validate(), &verr))
+	assert.Equal(t, []Violation{
+		{Field: "name", Message: "is required"},
+		{Field: "starts_at", Message: "is required"},
+	}, verr.Violations)
+
+	require.True(t, errors.As(Season{Name: "winter", StartsAt: start, EndsAt: start}.Validate(), &verr))
+	assert.Equal(t, "ends_at", verr.Violations[0].Field)
+}
+
+func TestSeason_Active(t *testing.T) {
+	start := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+	s := Season{Name: "winter", StartsAt: start, EndsAt: start.AddDate(0, 3, 0)}
+
+	assert.False(t, s.Active(start.Add(-time.Second)))
+	assert.True(t, s.Active(start))
+	assert.False(t, s.Ended(start))
+	assert.False(t, s.Active(s.EndsAt))
+	assert.True(t, s.Ended(s.EndsAt))
+}
+
+func TestCardStatic_ValidateSeason(t *testing.T) {
+	seasons := Seasons{{Name: "winter"}}
+	card := CardStatic{
+		Goal:        GoalBuyFood,
+		Type:        TypeOrdinary,
+		Pool:        PoolSeason,
+		Season:      "winter",
+		OrdSettings: &OrdSettings{},
+	}
+	assert.NoError(t, card.Validate(DefaultGoals, nil, seasons))
+
+	card.Season = "summer"
+	var verr *ValidationError
+	require.True(t, errors.As(card.Validate(DefaultGoals, nil, seasons), &verr))
+	assert.Equal(t, []Violation{{Field: "season", Message: "must be one of the seasons: winter"}}, verr.Violations)
+}
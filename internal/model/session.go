@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+type Session struct {
+	ID           string    `json:"id"`
+	Username     string    `json:"username"`
+	RefreshToken string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	Revoked      bool      `json:"revoked"`
+}
+
+type Tokens struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Challenge is returned by sign in instead of tokens until the second factor
+// is verified. EnrollmentRequired is set for accounts whose role requires the
+// second factor but which have not enrolled yet.
+type Challenge struct {
+	Token              string    `json:"challenge_token"`
+	Username           string    `json:"-"`
+	EnrollmentRequired bool      `json:"enrollment_required"`
+	ExpiresAt          time.Time `json:"expires_at"`
+}
+
+// PasswordReset is a single-use token that lets a user set a new password
+// without knowing the old one. Only the hash of the token is stored.
+type PasswordReset struct {
+	ID        string    `json:"id"`
+	Username  string    `json:"username"`
+	Token     string    `json:"-"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Used      bool      `json:"used"`
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -12,16 +13,67 @@ const (
 	RoleUser Role = iota + 1
 	RoleAdmin
 	RoleModerator
+	RoleCashier
 )
 
 type User struct {
 	CredentialsSecure
-	Nickname             string      `json:"nickname"`
-	AvatarURL            string      `json:"avatar_url"`
-	XPoints              int         `json:"XPoints"`
-	RegistrationTime     time.Time   `json:"registration_time"`
-	LastDailyCardsUpdate time.Time   `json:"last_daily_cards_update"`
-	Prizes               []UserPrize `json:"prizes"`
+	Nickname              string      `json:"nickname"`
+	AvatarURL             string      `json:"avatar_url"`
+	XPoints               int         `json:"XPoints"`
+	RegistrationTime      time.Time   `json:"registration_time"`
+	LastDailyCardsUpdate  time.Time   `json:"last_daily_cards_update"`
+	LastWeeklyCardsUpdate time.Time   `json:"last_weekly_cards_update"`
+	Timezone              string      `json:"timezone"`
+	Prizes                []UserPrize `json:"prizes"`
+}
+
+// Level returns the level of the user by the XPoints.
+func (u User) Level() int {
+	return Level(u.XPoints)
+}
+
+// LastRotation returns when the cards of the rotating pool were last replaced.
+func (u User) LastRotation(pool string) time.Time {
+	if pool == PoolWeekly {
+		return u.LastWeeklyCardsUpdate
+	}
+	return u.LastDailyCardsUpdate
+}
+
+// SetLastRotation records that the cards of the rotating pool were replaced at t.
+func (u *User) SetLastRotation(pool string, t time.Time) {
+	if pool == PoolWeekly {
+		u.LastWeeklyCardsUpdate = t
+		return
+	}
+	u.LastDailyCardsUpdate = t
+}
+
+// Location returns the timezone of the user, def if they have none.
+func (u User) Location(def *time.Location) *time.Location {
+	if u.Timezone == "" || u.Timezone == "Local" {
+		return def
+	}
+
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return def
+	}
+	return loc
+}
+
+// ValidateTimezone checks that name is an IANA timezone such as
+// "Asia/Yekaterinburg", empty name stands for the venue timezone. "Local" is
+// the timezone of the server, not an IANA one, so it is rejected.
+func ValidateTimezone(name string) error {
+	if name == "Local" {
+		return fmt.Errorf("%w: %q", ErrUnknownTimezone, name)
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return fmt.Errorf("%w: %q", ErrUnknownTimezone, name)
+	}
+	return nil
 }
 
 type UserPrize struct {
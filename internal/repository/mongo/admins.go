@@ -9,6 +9,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type AdminsRepository struct {
@@ -40,6 +41,21 @@ func (r *AdminsRepository) GetByUsername(ctx context.Context, username string) (
 	return toModelAdmin(admin), nil
 }
 
+// Save upserts the admin by username, only the role of an existing one changes.
+func (r *AdminsRepository) Save(ctx context.Context, admin model.Admin) error {
+	a := toMongoAdmin(admin)
+	update := bson.M{
+		"$set":         bson.M{"role": a.Role},
+		"$setOnInsert": bson.M{"_id": a.ID},
+	}
+
+	_, err := r.db.UpdateOne(ctx, bson.M{"username": a.Username}, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("error admins Save(): %w", err)
+	}
+	return nil
+}
+
 type mongoAdmin struct {
 	ID       primitive.ObjectID `bson:"_id,omitempty"`
 	Username string             `bson:"username"`
@@ -0,0 +1,143 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type APIKeysRepository struct {
+	db *mongo.Collection
+}
+
+func NewAPIKeysRepository(db *mongo.Database) *APIKeysRepository {
+	return &APIKeysRepository{db: db.Collection("api_keys")}
+}
+
+func (r *APIKeysRepository) Create(ctx context.Context, key model.APIKey) (string, error) {
+	doc, err := r.db.InsertOne(ctx, toMongoAPIKey(key))
+	if err != nil {
+		return "", fmt.Errorf("error api keys Create(): %w", err)
+	}
+
+	id, ok := doc.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return "", fmt.Errorf("error api keys Create(): %w", model.ErrInterfaceCast)
+	}
+	return id.Hex(), nil
+}
+
+func (r *APIKeysRepository) GetAll(ctx context.Context) ([]model.APIKey, error) {
+	cur, err := r.db.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("error api keys GetAll(): %w", err)
+	}
+
+	var keys []mongoAPIKey
+	if err := cur.All(ctx, &keys); err != nil {
+		return nil, fmt.Errorf("error api keys GetAll(): %w", err)
+	}
+
+	result := make([]model.APIKey, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, toModelAPIKey(k))
+	}
+	return result, nil
+}
+
+func (r *APIKeysRepository) GetByKey(ctx context.Context, key string) (model.APIKey, error) {
+	var apiKey mongoAPIKey
+
+	err := r.db.FindOne(ctx, bson.M{"key": key}).Decode(&apiKey)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return model.APIKey{}, fmt.Errorf("error api keys GetByKey(): %w", model.ErrAPIKeyNotFound)
+		}
+		return model.APIKey{}, fmt.Errorf("error api keys GetByKey(): %w", err)
+	}
+
+	return toModelAPIKey(apiKey), nil
+}
+
+func (r *APIKeysRepository) Revoke(ctx context.Context, id string) error {
+	_id, _ := primitive.ObjectIDFromHex(id)
+	update := bson.M{"$set": bson.M{"revoked": true}}
+
+	res, err := r.db.UpdateOne(ctx, bson.M{"_id": _id}, update)
+	if err != nil {
+		return fmt.Errorf("error api keys Revoke(): %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("error api keys Revoke(): %w", model.ErrAPIKeyNotFound)
+	}
+	return nil
+}
+
+func (r *APIKeysRepository) UpdateLastUsed(ctx context.Context, id string, lastUsedAt time.Time) error {
+	_id, _ := primitive.ObjectIDFromHex(id)
+	update := bson.M{"$set": bson.M{"last_used_at": lastUsedAt}}
+
+	if _, err := r.db.UpdateOne(ctx, bson.M{"_id": _id}, update); err != nil {
+		return fmt.Errorf("error api keys UpdateLastUsed(): %w", err)
+	}
+	return nil
+}
+
+type mongoAPIKey struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	Name        string             `bson:"name"`
+	Prefix      string             `bson:"prefix"`
+	Key         string             `bson:"key"`
+	Permissions []string           `bson:"permissions"`
+	CreatedBy   string             `bson:"created_by"`
+	CreatedAt   time.Time          `bson:"created_at"`
+	LastUsedAt  time.Time          `bson:"last_used_at"`
+	Revoked     bool               `bson:"revoked"`
+}
+
+func toMongoAPIKey(k model.APIKey) mongoAPIKey {
+	id, _ := primitive.ObjectIDFromHex(k.ID)
+
+	permissions := make([]string, 0, len(k.Permissions))
+	for _, p := range k.Permissions {
+		permissions = append(permissions, string(p))
+	}
+
+	return mongoAPIKey{
+		ID:          id,
+		Name:        k.Name,
+		Prefix:      k.Prefix,
+		Key:         k.Key,
+		Permissions: permissions,
+		CreatedBy:   k.CreatedBy,
+		CreatedAt:   k.CreatedAt,
+		LastUsedAt:  k.LastUsedAt,
+		Revoked:     k.Revoked,
+	}
+}
+
+func toModelAPIKey(k mongoAPIKey) model.APIKey {
+	permissions := make([]model.Permission, 0, len(k.Permissions))
+	for _, p := range k.Permissions {
+		permissions = append(permissions, model.Permission(p))
+	}
+
+	return model.APIKey{
+		ID:          k.ID.Hex(),
+		Name:        k.Name,
+		Prefix:      k.Prefix,
+		Key:         k.Key,
+		Permissions: permissions,
+		CreatedBy:   k.CreatedBy,
+		CreatedAt:   k.CreatedAt,
+		LastUsedAt:  k.LastUsedAt,
+		Revoked:     k.Revoked,
+	}
+}
@@ -15,14 +15,18 @@ import (
 )
 
 type CardsRepository struct {
-	staticCardsDB *mongo.Collection
-	cardsDB       *mongo.Collection
+	staticCardsDB  *mongo.Collection
+	staticVersions *mongo.Collection
+	cardsDB        *mongo.Collection
+	dailyIssues    *mongo.Collection
 }
 
 func NewCardsRepository(db *mongo.Database) *CardsRepository {
 	return &CardsRepository{
-		staticCardsDB: db.Collection("cards_static"),
-		cardsDB:       db.Collection("cards"),
+		staticCardsDB:  db.Collection("cards_static"),
+		staticVersions: db.Collection("cards_static_versions"),
+		cardsDB:        db.Collection("cards"),
+		dailyIssues:    db.Collection("daily_issues"),
 	}
 }
 
@@ -55,13 +59,53 @@ func (r *CardsRepository) Update(ctx context.Context, card model.Card) error {
 	return nil
 }
 
-func (r *CardsRepository) DeleteUsersPendingDailyCards(ctx context.Context, username string) error {
-	if _, err := r.cardsDB.DeleteMany(ctx, bson.M{"type": model.PoolDaily, "done": 0, "owner_username": username}); err != nil {
-		return fmt.Errorf("error cards DeletePendingDailyCards(): %w", err)
+func (r *CardsRepository) DeleteUsersPendingCards(ctx context.Context, username, pool string) error {
+	if _, err := r.cardsDB.DeleteMany(ctx, bson.M{"static.pool": pool, "done": 0, "owner_username": username}); err != nil {
+		return fmt.Errorf("error cards DeleteUsersPendingCards(): %w", err)
 	}
 	return nil
 }
 
+func (r *CardsRepository) AddDailyIssues(ctx context.Context, issues []model.DailyIssue) error {
+	if len(issues) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(issues))
+	for i, issue := range issues {
+		docs[i] = mongoDailyIssue(issue)
+	}
+	if _, err := r.dailyIssues.InsertMany(ctx, docs); err != nil {
+		return fmt.Errorf("error cards AddDailyIssues(): %w", err)
+	}
+	return nil
+}
+
+func (r *CardsRepository) GetDailyIssues(ctx context.Context, username string, since time.Time) ([]model.DailyIssue, error) {
+	query := bson.M{"username": username, "issued_at": bson.M{"$gte": since}}
+
+	cursor, err := r.dailyIssues.Find(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error cards GetDailyIssues(): %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var issues []mongoDailyIssue
+	if err := cursor.All(ctx, &issues); err != nil {
+		return nil, fmt.Errorf("error cards GetDailyIssues(): %w", err)
+	}
+
+	result := make([]model.DailyIssue, len(issues))
+	for i, issue := range issues {
+		result[i] = model.DailyIssue(issue)
+		// issues logged before weekly cards are all daily
+		if result[i].Pool == "" {
+			result[i].Pool = model.PoolDaily
+		}
+	}
+	return result, nil
+}
+
 func (r *CardsRepository) GetCardsByOwnerPool(ctx context.Context, ownerUsername, pool string) (model.Cards, error) {
 	var cards mongoCards
 
@@ -125,11 +169,111 @@ func (r *CardsRepository) CreateStatic(ctx context.Context, card model.CardStati
 	return nil
 }
 
-func (r *CardsRepository) DeleteStatic(ctx context.Context, ids []string) error {
+// CreateIndexes creates the indexes the repository relies on, it is safe to
+// call on every start.
+func (r *CardsRepository) CreateIndexes(ctx context.Context) error {
+	index := mongo.IndexModel{
+		Keys:    bson.D{{Key: "static_id", Value: 1}, {Key: "version", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	if _, err := r.staticVersions.Indexes().CreateOne(ctx, index); err != nil {
+		return fmt.Errorf("error cards CreateIndexes(): %w", err)
+	}
+	return nil
+}
+
+// UpdateStatic moves previous to the version history and replaces it with card.
+// Only one update of a version wins, the others get model.ErrCardChanged: the
+// history is unique by the static card and the version, and the card is only
+// replaced while it still has the version of previous.
+func (r *CardsRepository) UpdateStatic(ctx context.Context, previous, card model.CardStatic) error {
+	_id, _ := primitive.ObjectIDFromHex(card.ID)
+
+	version := mongoCardStaticVersion{
+		StaticID: _id,
+		Version:  previous.Version,
+		Static:   toMongoCardStatic(previous),
+	}
+	res, err := r.staticVersions.InsertOne(ctx, version)
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("error cards UpdateStatic(): %w", model.ErrCardChanged)
+	}
+	if err != nil {
+		return fmt.Errorf("error cards UpdateStatic(): %w", err)
+	}
+
+	// cards created before versioning have no version stored
+	versions := bson.A{previous.Version}
+	if previous.Version == 1 {
+		versions = append(versions, 0, nil)
+	}
+	filter := bson.M{"_id": _id, "version": bson.M{"$in": versions}}
+
+	replaced, err := r.staticCardsDB.ReplaceOne(ctx, filter, toMongoCardStatic(card))
+	if err == nil && replaced.MatchedCount == 1 {
+		return nil
+	}
+
+	// the card was not replaced, its history must not get the version
+	if _, derr := r.staticVersions.DeleteOne(ctx, bson.M{"_id": res.InsertedID}); derr != nil && err == nil {
+		err = derr
+	}
+	if err != nil {
+		return fmt.Errorf("error cards UpdateStatic(): %w", err)
+	}
+
+	n, err := r.staticCardsDB.CountDocuments(ctx, bson.M{"_id": _id})
+	if err != nil {
+		return fmt.Errorf("error cards UpdateStatic(): %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("error cards UpdateStatic(): %w", model.ErrNoSuchCard)
+	}
+	return fmt.Errorf("error cards UpdateStatic(): %w", model.ErrCardChanged)
+}
+
+// GetStaticVersions returns the previous versions of the static card.
+func (r *CardsRepository) GetStaticVersions(ctx context.Context, id string) (model.CardsStatic, error) {
+	var versions []mongoCardStaticVersion
+
+	_id, _ := primitive.ObjectIDFromHex(id)
+	queryOptions := options.Find()
+	queryOptions.SetSort(bson.D{bson.E{Key: "version", Value: 1}})
+
+	cursor, err := r.staticVersions.Find(ctx, bson.M{"static_id": _id}, queryOptions)
+	if err != nil {
+		return model.CardsStatic{}, fmt.Errorf("error cards GetStaticVersions(): %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &versions); err != nil {
+		return model.CardsStatic{}, fmt.Errorf("error cards GetStaticVersions(): %w", err)
+	}
+
+	cards := make(model.CardsStatic, len(versions))
+	for i := range versions {
+		cards[i] = toModelCardStatic(versions[i].Static)
+	}
+	return cards, nil
+}
+
+func (r *CardsRepository) ArchiveStatic(ctx context.Context, ids []string, archived bool, at time.Time) error {
 	ids_ := toPrimitives(ids)
+	update := bson.M{"$set": bson.M{"archived": archived, "archived_at": at}}
 
-	if _, err := r.staticCardsDB.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids_}}); err != nil {
-		return fmt.Errorf("error cards DeleteStatic(): %w", err)
+	if _, err := r.staticCardsDB.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids_}}, update); err != nil {
+		return fmt.Errorf("error cards ArchiveStatic(): %w", err)
+	}
+	return nil
+}
+
+// DeletePendingCardsByStatic removes the user cards issued from the static
+// cards which are not done yet.
+func (r *CardsRepository) DeletePendingCardsByStatic(ctx context.Context, staticIDs []string) error {
+	ids_ := toPrimitives(staticIDs)
+
+	if _, err := r.cardsDB.DeleteMany(ctx, bson.M{"static._id": bson.M{"$in": ids_}, "done": 0}); err != nil {
+		return fmt.Errorf("error cards DeletePendingCardsByStatic(): %w", err)
 	}
 	return nil
 }
@@ -137,7 +281,7 @@ func (r *CardsRepository) DeleteStatic(ctx context.Context, ids []string) error
 func (r *CardsRepository) GetStaticByPool(ctx context.Context, pool string) (model.CardsStatic, error) {
 	var cards mongoCardsStatic
 
-	query := bson.M{"pool": pool}
+	query := bson.M{"pool": pool, "archived": bson.M{"$ne": true}}
 	queryOptions := options.Find()
 	queryOptions.SetSort(bson.D{bson.E{Key: "chain_name", Value: 1}, {Key: "chain_order", Value: 1}})
 
@@ -155,6 +299,38 @@ func (r *CardsRepository) GetStaticByPool(ctx context.Context, pool string) (mod
 	return toModelCardsStatic(cards), nil
 }
 
+// CountStaticBySeason counts the season cards of the season, archived ones too.
+func (r *CardsRepository) CountStaticBySeason(ctx context.Context, season string) (int, error) {
+	n, err := r.staticCardsDB.CountDocuments(ctx, bson.M{"pool": model.PoolSeason, "season": season})
+	if err != nil {
+		return 0, fmt.Errorf("error cards CountStaticBySeason(): %w", err)
+	}
+	return int(n), nil
+}
+
+func (r *CardsRepository) GetStaticByExternalID(ctx context.Context, externalID string) (model.CardStatic, error) {
+	var card mongoCardStatic
+
+	err := r.staticCardsDB.FindOne(ctx, bson.M{"external_id": externalID}).Decode(&card)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return model.CardStatic{}, fmt.Errorf("error cards GetStaticByExternalID(): %w", model.ErrNoSuchCard)
+		}
+		return model.CardStatic{}, fmt.Errorf("error cards GetStaticByExternalID(): %w", err)
+	}
+
+	return toModelCardStatic(card), nil
+}
+
+// CountStaticByGoal counts the static cards with the goal, archived ones too.
+func (r *CardsRepository) CountStaticByGoal(ctx context.Context, goal string) (int, error) {
+	n, err := r.staticCardsDB.CountDocuments(ctx, bson.M{"goal": goal})
+	if err != nil {
+		return 0, fmt.Errorf("error cards CountStaticByGoal(): %w", err)
+	}
+	return int(n), nil
+}
+
 func (r *CardsRepository) GetStatic(ctx context.Context, ids []string) (model.CardsStatic, error) {
 	var cards mongoCardsStatic
 
@@ -184,6 +360,15 @@ func (r *CardsRepository) GetStatic(ctx context.Context, ids []string) (model.Ca
 	return toModelCardsStatic(cards), nil
 }
 
+type mongoDailyIssue struct {
+	Username string    `bson:"username"`
+	Pool     string    `bson:"pool"`
+	StaticID string    `bson:"static_id"`
+	Goal     string    `bson:"goal"`
+	IssuedAt time.Time `bson:"issued_at"`
+	Seed     int64     `bson:"seed"`
+}
+
 type mongoCard struct {
 	ID            primitive.ObjectID `bson:"_id,omitempty"`
 	OwnerUsername string             `bson:"owner_username"`
@@ -193,23 +378,47 @@ type mongoCard struct {
 	IsViewed      bool               `bson:"is_viewed"`
 	History       []int              `bson:"history,omitempty"`
 	OptDoneNum    int                `bson:"opt_done_num"`
+	Streak        *int               `bson:"streak,omitempty"`
+	LastDoneAt    time.Time          `bson:"last_done_at,omitempty"`
+	CheckedSteps  []string           `bson:"checked_steps,omitempty"`
+	CompletedAt   time.Time          `bson:"completed_at,omitempty"`
+	Seed          int64              `bson:"seed,omitempty"`
+	APIKeyID      string             `bson:"api_key_id,omitempty"`
 }
 
 type mongoCardStatic struct {
-	ID               primitive.ObjectID `bson:"_id,omitempty"`
-	Title            string             `bson:"title"`
-	ShortDescription string             `bson:"short_description"`
-	LongDescription  string             `bson:"long_description"`
-	Goal             string             `bson:"goal"`
-	CreatedAt        time.Time          `bson:"created_at"`
-	Type             string             `bson:"type"`
-	Pool             string             `bson:"pool"`
-	BackgroundURL    string             `bson:"background_url"`
-	ChainName        *string            `bson:"chain_name,omitempty"`
-	ChainOrder       *int               `bson:"chain_order,omitempty"`
-	OrdSettings      *mongoOrdSettings  `bson:"ordinary_settings,omitempty"`
-	PrgSettings      *mongoPrgSettings  `bson:"progress_settings,omitempty"`
-	OptSettings      *mongoOptSettings  `bson:"options_settings,omitempty"`
+	ID                primitive.ObjectID      `bson:"_id,omitempty"`
+	Version           int                     `bson:"version"`
+	ExternalID        string                  `bson:"external_id,omitempty"`
+	Title             string                  `bson:"title"`
+	ShortDescription  string                  `bson:"short_description"`
+	LongDescription   string                  `bson:"long_description"`
+	Goal              string                  `bson:"goal"`
+	CreatedAt         time.Time               `bson:"created_at"`
+	UpdatedAt         time.Time               `bson:"updated_at"`
+	Archived          bool                    `bson:"archived"`
+	ArchivedAt        time.Time               `bson:"archived_at"`
+	Type              string                  `bson:"type"`
+	Pool              string                  `bson:"pool"`
+	BackgroundURL     string                  `bson:"background_url"`
+	ChainName         *string                 `bson:"chain_name,omitempty"`
+	ChainOrder        *int                    `bson:"chain_order,omitempty"`
+	OrdSettings       *mongoOrdSettings       `bson:"ordinary_settings,omitempty"`
+	PrgSettings       *mongoPrgSettings       `bson:"progress_settings,omitempty"`
+	OptSettings       *mongoOptSettings       `bson:"options_settings,omitempty"`
+	StreakSettings    *mongoStreakSettings    `bson:"streak_settings,omitempty"`
+	ChecklistSettings *mongoChecklistSettings `bson:"checklist_settings,omitempty"`
+	Availability      *mongoAvailability      `bson:"availability,omitempty"`
+	Repeat            *mongoRepeatSettings    `bson:"repeat_settings,omitempty"`
+	Weight            int                     `bson:"weight,omitempty"`
+	Season            string                  `bson:"season,omitempty"`
+}
+
+type mongoCardStaticVersion struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty"`
+	StaticID primitive.ObjectID `bson:"static_id"`
+	Version  int                `bson:"version"`
+	Static   mongoCardStatic    `bson:"static"`
 }
 
 type mongoOrdSettings struct {
@@ -226,6 +435,40 @@ type mongoOptSettings struct {
 	Options []float32    `bson:"options"`
 }
 
+type mongoStreakSettings struct {
+	Days       int              `bson:"days"`
+	GraceDays  int              `bson:"grace_days"`
+	Milestones []mongoMilestone `bson:"milestones"`
+}
+
+type mongoMilestone struct {
+	Day   int        `bson:"day"`
+	Award mongoAward `bson:"award"`
+}
+
+type mongoChecklistSettings struct {
+	Steps []mongoStep `bson:"steps"`
+	Award mongoAward  `bson:"award"`
+}
+
+type mongoStep struct {
+	ID    string `bson:"id"`
+	Title string `bson:"title"`
+}
+
+type mongoAvailability struct {
+	StartsAt time.Time      `bson:"starts_at,omitempty"`
+	EndsAt   time.Time      `bson:"ends_at,omitempty"`
+	Weekdays []time.Weekday `bson:"weekdays,omitempty"`
+	FromHour int            `bson:"from_hour"`
+	ToHour   int            `bson:"to_hour"`
+}
+
+type mongoRepeatSettings struct {
+	MaxCompletions int           `bson:"max_completions"`
+	Cooldown       time.Duration `bson:"cooldown"`
+}
+
 type mongoAward struct {
 	XPoints       int    `bson:"XPoints"`
 	Prize         string `bson:"prize"`
@@ -257,6 +500,11 @@ func toMongoCard(c model.Card) mongoCard {
 		progress = &c.Progress
 	}
 
+	var streak *int
+	if c.Static.Type == model.TypeStreak {
+		streak = &c.Streak
+	}
+
 	id, _ := primitive.ObjectIDFromHex(c.ID)
 	mongoCard := mongoCard{
 		ID:            id,
@@ -267,6 +515,12 @@ func toMongoCard(c model.Card) mongoCard {
 		Progress:      progress,
 		History:       c.History,
 		OptDoneNum:    c.OptDoneNum,
+		Streak:        streak,
+		LastDoneAt:    c.LastDoneAt,
+		CheckedSteps:  c.CheckedSteps,
+		CompletedAt:   c.CompletedAt,
+		Seed:          c.Seed,
+		APIKeyID:      c.APIKeyID,
 	}
 	return mongoCard
 }
@@ -277,6 +531,11 @@ func toModelCard(c mongoCard) model.Card {
 		progress = *c.Progress
 	}
 
+	var streak int
+	if c.Streak != nil {
+		streak = *c.Streak
+	}
+
 	mongoCard := model.Card{
 		ID:            c.ID.Hex(),
 		OwnerUsername: c.OwnerUsername,
@@ -286,6 +545,12 @@ func toModelCard(c mongoCard) model.Card {
 		Progress:      progress,
 		History:       c.History,
 		OptDoneNum:    c.OptDoneNum,
+		Streak:        streak,
+		LastDoneAt:    c.LastDoneAt,
+		CheckedSteps:  c.CheckedSteps,
+		CompletedAt:   c.CompletedAt,
+		Seed:          c.Seed,
+		APIKeyID:      c.APIKeyID,
 	}
 	return mongoCard
 }
@@ -301,6 +566,8 @@ func toMongoCardStatic(c model.CardStatic) mongoCardStatic {
 	var ordsettings *mongoOrdSettings
 	var prgsettings *mongoPrgSettings
 	var optsettings *mongoOptSettings
+	var streakSettings *mongoStreakSettings
+	var checklistSettings *mongoChecklistSettings
 	if c.Type == model.TypeOrdinary {
 		ordsettings = &mongoOrdSettings{
 			Award: mongoAward(c.OrdSettings.Award),
@@ -315,24 +582,54 @@ func toMongoCardStatic(c model.CardStatic) mongoCardStatic {
 			Awards:  toMongoAwards(c.OptSettings.Awards),
 			Options: c.OptSettings.Options,
 		}
+	} else if c.Type == model.TypeStreak {
+		milestones := make([]mongoMilestone, len(c.StreakSettings.Milestones))
+		for i, m := range c.StreakSettings.Milestones {
+			milestones[i] = mongoMilestone{Day: m.Day, Award: mongoAward(m.Award)}
+		}
+		streakSettings = &mongoStreakSettings{
+			Days:       c.StreakSettings.Days,
+			GraceDays:  c.StreakSettings.GraceDays,
+			Milestones: milestones,
+		}
+	} else if c.Type == model.TypeChecklist {
+		steps := make([]mongoStep, len(c.ChecklistSettings.Steps))
+		for i, step := range c.ChecklistSettings.Steps {
+			steps[i] = mongoStep(step)
+		}
+		checklistSettings = &mongoChecklistSettings{
+			Steps: steps,
+			Award: mongoAward(c.ChecklistSettings.Award),
+		}
 	}
 
 	id, _ := primitive.ObjectIDFromHex(c.ID)
 	mongoStaticCard := mongoCardStatic{
-		ID:               id,
-		Title:            c.Title,
-		ShortDescription: c.ShortDescription,
-		LongDescription:  c.LongDescription,
-		Goal:             c.Goal,
-		CreatedAt:        c.CreatedAt,
-		Type:             c.Type,
-		Pool:             c.Pool,
-		ChainName:        chainName,
-		BackgroundURL:    c.BackgroundURL,
-		ChainOrder:       chainOrder,
-		OrdSettings:      ordsettings,
-		PrgSettings:      prgsettings,
-		OptSettings:      optsettings,
+		ID:                id,
+		Version:           c.Version,
+		ExternalID:        c.ExternalID,
+		Title:             c.Title,
+		ShortDescription:  c.ShortDescription,
+		LongDescription:   c.LongDescription,
+		Goal:              c.Goal,
+		CreatedAt:         c.CreatedAt,
+		UpdatedAt:         c.UpdatedAt,
+		Archived:          c.Archived,
+		ArchivedAt:        c.ArchivedAt,
+		Type:              c.Type,
+		Pool:              c.Pool,
+		ChainName:         chainName,
+		BackgroundURL:     c.BackgroundURL,
+		ChainOrder:        chainOrder,
+		OrdSettings:       ordsettings,
+		PrgSettings:       prgsettings,
+		OptSettings:       optsettings,
+		StreakSettings:    streakSettings,
+		ChecklistSettings: checklistSettings,
+		Availability:      (*mongoAvailability)(c.Availability),
+		Repeat:            toMongoRepeatSettings(c.Repeat),
+		Weight:            c.Weight,
+		Season:            c.Season,
 	}
 	return mongoStaticCard
 }
@@ -348,6 +645,8 @@ func toModelCardStatic(c mongoCardStatic) model.CardStatic {
 	var ordsettings *model.OrdSettings
 	var prgsettings *model.PrgSettings
 	var optsettings *model.OptSettings
+	var streakSettings *model.StreakSettings
+	var checklistSettings *model.ChecklistSettings
 	if c.Type == model.TypeOrdinary {
 		ordsettings = &model.OrdSettings{
 			Award: model.Award(c.OrdSettings.Award),
@@ -362,23 +661,53 @@ func toModelCardStatic(c mongoCardStatic) model.CardStatic {
 			Awards:  toModelAwards(c.OptSettings.Awards),
 			Options: c.OptSettings.Options,
 		}
+	} else if c.Type == model.TypeStreak {
+		milestones := make([]model.Milestone, len(c.StreakSettings.Milestones))
+		for i, m := range c.StreakSettings.Milestones {
+			milestones[i] = model.Milestone{Day: m.Day, Award: model.Award(m.Award)}
+		}
+		streakSettings = &model.StreakSettings{
+			Days:       c.StreakSettings.Days,
+			GraceDays:  c.StreakSettings.GraceDays,
+			Milestones: milestones,
+		}
+	} else if c.Type == model.TypeChecklist {
+		steps := make([]model.Step, len(c.ChecklistSettings.Steps))
+		for i, step := range c.ChecklistSettings.Steps {
+			steps[i] = model.Step(step)
+		}
+		checklistSettings = &model.ChecklistSettings{
+			Steps: steps,
+			Award: model.Award(c.ChecklistSettings.Award),
+		}
 	}
 
 	mongoStaticCard := model.CardStatic{
-		ID:               c.ID.Hex(),
-		Title:            c.Title,
-		ShortDescription: c.ShortDescription,
-		LongDescription:  c.LongDescription,
-		Goal:             c.Goal,
-		CreatedAt:        c.CreatedAt,
-		Type:             c.Type,
-		Pool:             c.Pool,
-		ChainName:        chainName,
-		BackgroundURL:    c.BackgroundURL,
-		ChainOrder:       chainOrder,
-		OrdSettings:      ordsettings,
-		PrgSettings:      prgsettings,
-		OptSettings:      optsettings,
+		ID:                c.ID.Hex(),
+		Version:           c.Version,
+		ExternalID:        c.ExternalID,
+		Title:             c.Title,
+		ShortDescription:  c.ShortDescription,
+		LongDescription:   c.LongDescription,
+		Goal:              c.Goal,
+		CreatedAt:         c.CreatedAt,
+		UpdatedAt:         c.UpdatedAt,
+		Archived:          c.Archived,
+		ArchivedAt:        c.ArchivedAt,
+		Type:              c.Type,
+		Pool:              c.Pool,
+		ChainName:         chainName,
+		BackgroundURL:     c.BackgroundURL,
+		ChainOrder:        chainOrder,
+		OrdSettings:       ordsettings,
+		PrgSettings:       prgsettings,
+		OptSettings:       optsettings,
+		StreakSettings:    streakSettings,
+		ChecklistSettings: checklistSettings,
+		Availability:      (*model.Availability)(c.Availability),
+		Repeat:            toModelRepeatSettings(c.Repeat),
+		Weight:            c.Weight,
+		Season:            c.Season,
 	}
 	return mongoStaticCard
 }
@@ -398,3 +727,17 @@ func toModelCardsStatic(c mongoCardsStatic) model.CardsStatic {
 	}
 	return cards
 }
+
+func toMongoRepeatSettings(s *model.RepeatSettings) *mongoRepeatSettings {
+	if s == nil {
+		return nil
+	}
+	return &mongoRepeatSettings{MaxCompletions: s.MaxCompletions, Cooldown: s.Cooldown.Duration}
+}
+
+func toModelRepeatSettings(s *mongoRepeatSettings) *model.RepeatSettings {
+	if s == nil {
+		return nil
+	}
+	return &model.RepeatSettings{MaxCompletions: s.MaxCompletions, Cooldown: model.Duration{Duration: s.Cooldown}}
+}
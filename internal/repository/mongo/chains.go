@@ -0,0 +1,67 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type ChainsRepository struct {
+	db *mongo.Collection
+}
+
+func NewChainsRepository(db *mongo.Database) *ChainsRepository {
+	return &ChainsRepository{db: db.Collection("chains")}
+}
+
+func (r *ChainsRepository) GetAll(ctx context.Context) (model.Chains, error) {
+	queryOptions := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+
+	cur, err := r.db.Find(ctx, bson.M{}, queryOptions)
+	if err != nil {
+		return nil, fmt.Errorf("error chains GetAll(): %w", err)
+	}
+
+	var chains []mongoChain
+	if err := cur.All(ctx, &chains); err != nil {
+		return nil, fmt.Errorf("error chains GetAll(): %w", err)
+	}
+
+	result := make(model.Chains, 0, len(chains))
+	for _, c := range chains {
+		result = append(result, model.Chain(c))
+	}
+	return result, nil
+}
+
+func (r *ChainsRepository) Set(ctx context.Context, chain model.Chain) error {
+	queryOptions := options.Replace().SetUpsert(true)
+
+	_, err := r.db.ReplaceOne(ctx, bson.M{"name": chain.Name}, mongoChain(chain), queryOptions)
+	if err != nil {
+		return fmt.Errorf("error chains Set(): %w", err)
+	}
+	return nil
+}
+
+func (r *ChainsRepository) Delete(ctx context.Context, name string) error {
+	res, err := r.db.DeleteOne(ctx, bson.M{"name": name})
+	if err != nil {
+		return fmt.Errorf("error chains Delete(): %w", err)
+	}
+	if res.DeletedCount == 0 {
+		return fmt.Errorf("error chains Delete(): %w", model.ErrChainNotFound)
+	}
+	return nil
+}
+
+type mongoChain struct {
+	Name     string   `bson:"name"`
+	Requires []string `bson:"requires"`
+	MinLevel int      `bson:"min_level"`
+}
@@ -45,34 +45,77 @@ func (r *CredentialsRepository) GetByUsername(ctx context.Context, username stri
 	return toModelCredentials(credentials), nil
 }
 
-func (r *CredentialsRepository) GetByCredentials(ctx context.Context, username, password string) (model.Credentials, error) {
+func (r *CredentialsRepository) GetByIdentity(ctx context.Context, issuer, subject string) (model.Credentials, error) {
 	var credentials mongoCredentials
 
-	err := r.db.FindOne(ctx, bson.M{"username": username, "password": password}).Decode(&credentials)
+	err := r.db.FindOne(ctx, bson.M{"issuer": issuer, "subject": subject}).Decode(&credentials)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
-			return model.Credentials{}, model.ErrUserNotFound
+			return model.Credentials{}, fmt.Errorf("error credentials GetByIdentity(): %w", model.ErrUserNotFound)
 		}
-		return model.Credentials{}, fmt.Errorf("error credentials GetByCredentials(): %w", err)
+		return model.Credentials{}, fmt.Errorf("error credentials GetByIdentity(): %w", err)
 	}
 
 	return toModelCredentials(credentials), nil
 }
 
+func (r *CredentialsRepository) UpdatePassword(ctx context.Context, id, password string) error {
+	_id, _ := primitive.ObjectIDFromHex(id)
+	update := bson.M{"$set": bson.M{"password": password}}
+
+	if _, err := r.db.UpdateOne(ctx, bson.M{"_id": _id}, update); err != nil {
+		return fmt.Errorf("error credentials UpdatePassword(): %w", err)
+	}
+	return nil
+}
+
+func (r *CredentialsRepository) UpdateRole(ctx context.Context, id string, role model.Role) error {
+	_id, _ := primitive.ObjectIDFromHex(id)
+	update := bson.M{"$set": bson.M{"role": int(role)}}
+
+	if _, err := r.db.UpdateOne(ctx, bson.M{"_id": _id}, update); err != nil {
+		return fmt.Errorf("error credentials UpdateRole(): %w", err)
+	}
+	return nil
+}
+
+func (r *CredentialsRepository) UpdateSecondFactor(ctx context.Context, id string, factor model.SecondFactor) error {
+	_id, _ := primitive.ObjectIDFromHex(id)
+	update := bson.M{"$set": bson.M{"second_factor": toMongoSecondFactor(factor)}}
+
+	if _, err := r.db.UpdateOne(ctx, bson.M{"_id": _id}, update); err != nil {
+		return fmt.Errorf("error credentials UpdateSecondFactor(): %w", err)
+	}
+	return nil
+}
+
 type mongoCredentials struct {
-	ID       primitive.ObjectID `bson:"_id,omitempty"`
-	Username string             `bson:"username"`
-	Role     int                `bson:"role"`
-	Password string             `bson:"password"`
+	ID           primitive.ObjectID `bson:"_id,omitempty"`
+	Username     string             `bson:"username"`
+	Role         int                `bson:"role"`
+	Password     string             `bson:"password"`
+	Issuer       string             `bson:"issuer"`
+	Subject      string             `bson:"subject,omitempty"`
+	SecondFactor mongoSecondFactor  `bson:"second_factor"`
+}
+
+type mongoSecondFactor struct {
+	Secret        string   `bson:"secret"`
+	Enabled       bool     `bson:"enabled"`
+	RecoveryCodes []string `bson:"recovery_codes"`
+	LastStep      int64    `bson:"last_step"`
 }
 
 func toMongoCredentials(c model.Credentials) mongoCredentials {
 	id, _ := primitive.ObjectIDFromHex(c.ID)
 	creds := mongoCredentials{
-		ID:       id,
-		Username: c.Username,
-		Role:     int(c.Role),
-		Password: c.Password,
+		ID:           id,
+		Username:     c.Username,
+		Role:         int(c.Role),
+		Password:     c.Password,
+		Issuer:       c.Issuer,
+		Subject:      c.Subject,
+		SecondFactor: toMongoSecondFactor(c.SecondFactor),
 	}
 	return creds
 }
@@ -84,7 +127,28 @@ func toModelCredentials(c mongoCredentials) model.Credentials {
 			Username: c.Username,
 			Role:     model.Role(c.Role),
 		},
-		Password: c.Password,
+		Password:     c.Password,
+		Issuer:       c.Issuer,
+		Subject:      c.Subject,
+		SecondFactor: toModelSecondFactor(c.SecondFactor),
 	}
 	return creds
 }
+
+func toMongoSecondFactor(f model.SecondFactor) mongoSecondFactor {
+	return mongoSecondFactor{
+		Secret:        f.Secret,
+		Enabled:       f.Enabled,
+		RecoveryCodes: f.RecoveryCodes,
+		LastStep:      f.LastStep,
+	}
+}
+
+func toModelSecondFactor(f mongoSecondFactor) model.SecondFactor {
+	return model.SecondFactor{
+		Secret:        f.Secret,
+		Enabled:       f.Enabled,
+		RecoveryCodes: f.RecoveryCodes,
+		LastStep:      f.LastStep,
+	}
+}
@@ -0,0 +1,120 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type GoalsRepository struct {
+	db *mongo.Collection
+}
+
+func NewGoalsRepository(db *mongo.Database) *GoalsRepository {
+	return &GoalsRepository{db: db.Collection("goals")}
+}
+
+func (r *GoalsRepository) Create(ctx context.Context, goal model.Goal) (string, error) {
+	doc, err := r.db.InsertOne(ctx, toMongoGoal(goal))
+	if err != nil {
+		return "", fmt.Errorf("error goals Create(): %w", err)
+	}
+
+	id, ok := doc.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return "", fmt.Errorf("error goals Create(): %w", model.ErrInterfaceCast)
+	}
+	return id.Hex(), nil
+}
+
+func (r *GoalsRepository) GetAll(ctx context.Context) (model.Goals, error) {
+	queryOptions := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+
+	cur, err := r.db.Find(ctx, bson.M{}, queryOptions)
+	if err != nil {
+		return nil, fmt.Errorf("error goals GetAll(): %w", err)
+	}
+
+	var goals []mongoGoal
+	if err := cur.All(ctx, &goals); err != nil {
+		return nil, fmt.Errorf("error goals GetAll(): %w", err)
+	}
+
+	result := make(model.Goals, 0, len(goals))
+	for _, g := range goals {
+		result = append(result, toModelGoal(g))
+	}
+	return result, nil
+}
+
+func (r *GoalsRepository) GetByName(ctx context.Context, name string) (model.Goal, error) {
+	var goal mongoGoal
+
+	err := r.db.FindOne(ctx, bson.M{"name": name}).Decode(&goal)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return model.Goal{}, fmt.Errorf("error goals GetByName(): %w", model.ErrGoalNotFound)
+		}
+		return model.Goal{}, fmt.Errorf("error goals GetByName(): %w", err)
+	}
+
+	return toModelGoal(goal), nil
+}
+
+func (r *GoalsRepository) UpdateTitle(ctx context.Context, name, title string) error {
+	update := bson.M{"$set": bson.M{"title": title}}
+
+	res, err := r.db.UpdateOne(ctx, bson.M{"name": name}, update)
+	if err != nil {
+		return fmt.Errorf("error goals UpdateTitle(): %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("error goals UpdateTitle(): %w", model.ErrGoalNotFound)
+	}
+	return nil
+}
+
+func (r *GoalsRepository) Delete(ctx context.Context, name string) error {
+	res, err := r.db.DeleteOne(ctx, bson.M{"name": name})
+	if err != nil {
+		return fmt.Errorf("error goals Delete(): %w", err)
+	}
+	if res.DeletedCount == 0 {
+		return fmt.Errorf("error goals Delete(): %w", model.ErrGoalNotFound)
+	}
+	return nil
+}
+
+type mongoGoal struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Name      string             `bson:"name"`
+	Title     string             `bson:"title"`
+	CreatedAt time.Time          `bson:"created_at"`
+}
+
+func toMongoGoal(g model.Goal) mongoGoal {
+	id, _ := primitive.ObjectIDFromHex(g.ID)
+	return mongoGoal{
+		ID:        id,
+		Name:      g.Name,
+		Title:     g.Title,
+		CreatedAt: g.CreatedAt,
+	}
+}
+
+func toModelGoal(g mongoGoal) model.Goal {
+	return model.Goal{
+		ID:        g.ID.Hex(),
+		Name:      g.Name,
+		Title:     g.Title,
+		CreatedAt: g.CreatedAt,
+	}
+}
@@ -0,0 +1,79 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type LoginAttemptsRepository struct {
+	db *mongo.Collection
+}
+
+func NewLoginAttemptsRepository(db *mongo.Database) *LoginAttemptsRepository {
+	return &LoginAttemptsRepository{db: db.Collection("login_attempts")}
+}
+
+// Get returns empty attempts for a key without failures.
+func (r *LoginAttemptsRepository) Get(ctx context.Context, key string) (model.LoginAttempts, error) {
+	var attempts mongoLoginAttempts
+
+	err := r.db.FindOne(ctx, bson.M{"_id": key}).Decode(&attempts)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return model.LoginAttempts{Key: key}, nil
+		}
+		return model.LoginAttempts{}, fmt.Errorf("error login attempts Get(): %w", err)
+	}
+
+	return model.LoginAttempts(attempts), nil
+}
+
+// AddFailure increments the failures with one pipeline update, so the count is
+// reset and incremented atomically.
+func (r *LoginAttemptsRepository) AddFailure(ctx context.Context, key string, now, since time.Time) (model.LoginAttempts, error) {
+	var attempts mongoLoginAttempts
+
+	failures := bson.M{"$cond": bson.A{
+		bson.M{"$gte": bson.A{"$last_failure", since}},
+		bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$failures", 0}}, 1}},
+		1,
+	}}
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{"failures": failures, "last_failure": now}}}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	err := r.db.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(&attempts)
+	if err != nil {
+		return model.LoginAttempts{}, fmt.Errorf("error login attempts AddFailure(): %w", err)
+	}
+	return model.LoginAttempts(attempts), nil
+}
+
+func (r *LoginAttemptsRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	_, err := r.db.UpdateOne(ctx, bson.M{"_id": key}, bson.M{"$max": bson.M{"locked_until": until}})
+	if err != nil {
+		return fmt.Errorf("error login attempts Lock(): %w", err)
+	}
+	return nil
+}
+
+func (r *LoginAttemptsRepository) Delete(ctx context.Context, key string) error {
+	if _, err := r.db.DeleteOne(ctx, bson.M{"_id": key}); err != nil {
+		return fmt.Errorf("error login attempts Delete(): %w", err)
+	}
+	return nil
+}
+
+type mongoLoginAttempts struct {
+	Key         string    `bson:"_id"`
+	Failures    int       `bson:"failures"`
+	LastFailure time.Time `bson:"last_failure"`
+	LockedUntil time.Time `bson:"locked_until"`
+}
@@ -0,0 +1,87 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type PasswordResetsRepository struct {
+	db *mongo.Collection
+}
+
+func NewPasswordResetsRepository(db *mongo.Database) *PasswordResetsRepository {
+	return &PasswordResetsRepository{db: db.Collection("password_resets")}
+}
+
+func (r *PasswordResetsRepository) Create(ctx context.Context, reset model.PasswordReset) error {
+	if _, err := r.db.InsertOne(ctx, toMongoPasswordReset(reset)); err != nil {
+		return fmt.Errorf("error password resets Create(): %w", err)
+	}
+	return nil
+}
+
+func (r *PasswordResetsRepository) GetByToken(ctx context.Context, token string) (model.PasswordReset, error) {
+	var reset mongoPasswordReset
+
+	err := r.db.FindOne(ctx, bson.M{"token": token}).Decode(&reset)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return model.PasswordReset{}, fmt.Errorf("error password resets GetByToken(): %w", model.ErrInvalidResetToken)
+		}
+		return model.PasswordReset{}, fmt.Errorf("error password resets GetByToken(): %w", err)
+	}
+
+	return toModelPasswordReset(reset), nil
+}
+
+func (r *PasswordResetsRepository) Update(ctx context.Context, reset model.PasswordReset) error {
+	_id, _ := primitive.ObjectIDFromHex(reset.ID)
+
+	if _, err := r.db.ReplaceOne(ctx, bson.M{"_id": _id}, toMongoPasswordReset(reset)); err != nil {
+		return fmt.Errorf("error password resets Update(): %w", err)
+	}
+	return nil
+}
+
+type mongoPasswordReset struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Username  string             `bson:"username"`
+	Token     string             `bson:"token"`
+	CreatedBy string             `bson:"created_by"`
+	CreatedAt time.Time          `bson:"created_at"`
+	ExpiresAt time.Time          `bson:"expires_at"`
+	Used      bool               `bson:"used"`
+}
+
+func toMongoPasswordReset(r model.PasswordReset) mongoPasswordReset {
+	id, _ := primitive.ObjectIDFromHex(r.ID)
+	return mongoPasswordReset{
+		ID:        id,
+		Username:  r.Username,
+		Token:     r.Token,
+		CreatedBy: r.CreatedBy,
+		CreatedAt: r.CreatedAt,
+		ExpiresAt: r.ExpiresAt,
+		Used:      r.Used,
+	}
+}
+
+func toModelPasswordReset(r mongoPasswordReset) model.PasswordReset {
+	return model.PasswordReset{
+		ID:        r.ID.Hex(),
+		Username:  r.Username,
+		Token:     r.Token,
+		CreatedBy: r.CreatedBy,
+		CreatedAt: r.CreatedAt,
+		ExpiresAt: r.ExpiresAt,
+		Used:      r.Used,
+	}
+}
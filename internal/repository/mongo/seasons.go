@@ -0,0 +1,68 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type SeasonsRepository struct {
+	db *mongo.Collection
+}
+
+func NewSeasonsRepository(db *mongo.Database) *SeasonsRepository {
+	return &SeasonsRepository{db: db.Collection("seasons")}
+}
+
+func (r *SeasonsRepository) GetAll(ctx context.Context) (model.Seasons, error) {
+	queryOptions := options.Find().SetSort(bson.D{{Key: "starts_at", Value: 1}, {Key: "name", Value: 1}})
+
+	cur, err := r.db.Find(ctx, bson.M{}, queryOptions)
+	if err != nil {
+		return nil, fmt.Errorf("error seasons GetAll(): %w", err)
+	}
+
+	var seasons []mongoSeason
+	if err := cur.All(ctx, &seasons); err != nil {
+		return nil, fmt.Errorf("error seasons GetAll(): %w", err)
+	}
+
+	result := make(model.Seasons, 0, len(seasons))
+	for _, s := range seasons {
+		result = append(result, model.Season(s))
+	}
+	return result, nil
+}
+
+func (r *SeasonsRepository) Set(ctx context.Context, season model.Season) error {
+	queryOptions := options.Replace().SetUpsert(true)
+
+	_, err := r.db.ReplaceOne(ctx, bson.M{"name": season.Name}, mongoSeason(season), queryOptions)
+	if err != nil {
+		return fmt.Errorf("error seasons Set(): %w", err)
+	}
+	return nil
+}
+
+func (r *SeasonsRepository) Delete(ctx context.Context, name string) error {
+	res, err := r.db.DeleteOne(ctx, bson.M{"name": name})
+	if err != nil {
+		return fmt.Errorf("error seasons Delete(): %w", err)
+	}
+	if res.DeletedCount == 0 {
+		return fmt.Errorf("error seasons Delete(): %w", model.ErrSeasonNotFound)
+	}
+	return nil
+}
+
+type mongoSeason struct {
+	Name     string    `bson:"name"`
+	StartsAt time.Time `bson:"starts_at"`
+	EndsAt   time.Time `bson:"ends_at"`
+}
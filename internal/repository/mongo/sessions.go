@@ -0,0 +1,109 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type SessionsRepository struct {
+	db *mongo.Collection
+}
+
+func NewSessionsRepository(db *mongo.Database) *SessionsRepository {
+	return &SessionsRepository{db: db.Collection("sessions")}
+}
+
+func (r *SessionsRepository) Create(ctx context.Context, session model.Session) (string, error) {
+	doc, err := r.db.InsertOne(ctx, toMongoSession(session))
+	if err != nil {
+		return "", fmt.Errorf("error sessions Create(): %w", err)
+	}
+
+	id, ok := doc.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return "", fmt.Errorf("error sessions Create(): %w", model.ErrInterfaceCast)
+	}
+	return id.Hex(), nil
+}
+
+func (r *SessionsRepository) Get(ctx context.Context, id string) (model.Session, error) {
+	_id, _ := primitive.ObjectIDFromHex(id)
+	return r.get(ctx, bson.M{"_id": _id})
+}
+
+func (r *SessionsRepository) GetByRefreshToken(ctx context.Context, refreshToken string) (model.Session, error) {
+	return r.get(ctx, bson.M{"refresh_token": refreshToken})
+}
+
+func (r *SessionsRepository) Update(ctx context.Context, session model.Session) error {
+	_id, _ := primitive.ObjectIDFromHex(session.ID)
+
+	if _, err := r.db.ReplaceOne(ctx, bson.M{"_id": _id}, toMongoSession(session)); err != nil {
+		return fmt.Errorf("error sessions Update(): %w", err)
+	}
+	return nil
+}
+
+func (r *SessionsRepository) RevokeByUsername(ctx context.Context, username string) error {
+	filter := bson.M{"username": username, "revoked": false}
+	update := bson.M{"$set": bson.M{"revoked": true}}
+
+	if _, err := r.db.UpdateMany(ctx, filter, update); err != nil {
+		return fmt.Errorf("error sessions RevokeByUsername(): %w", err)
+	}
+	return nil
+}
+
+func (r *SessionsRepository) get(ctx context.Context, filter bson.M) (model.Session, error) {
+	var session mongoSession
+
+	err := r.db.FindOne(ctx, filter).Decode(&session)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return model.Session{}, fmt.Errorf("error sessions get(): %w", model.ErrSessionNotFound)
+		}
+		return model.Session{}, fmt.Errorf("error sessions get(): %w", err)
+	}
+
+	return toModelSession(session), nil
+}
+
+type mongoSession struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"`
+	Username     string             `bson:"username"`
+	RefreshToken string             `bson:"refresh_token"`
+	CreatedAt    time.Time          `bson:"created_at"`
+	ExpiresAt    time.Time          `bson:"expires_at"`
+	Revoked      bool               `bson:"revoked"`
+}
+
+func toMongoSession(s model.Session) mongoSession {
+	id, _ := primitive.ObjectIDFromHex(s.ID)
+	return mongoSession{
+		ID:           id,
+		Username:     s.Username,
+		RefreshToken: s.RefreshToken,
+		CreatedAt:    s.CreatedAt,
+		ExpiresAt:    s.ExpiresAt,
+		Revoked:      s.Revoked,
+	}
+}
+
+func toModelSession(s mongoSession) model.Session {
+	return model.Session{
+		ID:           s.ID.Hex(),
+		Username:     s.Username,
+		RefreshToken: s.RefreshToken,
+		CreatedAt:    s.CreatedAt,
+		ExpiresAt:    s.ExpiresAt,
+		Revoked:      s.Revoked,
+	}
+}
@@ -71,29 +71,33 @@ func (r *UsersRepository) Update(ctx context.Context, user model.User) error {
 }
 
 type mongoUser struct {
-	ID                   primitive.ObjectID `bson:"_id,omitempty"`
-	Username             string             `bson:"username"`
-	Role                 model.Role         `bson:"role"`
-	Nickname             string             `bson:"nickname"`
-	AvatarURL            string             `bson:"avatar_url"`
-	XPoints              int                `bson:"XPoints"`
-	RegistrationTime     time.Time          `bson:"registration_time"`
-	LastDailyCardsUpdate time.Time          `bson:"last_daily_cards_update"`
-	Prizes               []model.UserPrize  `bson:"prizes"`
+	ID                    primitive.ObjectID `bson:"_id,omitempty"`
+	Username              string             `bson:"username"`
+	Role                  model.Role         `bson:"role"`
+	Nickname              string             `bson:"nickname"`
+	AvatarURL             string             `bson:"avatar_url"`
+	XPoints               int                `bson:"XPoints"`
+	RegistrationTime      time.Time          `bson:"registration_time"`
+	LastDailyCardsUpdate  time.Time          `bson:"last_daily_cards_update"`
+	LastWeeklyCardsUpdate time.Time          `bson:"last_weekly_cards_update"`
+	Timezone              string             `bson:"timezone,omitempty"`
+	Prizes                []model.UserPrize  `bson:"prizes"`
 }
 
 func toMongoUser(u model.User) mongoUser {
 	id, _ := primitive.ObjectIDFromHex(u.ID)
 	return mongoUser{
-		ID:                   id,
-		Username:             u.Username,
-		Role:                 u.Role,
-		Nickname:             u.Nickname,
-		AvatarURL:            u.AvatarURL,
-		XPoints:              u.XPoints,
-		RegistrationTime:     u.RegistrationTime,
-		LastDailyCardsUpdate: u.LastDailyCardsUpdate,
-		Prizes:               u.Prizes,
+		ID:                    id,
+		Username:              u.Username,
+		Role:                  u.Role,
+		Nickname:              u.Nickname,
+		AvatarURL:             u.AvatarURL,
+		XPoints:               u.XPoints,
+		RegistrationTime:      u.RegistrationTime,
+		LastDailyCardsUpdate:  u.LastDailyCardsUpdate,
+		LastWeeklyCardsUpdate: u.LastWeeklyCardsUpdate,
+		Timezone:              u.Timezone,
+		Prizes:                u.Prizes,
 	}
 }
 
@@ -104,12 +108,14 @@ func toModelUser(u mongoUser) model.User {
 			Username: u.Username,
 			Role:     u.Role,
 		},
-		Nickname:             u.Nickname,
-		AvatarURL:            u.AvatarURL,
-		XPoints:              u.XPoints,
-		RegistrationTime:     u.RegistrationTime,
-		LastDailyCardsUpdate: u.LastDailyCardsUpdate,
-		Prizes:               u.Prizes,
+		Nickname:              u.Nickname,
+		AvatarURL:             u.AvatarURL,
+		XPoints:               u.XPoints,
+		RegistrationTime:      u.RegistrationTime,
+		LastDailyCardsUpdate:  u.LastDailyCardsUpdate,
+		LastWeeklyCardsUpdate: u.LastWeeklyCardsUpdate,
+		Timezone:              u.Timezone,
+		Prizes:                u.Prizes,
 	}
 }
 
@@ -49,6 +49,29 @@ func (r *AdminsRepository) GetByUsername(ctx context.Context, username string) (
 	return toModelAdmin(result), nil
 }
 
+// Save updates the role of the admin with the username and creates the admin
+// if there is none.
+func (r *AdminsRepository) Save(ctx context.Context, admin model.Admin) error {
+	u := toSQLAdmin(admin)
+
+	query, args, err := psql.Update("admin").
+		Set("role", u.Role).
+		Where(sq.Eq{"username": u.Username}).ToSql()
+	if err != nil {
+		return fmt.Errorf("adminsRepo - Save() - sq: %w", err)
+	}
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("adminsRepo - Save() - ExecContext(): %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n > 0 {
+		return nil
+	}
+
+	return r.Create(ctx, admin)
+}
+
 type Admin struct {
 	ID       int    `db:"id"`
 	Username string `db:"username"`
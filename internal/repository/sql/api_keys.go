@@ -0,0 +1,172 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type APIKeysRepository struct {
+	db *sqlx.DB
+}
+
+func NewAPIKeysRepository(db *sqlx.DB) *APIKeysRepository {
+	return &APIKeysRepository{db: db}
+}
+
+func (r *APIKeysRepository) Create(ctx context.Context, key model.APIKey) (string, error) {
+	k := toSQLAPIKey(key)
+
+	query, args, err := psql.Insert("api_key").
+		Columns("name", "prefix", "key", "permissions", "created_by", "created_at", "revoked").
+		Values(k.Name, k.Prefix, k.Key, k.Permissions, k.CreatedBy, k.CreatedAt, k.Revoked).
+		Suffix("RETURNING id").ToSql()
+	if err != nil {
+		return "", fmt.Errorf("apiKeysRepo - Create() - sq: %w", err)
+	}
+
+	var id int
+	if err := r.db.QueryRowxContext(ctx, query, args...).Scan(&id); err != nil {
+		return "", fmt.Errorf("apiKeysRepo - Create() - QueryRowxContext(): %w", err)
+	}
+
+	return strconv.Itoa(id), nil
+}
+
+func (r *APIKeysRepository) GetAll(ctx context.Context) ([]model.APIKey, error) {
+	var keys []APIKey
+
+	query, args, err := psql.Select("*").From("api_key").OrderBy("id").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("apiKeysRepo - GetAll() - sq: %w", err)
+	}
+
+	if err := r.db.SelectContext(ctx, &keys, query, args...); err != nil {
+		return nil, fmt.Errorf("apiKeysRepo - GetAll() - SelectContext(): %w", err)
+	}
+
+	result := make([]model.APIKey, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, toModelAPIKey(k))
+	}
+	return result, nil
+}
+
+func (r *APIKeysRepository) GetByKey(ctx context.Context, key string) (model.APIKey, error) {
+	var apiKey APIKey
+
+	query, args, err := psql.Select("*").From("api_key").Where(sq.Eq{"key": key}).ToSql()
+	if err != nil {
+		return model.APIKey{}, fmt.Errorf("apiKeysRepo - GetByKey() - sq: %w", err)
+	}
+
+	err = r.db.QueryRowxContext(ctx, query, args...).StructScan(&apiKey)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = model.ErrAPIKeyNotFound
+		}
+		return model.APIKey{}, fmt.Errorf("apiKeysRepo - GetByKey() - QueryRowxContext(): %w", err)
+	}
+
+	return toModelAPIKey(apiKey), nil
+}
+
+func (r *APIKeysRepository) Revoke(ctx context.Context, id string) error {
+	query, args, err := psql.Update("api_key").
+		Set("revoked", true).
+		Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return fmt.Errorf("apiKeysRepo - Revoke() - sq: %w", err)
+	}
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("apiKeysRepo - Revoke() - ExecContext(): %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("apiKeysRepo - Revoke(): %w", model.ErrAPIKeyNotFound)
+	}
+
+	return nil
+}
+
+func (r *APIKeysRepository) UpdateLastUsed(ctx context.Context, id string, lastUsedAt time.Time) error {
+	query, args, err := psql.Update("api_key").
+		Set("last_used_at", lastUsedAt).
+		Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return fmt.Errorf("apiKeysRepo - UpdateLastUsed() - sq: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("apiKeysRepo - UpdateLastUsed() - ExecContext(): %w", err)
+	}
+
+	return nil
+}
+
+// APIKey keeps the permissions comma separated.
+type APIKey struct {
+	ID          int          `db:"id"`
+	Name        string       `db:"name"`
+	Prefix      string       `db:"prefix"`
+	Key         string       `db:"key"`
+	Permissions string       `db:"permissions"`
+	CreatedBy   string       `db:"created_by"`
+	CreatedAt   time.Time    `db:"created_at"`
+	LastUsedAt  sql.NullTime `db:"last_used_at"`
+	Revoked     bool         `db:"revoked"`
+}
+
+func toSQLAPIKey(k model.APIKey) APIKey {
+	id, _ := strconv.Atoi(k.ID)
+
+	permissions := make([]string, 0, len(k.Permissions))
+	for _, p := range k.Permissions {
+		permissions = append(permissions, string(p))
+	}
+
+	return APIKey{
+		ID:          id,
+		Name:        k.Name,
+		Prefix:      k.Prefix,
+		Key:         k.Key,
+		Permissions: strings.Join(permissions, ","),
+		CreatedBy:   k.CreatedBy,
+		CreatedAt:   k.CreatedAt,
+		LastUsedAt:  sql.NullTime{Time: k.LastUsedAt, Valid: !k.LastUsedAt.IsZero()},
+		Revoked:     k.Revoked,
+	}
+}
+
+func toModelAPIKey(k APIKey) model.APIKey {
+	var permissions []model.Permission
+	if k.Permissions != "" {
+		for _, p := range strings.Split(k.Permissions, ",") {
+			permissions = append(permissions, model.Permission(p))
+		}
+	}
+
+	return model.APIKey{
+		ID:          strconv.Itoa(k.ID),
+		Name:        k.Name,
+		Prefix:      k.Prefix,
+		Key:         k.Key,
+		Permissions: permissions,
+		CreatedBy:   k.CreatedBy,
+		CreatedAt:   k.CreatedAt,
+		LastUsedAt:  k.LastUsedAt.Time,
+		Revoked:     k.Revoked,
+	}
+}
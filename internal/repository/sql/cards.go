@@ -2,13 +2,16 @@ package sql
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"sort"
 	"strconv"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
-	"github.com/wintermonth2298/xp-loyalty/internal/model"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
 )
 
 type CardsRepository struct {
@@ -31,17 +34,59 @@ func (r *CardsRepository) GetStatic(ctx context.Context, ids []string) ([]model.
 }
 
 func (r *CardsRepository) GetStaticByPool(ctx context.Context, pool string) ([]model.CardStatic, error) {
-	return r.getStatic(ctx, sq.Eq{"pool": pool})
+	return r.getStatic(ctx, sq.Eq{"pool": pool, "archived": false})
+}
+
+func (r *CardsRepository) GetStaticByExternalID(ctx context.Context, externalID string) (model.CardStatic, error) {
+	cards, err := r.getStatic(ctx, sq.Eq{"external_id": externalID})
+	if err != nil {
+		return model.CardStatic{}, fmt.Errorf("cardsRepo - GetStaticByExternalID(): %w", err)
+	}
+	if len(cards) == 0 {
+		return model.CardStatic{}, fmt.Errorf("cardsRepo - GetStaticByExternalID(): %w", model.ErrNoSuchCard)
+	}
+	return cards[0], nil
+}
+
+// CountStaticByGoal counts the static cards with the goal, archived ones too.
+func (r *CardsRepository) CountStaticByGoal(ctx context.Context, goal string) (int, error) {
+	query, args, err := psql.Select("COUNT(*)").From("card_static").Where(sq.Eq{"goal": goal}).ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("cardsRepo - CountStaticByGoal() - sq: %w", err)
+	}
+
+	var n int
+	if err := r.db.QueryRowxContext(ctx, query, args...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("cardsRepo - CountStaticByGoal() - QueryRowxContext(): %w", err)
+	}
+	return n, nil
+}
+
+// CountStaticBySeason counts the season cards of the season, archived ones too.
+func (r *CardsRepository) CountStaticBySeason(ctx context.Context, season string) (int, error) {
+	query, args, err := psql.Select("COUNT(*)").From("card_static").
+		Where(sq.Eq{"pool": model.PoolSeason, "season": season}).ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("cardsRepo - CountStaticBySeason() - sq: %w", err)
+	}
+
+	var n int
+	if err := r.db.QueryRowxContext(ctx, query, args...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("cardsRepo - CountStaticBySeason() - QueryRowxContext(): %w", err)
+	}
+	return n, nil
 }
 
 func (r *CardsRepository) getStatic(ctx context.Context, eq sq.Eq) ([]model.CardStatic, error) {
 	var rows []CardStaticRow
 
 	query, args, err := psql.
-		Select("card_static.*, award.xpoints, award.prize, award.prize_img_url, award.opt").
+		Select("card_static.*, award.xpoints, award.prize, award.prize_img_url, award.opt, award.streak_day, " +
+			"card_static_step.step_id, card_static_step.title AS step_title").
 		From("card_static").LeftJoin("award ON award.card_static_id = card_static.id").
+		LeftJoin("card_static_step ON card_static_step.card_static_id = card_static.id").
 		Where(eq).
-		OrderBy("card_static.id, award.opt").
+		OrderBy("card_static.id, award.opt, award.streak_day, card_static_step.position").
 		ToSql()
 	if err != nil {
 		return nil, err
@@ -63,22 +108,41 @@ func (r *CardsRepository) getStatic(ctx context.Context, eq sq.Eq) ([]model.Card
 }
 
 type CardStaticRow struct {
-	ID               int       `db:"id"`
-	Title            string    `db:"title"`
-	ShortDescription string    `db:"short_description"`
-	LongDescription  string    `db:"long_description"`
-	Goal             string    `db:"goal"`
-	CreatedAt        time.Time `db:"created_at"`
-	Type             string    `db:"type"`
-	Pool             string    `db:"pool"`
-	SettingID        int       `db:"setting_id"`
-	XPoints          int       `db:"xpoints"`
-	Prize            string    `db:"prize"`
-	PrizeImageURL    string    `db:"prize_img_url"`
-	ChainName        *string   `db:"chain_name"`
-	ChainOrder       *int      `db:"chain_order"`
-	MaxProgress      *int      `db:"max_progress"`
-	Opt              *float32  `db:"opt"`
+	ID               int            `db:"id"`
+	Version          int            `db:"version"`
+	ExternalID       sql.NullString `db:"external_id"`
+	Title            string         `db:"title"`
+	ShortDescription string         `db:"short_description"`
+	LongDescription  string         `db:"long_description"`
+	Goal             string         `db:"goal"`
+	CreatedAt        time.Time      `db:"created_at"`
+	UpdatedAt        time.Time      `db:"updated_at"`
+	Archived         bool           `db:"archived"`
+	ArchivedAt       sql.NullTime   `db:"archived_at"`
+	Type             string         `db:"type"`
+	Pool             string         `db:"pool"`
+	SettingID        int            `db:"setting_id"`
+	XPoints          int            `db:"xpoints"`
+	Prize            string         `db:"prize"`
+	PrizeImageURL    string         `db:"prize_img_url"`
+	ChainName        *string        `db:"chain_name"`
+	ChainOrder       *int           `db:"chain_order"`
+	Season           *string        `db:"season"`
+	MaxProgress      *int           `db:"max_progress"`
+	Opt              *float32       `db:"opt"`
+	StreakDays       *int           `db:"streak_days"`
+	StreakGraceDays  *int           `db:"streak_grace_days"`
+	StartsAt         sql.NullTime   `db:"starts_at"`
+	EndsAt           sql.NullTime   `db:"ends_at"`
+	Weekdays         *int           `db:"weekdays"`
+	FromHour         *int           `db:"from_hour"`
+	ToHour           *int           `db:"to_hour"`
+	MaxCompletions   *int           `db:"max_completions"`
+	CooldownSeconds  *int           `db:"cooldown_seconds"`
+	Weight           int            `db:"weight"`
+	StreakDay        *int           `db:"streak_day"`
+	StepID           *string        `db:"step_id"`
+	StepTitle        *string        `db:"step_title"`
 }
 
 func ToModelCardStatic(rows []CardStaticRow) []model.CardStatic {
@@ -89,13 +153,19 @@ func ToModelCardStatic(rows []CardStaticRow) []model.CardStatic {
 
 		card := model.CardStatic{
 			ID:               strconv.Itoa(row.ID),
+			Version:          row.Version,
+			ExternalID:       row.ExternalID.String,
 			Title:            row.Title,
 			ShortDescription: row.ShortDescription,
 			LongDescription:  row.LongDescription,
 			Goal:             row.Goal,
 			CreatedAt:        row.CreatedAt,
+			UpdatedAt:        row.UpdatedAt,
+			Archived:         row.Archived,
+			ArchivedAt:       row.ArchivedAt.Time,
 			Type:             row.Type,
 			Pool:             row.Pool,
+			Weight:           row.Weight,
 			ChainName:        "",
 			ChainOrder:       0,
 		}
@@ -104,6 +174,16 @@ func ToModelCardStatic(rows []CardStaticRow) []model.CardStatic {
 			card.ChainName = *row.ChainName
 			card.ChainOrder = *row.ChainOrder
 		}
+		if row.Pool == model.PoolSeason && row.Season != nil {
+			card.Season = *row.Season
+		}
+
+		if row.StartsAt.Valid || row.EndsAt.Valid || row.Weekdays != nil || row.FromHour != nil {
+			card.Availability = toModelAvailability(row)
+		}
+		if row.MaxCompletions != nil || row.CooldownSeconds != nil {
+			card.Repeat = toModelRepeatSettings(row)
+		}
 
 		switch row.Type {
 		case model.TypeOptions:
@@ -127,6 +207,63 @@ func ToModelCardStatic(rows []CardStaticRow) []model.CardStatic {
 				Awards:  awards,
 				Options: opts,
 			}
+		case model.TypeStreak:
+			milestones := make([]model.Milestone, 0, 5)
+			var j int
+			for j = i; j < len(rows); j++ {
+				if rows[i].ID != rows[j].ID {
+					break
+				}
+				// a card without milestones is joined to no milestone row
+				if rows[j].StreakDay == nil {
+					continue
+				}
+				m := model.Milestone{
+					Day: *rows[j].StreakDay,
+					Award: model.Award{
+						XPoints:       rows[j].XPoints,
+						Prize:         rows[j].Prize,
+						PrizeImageURL: rows[j].PrizeImageURL,
+					},
+				}
+				milestones = append(milestones, m)
+			}
+			i = j - 1
+			card.StreakSettings = &model.StreakSettings{
+				Milestones: milestones,
+			}
+			if row.StreakDays != nil {
+				card.StreakSettings.Days = *row.StreakDays
+			}
+			if row.StreakGraceDays != nil {
+				card.StreakSettings.GraceDays = *row.StreakGraceDays
+			}
+		case model.TypeChecklist:
+			steps := make([]model.Step, 0, 5)
+			var j int
+			for j = i; j < len(rows); j++ {
+				if rows[i].ID != rows[j].ID {
+					break
+				}
+				// a card without steps is joined to no step row
+				if rows[j].StepID == nil {
+					continue
+				}
+				step := model.Step{ID: *rows[j].StepID}
+				if rows[j].StepTitle != nil {
+					step.Title = *rows[j].StepTitle
+				}
+				steps = append(steps, step)
+			}
+			i = j - 1
+			card.ChecklistSettings = &model.ChecklistSettings{
+				Steps: steps,
+				Award: model.Award{
+					XPoints:       row.XPoints,
+					Prize:         row.Prize,
+					PrizeImageURL: row.PrizeImageURL,
+				},
+			}
 		case model.TypeProgress:
 			card.PrgSettings = &model.PrgSettings{
 				Award: model.Award{
@@ -151,3 +288,33 @@ func ToModelCardStatic(rows []CardStaticRow) []model.CardStatic {
 
 	return cards
 }
+
+func toModelAvailability(row CardStaticRow) *model.Availability {
+	a := &model.Availability{
+		StartsAt: row.StartsAt.Time,
+		EndsAt:   row.EndsAt.Time,
+	}
+	if row.Weekdays != nil {
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			if *row.Weekdays&(1<<d) != 0 {
+				a.Weekdays = append(a.Weekdays, d)
+			}
+		}
+	}
+	if row.FromHour != nil && row.ToHour != nil {
+		a.FromHour = *row.FromHour
+		a.ToHour = *row.ToHour
+	}
+	return a
+}
+
+func toModelRepeatSettings(row CardStaticRow) *model.RepeatSettings {
+	s := new(model.RepeatSettings)
+	if row.MaxCompletions != nil {
+		s.MaxCompletions = *row.MaxCompletions
+	}
+	if row.CooldownSeconds != nil {
+		s.Cooldown.Duration = time.Duration(*row.CooldownSeconds) * time.Second
+	}
+	return s
+}
@@ -0,0 +1,22 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+func TestToModelCardStatic_NoSettingRows(t *testing.T) {
+	rows := []CardStaticRow{
+		{ID: 1, Type: model.TypeStreak, Pool: model.PoolDaily},
+		{ID: 2, Type: model.TypeChecklist, Pool: model.PoolDaily, XPoints: 10},
+	}
+
+	var cards []model.CardStatic
+	require.NotPanics(t, func() { cards = ToModelCardStatic(rows) })
+	require.Len(t, cards, 2)
+	require.Equal(t, &model.StreakSettings{Milestones: []model.Milestone{}}, cards[0].StreakSettings)
+	require.Equal(t, &model.ChecklistSettings{Steps: []model.Step{}, Award: model.Award{XPoints: 10}}, cards[1].ChecklistSettings)
+}
@@ -0,0 +1,118 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type ChainsRepository struct {
+	db *sqlx.DB
+}
+
+func NewChainsRepository(db *sqlx.DB) *ChainsRepository {
+	return &ChainsRepository{db: db}
+}
+
+func (r *ChainsRepository) GetAll(ctx context.Context) (model.Chains, error) {
+	var rows []ChainRow
+
+	query, args, err := psql.
+		Select("chain.name, chain.min_level, chain_requirement.requires").
+		From("chain").LeftJoin("chain_requirement ON chain_requirement.chain_name = chain.name").
+		OrderBy("chain.name, chain_requirement.requires").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("chainsRepo - GetAll() - sq: %w", err)
+	}
+
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("chainsRepo - GetAll() - SelectContext(): %w", err)
+	}
+
+	var chains model.Chains
+	for _, row := range rows {
+		if len(chains) == 0 || chains[len(chains)-1].Name != row.Name {
+			chains = append(chains, model.Chain{Name: row.Name, MinLevel: row.MinLevel})
+		}
+		if row.Requires != nil {
+			c := &chains[len(chains)-1]
+			c.Requires = append(c.Requires, *row.Requires)
+		}
+	}
+	return chains, nil
+}
+
+func (r *ChainsRepository) Set(ctx context.Context, chain model.Chain) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("chainsRepo - Set() - BeginTxx(): %w", err)
+	}
+	defer tx.Rollback()
+
+	query, args, err := psql.Insert("chain").
+		Columns("name", "min_level").
+		Values(chain.Name, chain.MinLevel).
+		Suffix("ON CONFLICT (name) DO UPDATE SET min_level = EXCLUDED.min_level").ToSql()
+	if err != nil {
+		return fmt.Errorf("chainsRepo - Set() - sq: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("chainsRepo - Set() - ExecContext(): %w", err)
+	}
+
+	query, args, err = psql.Delete("chain_requirement").Where(sq.Eq{"chain_name": chain.Name}).ToSql()
+	if err != nil {
+		return fmt.Errorf("chainsRepo - Set() - sq: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("chainsRepo - Set() - ExecContext(): %w", err)
+	}
+
+	if len(chain.Requires) != 0 {
+		insert := psql.Insert("chain_requirement").Columns("chain_name", "requires")
+		for _, name := range chain.Requires {
+			insert = insert.Values(chain.Name, name)
+		}
+		query, args, err = insert.ToSql()
+		if err != nil {
+			return fmt.Errorf("chainsRepo - Set() - sq: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("chainsRepo - Set() - ExecContext(): %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("chainsRepo - Set() - Commit(): %w", err)
+	}
+	return nil
+}
+
+func (r *ChainsRepository) Delete(ctx context.Context, name string) error {
+	query, args, err := psql.Delete("chain").Where(sq.Eq{"name": name}).ToSql()
+	if err != nil {
+		return fmt.Errorf("chainsRepo - Delete() - sq: %w", err)
+	}
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("chainsRepo - Delete() - ExecContext(): %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("chainsRepo - Delete(): %w", model.ErrChainNotFound)
+	}
+
+	return nil
+}
+
+type ChainRow struct {
+	Name     string  `db:"name"`
+	MinLevel int     `db:"min_level"`
+	Requires *string `db:"requires"`
+}
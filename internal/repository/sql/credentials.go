@@ -6,10 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
-	"github.com/wintermonth2298/xp-loyalty/internal/model"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
 )
 
 type CredentialsRepository struct {
@@ -23,8 +25,8 @@ func NewCredentialsRepository(db *sqlx.DB) *CredentialsRepository {
 func (r *CredentialsRepository) Create(ctx context.Context, creds model.Credentials) error {
 	c := toSQLCreds(creds)
 
-	query, args, err := psql.Insert("creds").Columns("username", "role", "password").
-		Values(c.Username, c.Role, c.Password).ToSql()
+	query, args, err := psql.Insert("creds").Columns("username", "role", "password", "issuer", "subject").
+		Values(c.Username, c.Role, c.Password, c.Issuer, c.Subject).ToSql()
 	if err != nil {
 		return fmt.Errorf("credsRepo - Create() - sq: %w", err)
 	}
@@ -41,8 +43,61 @@ func (r *CredentialsRepository) GetByUsername(ctx context.Context, username stri
 	return r.get(ctx, sq.Eq{"creds.username": username})
 }
 
-func (r *CredentialsRepository) GetByCredentials(ctx context.Context, username, password string) (model.Credentials, error) {
-	return r.get(ctx, sq.Eq{"creds.username": username, "creds.password": password})
+func (r *CredentialsRepository) GetByIdentity(ctx context.Context, issuer, subject string) (model.Credentials, error) {
+	return r.get(ctx, sq.Eq{"creds.issuer": issuer, "creds.subject": subject})
+}
+
+func (r *CredentialsRepository) UpdatePassword(ctx context.Context, id, password string) error {
+	query, args, err := psql.Update("creds").
+		Set("password", password).
+		Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return fmt.Errorf("credsRepo - UpdatePassword() - sq: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("credsRepo - UpdatePassword() - ExecContext(): %w", err)
+	}
+
+	return nil
+}
+
+func (r *CredentialsRepository) UpdateRole(ctx context.Context, id string, role model.Role) error {
+	query, args, err := psql.Update("creds").
+		Set("role", int(role)).
+		Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return fmt.Errorf("credsRepo - UpdateRole() - sq: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("credsRepo - UpdateRole() - ExecContext(): %w", err)
+	}
+
+	return nil
+}
+
+func (r *CredentialsRepository) UpdateSecondFactor(ctx context.Context, id string, factor model.SecondFactor) error {
+	f := toSQLSecondFactor(factor)
+
+	query, args, err := psql.Update("creds").
+		Set("totp_secret", f.Secret).
+		Set("totp_enabled", f.Enabled).
+		Set("recovery_codes", f.RecoveryCodes).
+		Set("totp_last_step", f.LastStep).
+		Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return fmt.Errorf("credsRepo - UpdateSecondFactor() - sq: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("credsRepo - UpdateSecondFactor() - ExecContext(): %w", err)
+	}
+
+	return nil
 }
 
 func (r *CredentialsRepository) get(ctx context.Context, eq sq.Eq) (model.Credentials, error) {
@@ -63,15 +118,29 @@ type Credentials struct {
 	Username string `db:"username"`
 	Role     int    `db:"role"`
 	Password string `db:"password"`
+	Issuer   string `db:"issuer"`
+	Subject  string `db:"subject"`
+	SecondFactor
+}
+
+// SecondFactor keeps the recovery code hashes comma separated.
+type SecondFactor struct {
+	Secret        string `db:"totp_secret"`
+	Enabled       bool   `db:"totp_enabled"`
+	RecoveryCodes string `db:"recovery_codes"`
+	LastStep      int64  `db:"totp_last_step"`
 }
 
 func toSQLCreds(c model.Credentials) Credentials {
 	id, _ := strconv.Atoi(c.ID)
 	return Credentials{
-		ID:       id,
-		Username: c.Username,
-		Role:     int(c.Role),
-		Password: c.Password,
+		ID:           id,
+		Username:     c.Username,
+		Role:         int(c.Role),
+		Password:     c.Password,
+		Issuer:       c.Issuer,
+		Subject:      c.Subject,
+		SecondFactor: toSQLSecondFactor(c.SecondFactor),
 	}
 }
 
@@ -82,6 +151,32 @@ func toModelCreds(c Credentials) model.Credentials {
 			Username: c.Username,
 			Role:     model.Role(c.Role),
 		},
-		Password: c.Password,
+		Password:     c.Password,
+		Issuer:       c.Issuer,
+		Subject:      c.Subject,
+		SecondFactor: toModelSecondFactor(c.SecondFactor),
+	}
+}
+
+func toSQLSecondFactor(f model.SecondFactor) SecondFactor {
+	return SecondFactor{
+		Secret:        f.Secret,
+		Enabled:       f.Enabled,
+		RecoveryCodes: strings.Join(f.RecoveryCodes, ","),
+		LastStep:      f.LastStep,
+	}
+}
+
+func toModelSecondFactor(f SecondFactor) model.SecondFactor {
+	var codes []string
+	if f.RecoveryCodes != "" {
+		codes = strings.Split(f.RecoveryCodes, ",")
+	}
+
+	return model.SecondFactor{
+		Secret:        f.Secret,
+		Enabled:       f.Enabled,
+		RecoveryCodes: codes,
+		LastStep:      f.LastStep,
 	}
 }
@@ -0,0 +1,132 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type GoalsRepository struct {
+	db *sqlx.DB
+}
+
+func NewGoalsRepository(db *sqlx.DB) *GoalsRepository {
+	return &GoalsRepository{db: db}
+}
+
+func (r *GoalsRepository) Create(ctx context.Context, goal model.Goal) (string, error) {
+	query, args, err := psql.Insert("goal").
+		Columns("name", "title", "created_at").
+		Values(goal.Name, goal.Title, goal.CreatedAt).
+		Suffix("RETURNING id").ToSql()
+	if err != nil {
+		return "", fmt.Errorf("goalsRepo - Create() - sq: %w", err)
+	}
+
+	var id int
+	if err := r.db.QueryRowxContext(ctx, query, args...).Scan(&id); err != nil {
+		return "", fmt.Errorf("goalsRepo - Create() - QueryRowxContext(): %w", err)
+	}
+
+	return strconv.Itoa(id), nil
+}
+
+func (r *GoalsRepository) GetAll(ctx context.Context) (model.Goals, error) {
+	var goals []Goal
+
+	query, args, err := psql.Select("*").From("goal").OrderBy("name").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("goalsRepo - GetAll() - sq: %w", err)
+	}
+
+	if err := r.db.SelectContext(ctx, &goals, query, args...); err != nil {
+		return nil, fmt.Errorf("goalsRepo - GetAll() - SelectContext(): %w", err)
+	}
+
+	result := make(model.Goals, 0, len(goals))
+	for _, g := range goals {
+		result = append(result, toModelGoal(g))
+	}
+	return result, nil
+}
+
+func (r *GoalsRepository) GetByName(ctx context.Context, name string) (model.Goal, error) {
+	var goal Goal
+
+	query, args, err := psql.Select("*").From("goal").Where(sq.Eq{"name": name}).ToSql()
+	if err != nil {
+		return model.Goal{}, fmt.Errorf("goalsRepo - GetByName() - sq: %w", err)
+	}
+
+	err = r.db.QueryRowxContext(ctx, query, args...).StructScan(&goal)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = model.ErrGoalNotFound
+		}
+		return model.Goal{}, fmt.Errorf("goalsRepo - GetByName() - QueryRowxContext(): %w", err)
+	}
+
+	return toModelGoal(goal), nil
+}
+
+func (r *GoalsRepository) UpdateTitle(ctx context.Context, name, title string) error {
+	query, args, err := psql.Update("goal").
+		Set("title", title).
+		Where(sq.Eq{"name": name}).ToSql()
+	if err != nil {
+		return fmt.Errorf("goalsRepo - UpdateTitle() - sq: %w", err)
+	}
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("goalsRepo - UpdateTitle() - ExecContext(): %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("goalsRepo - UpdateTitle(): %w", model.ErrGoalNotFound)
+	}
+
+	return nil
+}
+
+func (r *GoalsRepository) Delete(ctx context.Context, name string) error {
+	query, args, err := psql.Delete("goal").Where(sq.Eq{"name": name}).ToSql()
+	if err != nil {
+		return fmt.Errorf("goalsRepo - Delete() - sq: %w", err)
+	}
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("goalsRepo - Delete() - ExecContext(): %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("goalsRepo - Delete(): %w", model.ErrGoalNotFound)
+	}
+
+	return nil
+}
+
+type Goal struct {
+	ID        int       `db:"id"`
+	Name      string    `db:"name"`
+	Title     string    `db:"title"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func toModelGoal(g Goal) model.Goal {
+	return model.Goal{
+		ID:        strconv.Itoa(g.ID),
+		Name:      g.Name,
+		Title:     g.Title,
+		CreatedAt: g.CreatedAt,
+	}
+}
@@ -7,7 +7,8 @@ import (
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
-	"github.com/wintermonth2298/xp-loyalty/internal/model"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
 )
 
 type ImagesRepository struct {
@@ -0,0 +1,105 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type LoginAttemptsRepository struct {
+	db *sqlx.DB
+}
+
+func NewLoginAttemptsRepository(db *sqlx.DB) *LoginAttemptsRepository {
+	return &LoginAttemptsRepository{db: db}
+}
+
+// Get returns empty attempts for a key without failures.
+func (r *LoginAttemptsRepository) Get(ctx context.Context, key string) (model.LoginAttempts, error) {
+	var attempts LoginAttempts
+
+	query, args, err := psql.Select("*").From("login_attempt").
+		Where(sq.Eq{"login_attempt.key": key}).ToSql()
+	if err != nil {
+		return model.LoginAttempts{}, fmt.Errorf("loginAttemptsRepo - Get() - sq: %w", err)
+	}
+
+	err = r.db.QueryRowxContext(ctx, query, args...).StructScan(&attempts)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.LoginAttempts{Key: key}, nil
+		}
+		return model.LoginAttempts{}, fmt.Errorf("loginAttemptsRepo - Get() - QueryRowxContext(): %w", err)
+	}
+
+	return model.LoginAttempts(attempts), nil
+}
+
+// AddFailure increments the failures with a single upsert, so the count is
+// reset and incremented atomically.
+func (r *LoginAttemptsRepository) AddFailure(ctx context.Context, key string, now, since time.Time) (model.LoginAttempts, error) {
+	var attempts LoginAttempts
+
+	query, args, err := psql.Insert("login_attempt").
+		Columns("key", "failures", "last_failure", "locked_until").
+		Values(key, 1, now, time.Time{}).
+		Suffix("ON CONFLICT (key) DO UPDATE SET "+
+			"failures = CASE WHEN login_attempt.last_failure >= ? THEN login_attempt.failures + 1 ELSE 1 END, "+
+			"last_failure = EXCLUDED.last_failure "+
+			"RETURNING key, failures, last_failure, locked_until", since).
+		ToSql()
+	if err != nil {
+		return model.LoginAttempts{}, fmt.Errorf("loginAttemptsRepo - AddFailure() - sq: %w", err)
+	}
+
+	err = r.db.QueryRowxContext(ctx, query, args...).StructScan(&attempts)
+	if err != nil {
+		return model.LoginAttempts{}, fmt.Errorf("loginAttemptsRepo - AddFailure() - QueryRowxContext(): %w", err)
+	}
+
+	return model.LoginAttempts(attempts), nil
+}
+
+func (r *LoginAttemptsRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	query, args, err := psql.Update("login_attempt").
+		Set("locked_until", sq.Expr("GREATEST(locked_until, ?)", until)).
+		Where(sq.Eq{"key": key}).ToSql()
+	if err != nil {
+		return fmt.Errorf("loginAttemptsRepo - Lock() - sq: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("loginAttemptsRepo - Lock() - ExecContext(): %w", err)
+	}
+
+	return nil
+}
+
+func (r *LoginAttemptsRepository) Delete(ctx context.Context, key string) error {
+	query, args, err := psql.Delete("login_attempt").Where(sq.Eq{"key": key}).ToSql()
+	if err != nil {
+		return fmt.Errorf("loginAttemptsRepo - Delete() - sq: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("loginAttemptsRepo - Delete() - ExecContext(): %w", err)
+	}
+
+	return nil
+}
+
+type LoginAttempts struct {
+	Key         string    `db:"key"`
+	Failures    int       `db:"failures"`
+	LastFailure time.Time `db:"last_failure"`
+	LockedUntil time.Time `db:"locked_until"`
+}
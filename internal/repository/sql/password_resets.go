@@ -0,0 +1,114 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type PasswordResetsRepository struct {
+	db *sqlx.DB
+}
+
+func NewPasswordResetsRepository(db *sqlx.DB) *PasswordResetsRepository {
+	return &PasswordResetsRepository{db: db}
+}
+
+func (r *PasswordResetsRepository) Create(ctx context.Context, reset model.PasswordReset) error {
+	p := toSQLPasswordReset(reset)
+
+	query, args, err := psql.Insert("password_reset").
+		Columns("username", "token", "created_by", "created_at", "expires_at", "used").
+		Values(p.Username, p.Token, p.CreatedBy, p.CreatedAt, p.ExpiresAt, p.Used).ToSql()
+	if err != nil {
+		return fmt.Errorf("passwordResetsRepo - Create() - sq: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("passwordResetsRepo - Create() - ExecContext(): %w", err)
+	}
+
+	return nil
+}
+
+func (r *PasswordResetsRepository) GetByToken(ctx context.Context, token string) (model.PasswordReset, error) {
+	var reset PasswordReset
+
+	query, args, err := psql.Select("*").From("password_reset").
+		Where(sq.Eq{"password_reset.token": token}).ToSql()
+	if err != nil {
+		return model.PasswordReset{}, fmt.Errorf("passwordResetsRepo - GetByToken() - sq: %w", err)
+	}
+
+	err = r.db.QueryRowxContext(ctx, query, args...).StructScan(&reset)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = model.ErrInvalidResetToken
+		}
+		return model.PasswordReset{}, fmt.Errorf("passwordResetsRepo - GetByToken() - QueryRowxContext(): %w", err)
+	}
+
+	return toModelPasswordReset(reset), nil
+}
+
+func (r *PasswordResetsRepository) Update(ctx context.Context, reset model.PasswordReset) error {
+	p := toSQLPasswordReset(reset)
+
+	query, args, err := psql.Update("password_reset").
+		Set("used", p.Used).
+		Where(sq.Eq{"id": p.ID}).ToSql()
+	if err != nil {
+		return fmt.Errorf("passwordResetsRepo - Update() - sq: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("passwordResetsRepo - Update() - ExecContext(): %w", err)
+	}
+
+	return nil
+}
+
+type PasswordReset struct {
+	ID        int       `db:"id"`
+	Username  string    `db:"username"`
+	Token     string    `db:"token"`
+	CreatedBy string    `db:"created_by"`
+	CreatedAt time.Time `db:"created_at"`
+	ExpiresAt time.Time `db:"expires_at"`
+	Used      bool      `db:"used"`
+}
+
+func toSQLPasswordReset(r model.PasswordReset) PasswordReset {
+	id, _ := strconv.Atoi(r.ID)
+	return PasswordReset{
+		ID:        id,
+		Username:  r.Username,
+		Token:     r.Token,
+		CreatedBy: r.CreatedBy,
+		CreatedAt: r.CreatedAt,
+		ExpiresAt: r.ExpiresAt,
+		Used:      r.Used,
+	}
+}
+
+func toModelPasswordReset(r PasswordReset) model.PasswordReset {
+	return model.PasswordReset{
+		ID:        strconv.Itoa(r.ID),
+		Username:  r.Username,
+		Token:     r.Token,
+		CreatedBy: r.CreatedBy,
+		CreatedAt: r.CreatedAt,
+		ExpiresAt: r.ExpiresAt,
+		Used:      r.Used,
+	}
+}
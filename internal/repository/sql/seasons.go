@@ -0,0 +1,78 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type SeasonsRepository struct {
+	db *sqlx.DB
+}
+
+func NewSeasonsRepository(db *sqlx.DB) *SeasonsRepository {
+	return &SeasonsRepository{db: db}
+}
+
+func (r *SeasonsRepository) GetAll(ctx context.Context) (model.Seasons, error) {
+	var rows []SeasonRow
+
+	query, args, err := psql.Select("name, starts_at, ends_at").From("season").OrderBy("starts_at, name").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("seasonsRepo - GetAll() - sq: %w", err)
+	}
+
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("seasonsRepo - GetAll() - SelectContext(): %w", err)
+	}
+
+	seasons := make(model.Seasons, 0, len(rows))
+	for _, row := range rows {
+		seasons = append(seasons, model.Season(row))
+	}
+	return seasons, nil
+}
+
+func (r *SeasonsRepository) Set(ctx context.Context, season model.Season) error {
+	query, args, err := psql.Insert("season").
+		Columns("name", "starts_at", "ends_at").
+		Values(season.Name, season.StartsAt, season.EndsAt).
+		Suffix("ON CONFLICT (name) DO UPDATE SET starts_at = EXCLUDED.starts_at, ends_at = EXCLUDED.ends_at").ToSql()
+	if err != nil {
+		return fmt.Errorf("seasonsRepo - Set() - sq: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("seasonsRepo - Set() - ExecContext(): %w", err)
+	}
+	return nil
+}
+
+func (r *SeasonsRepository) Delete(ctx context.Context, name string) error {
+	query, args, err := psql.Delete("season").Where(sq.Eq{"name": name}).ToSql()
+	if err != nil {
+		return fmt.Errorf("seasonsRepo - Delete() - sq: %w", err)
+	}
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("seasonsRepo - Delete() - ExecContext(): %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("seasonsRepo - Delete(): %w", model.ErrSeasonNotFound)
+	}
+
+	return nil
+}
+
+type SeasonRow struct {
+	Name     string    `db:"name"`
+	StartsAt time.Time `db:"starts_at"`
+	EndsAt   time.Time `db:"ends_at"`
+}
@@ -0,0 +1,138 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type SessionsRepository struct {
+	db *sqlx.DB
+}
+
+func NewSessionsRepository(db *sqlx.DB) *SessionsRepository {
+	return &SessionsRepository{db: db}
+}
+
+func (r *SessionsRepository) Create(ctx context.Context, session model.Session) (string, error) {
+	s := toSQLSession(session)
+
+	query, args, err := psql.Insert("session").
+		Columns("username", "refresh_token", "created_at", "expires_at", "revoked").
+		Values(s.Username, s.RefreshToken, s.CreatedAt, s.ExpiresAt, s.Revoked).
+		Suffix("RETURNING id").ToSql()
+	if err != nil {
+		return "", fmt.Errorf("sessionsRepo - Create() - sq: %w", err)
+	}
+
+	var id int
+	if err := r.db.QueryRowxContext(ctx, query, args...).Scan(&id); err != nil {
+		return "", fmt.Errorf("sessionsRepo - Create() - QueryRowxContext(): %w", err)
+	}
+
+	return strconv.Itoa(id), nil
+}
+
+func (r *SessionsRepository) Get(ctx context.Context, id string) (model.Session, error) {
+	return r.get(ctx, sq.Eq{"session.id": id})
+}
+
+func (r *SessionsRepository) GetByRefreshToken(ctx context.Context, refreshToken string) (model.Session, error) {
+	return r.get(ctx, sq.Eq{"session.refresh_token": refreshToken})
+}
+
+func (r *SessionsRepository) Update(ctx context.Context, session model.Session) error {
+	s := toSQLSession(session)
+
+	query, args, err := psql.Update("session").
+		SetMap(map[string]interface{}{
+			"refresh_token": s.RefreshToken,
+			"expires_at":    s.ExpiresAt,
+			"revoked":       s.Revoked,
+		}).Where(sq.Eq{"id": s.ID}).ToSql()
+	if err != nil {
+		return fmt.Errorf("sessionsRepo - Update() - sq: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("sessionsRepo - Update() - ExecContext(): %w", err)
+	}
+
+	return nil
+}
+
+func (r *SessionsRepository) RevokeByUsername(ctx context.Context, username string) error {
+	query, args, err := psql.Update("session").
+		Set("revoked", true).
+		Where(sq.Eq{"username": username, "revoked": false}).ToSql()
+	if err != nil {
+		return fmt.Errorf("sessionsRepo - RevokeByUsername() - sq: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("sessionsRepo - RevokeByUsername() - ExecContext(): %w", err)
+	}
+
+	return nil
+}
+
+func (r *SessionsRepository) get(ctx context.Context, eq sq.Eq) (model.Session, error) {
+	var session Session
+
+	query, args, err := psql.Select("*").From("session").Where(eq).ToSql()
+	if err != nil {
+		return model.Session{}, fmt.Errorf("sessionsRepo - get() - sq: %w", err)
+	}
+
+	err = r.db.QueryRowxContext(ctx, query, args...).StructScan(&session)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = model.ErrSessionNotFound
+		}
+		return model.Session{}, fmt.Errorf("sessionsRepo - get() - QueryRowxContext(): %w", err)
+	}
+
+	return toModelSession(session), nil
+}
+
+type Session struct {
+	ID           int       `db:"id"`
+	Username     string    `db:"username"`
+	RefreshToken string    `db:"refresh_token"`
+	CreatedAt    time.Time `db:"created_at"`
+	ExpiresAt    time.Time `db:"expires_at"`
+	Revoked      bool      `db:"revoked"`
+}
+
+func toSQLSession(s model.Session) Session {
+	id, _ := strconv.Atoi(s.ID)
+	return Session{
+		ID:           id,
+		Username:     s.Username,
+		RefreshToken: s.RefreshToken,
+		CreatedAt:    s.CreatedAt,
+		ExpiresAt:    s.ExpiresAt,
+		Revoked:      s.Revoked,
+	}
+}
+
+func toModelSession(s Session) model.Session {
+	return model.Session{
+		ID:           strconv.Itoa(s.ID),
+		Username:     s.Username,
+		RefreshToken: s.RefreshToken,
+		CreatedAt:    s.CreatedAt,
+		ExpiresAt:    s.ExpiresAt,
+		Revoked:      s.Revoked,
+	}
+}
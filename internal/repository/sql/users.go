@@ -8,7 +8,8 @@ import (
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
-	"github.com/wintermonth2298/xp-loyalty/internal/model"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
 )
 
 type UsersRepository struct {
@@ -30,6 +31,8 @@ func (r *UsersRepository) Create(ctx context.Context, user model.User) error {
 			"xpoints",
 			"registration_time",
 			"last_daily_cards_update",
+			"last_weekly_cards_update",
+			"timezone",
 		).
 		Values(u.Username,
 			u.Role,
@@ -38,6 +41,8 @@ func (r *UsersRepository) Create(ctx context.Context, user model.User) error {
 			u.XPoints,
 			u.RegistrationTime,
 			u.LastDailyCardsUpdate,
+			u.LastWeeklyCardsUpdate,
+			u.Timezone,
 		).ToSql()
 	if err != nil {
 		return fmt.Errorf("error users Create(): %w", err)
@@ -74,13 +79,15 @@ func (r *UsersRepository) Update(ctx context.Context, user model.User) error {
 
 	query, args, err := psql.Update("usr").
 		SetMap(map[string]interface{}{
-			"username":                u.Username,
-			"role":                    u.Role,
-			"nickname":                u.Nickname,
-			"avatar_url":              u.AvatarURL,
-			"xpoints":                 u.XPoints,
-			"registration_time":       u.RegistrationTime,
-			"last_daily_cards_update": u.LastDailyCardsUpdate,
+			"username":                 u.Username,
+			"role":                     u.Role,
+			"nickname":                 u.Nickname,
+			"avatar_url":               u.AvatarURL,
+			"xpoints":                  u.XPoints,
+			"registration_time":        u.RegistrationTime,
+			"last_daily_cards_update":  u.LastDailyCardsUpdate,
+			"last_weekly_cards_update": u.LastWeeklyCardsUpdate,
+			"timezone":                 u.Timezone,
 		}).Where(sq.Eq{"id": u.ID}).ToSql()
 	if err != nil {
 		return fmt.Errorf("usersRepo - GetAll() - sq: %w", err)
@@ -95,27 +102,31 @@ func (r *UsersRepository) Update(ctx context.Context, user model.User) error {
 }
 
 type User struct {
-	ID                   int       `db:"id"`
-	Username             string    `db:"username"`
-	Role                 int       `db:"role"`
-	Nickname             string    `db:"nickname"`
-	AvatarURL            string    `db:"avatar_url"`
-	XPoints              int       `db:"xpoints"`
-	RegistrationTime     time.Time `db:"registration_time"`
-	LastDailyCardsUpdate time.Time `db:"last_daily_cards_update"`
+	ID                    int       `db:"id"`
+	Username              string    `db:"username"`
+	Role                  int       `db:"role"`
+	Nickname              string    `db:"nickname"`
+	AvatarURL             string    `db:"avatar_url"`
+	XPoints               int       `db:"xpoints"`
+	RegistrationTime      time.Time `db:"registration_time"`
+	LastDailyCardsUpdate  time.Time `db:"last_daily_cards_update"`
+	LastWeeklyCardsUpdate time.Time `db:"last_weekly_cards_update"`
+	Timezone              string    `db:"timezone"`
 }
 
 func toSQLUser(u model.User) User {
 	id, _ := strconv.Atoi(u.ID)
 	return User{
-		ID:                   id,
-		Username:             u.Username,
-		Role:                 int(u.Role),
-		Nickname:             u.Nickname,
-		AvatarURL:            u.AvatarURL,
-		XPoints:              u.XPoints,
-		RegistrationTime:     u.RegistrationTime,
-		LastDailyCardsUpdate: u.LastDailyCardsUpdate,
+		ID:                    id,
+		Username:              u.Username,
+		Role:                  int(u.Role),
+		Nickname:              u.Nickname,
+		AvatarURL:             u.AvatarURL,
+		XPoints:               u.XPoints,
+		RegistrationTime:      u.RegistrationTime,
+		LastDailyCardsUpdate:  u.LastDailyCardsUpdate,
+		LastWeeklyCardsUpdate: u.LastWeeklyCardsUpdate,
+		Timezone:              u.Timezone,
 	}
 }
 
@@ -126,11 +137,13 @@ func toModelUser(u User) model.User {
 			Username: u.Username,
 			Role:     model.Role(u.Role),
 		},
-		Nickname:             u.Nickname,
-		AvatarURL:            u.AvatarURL,
-		XPoints:              u.XPoints,
-		RegistrationTime:     u.RegistrationTime,
-		LastDailyCardsUpdate: u.LastDailyCardsUpdate,
+		Nickname:              u.Nickname,
+		AvatarURL:             u.AvatarURL,
+		XPoints:               u.XPoints,
+		RegistrationTime:      u.RegistrationTime,
+		LastDailyCardsUpdate:  u.LastDailyCardsUpdate,
+		LastWeeklyCardsUpdate: u.LastWeeklyCardsUpdate,
+		Timezone:              u.Timezone,
 	}
 }
 
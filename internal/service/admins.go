@@ -9,6 +9,7 @@ import (
 type AdminsRepository interface {
 	Create(ctx context.Context, admin model.Admin) error
 	GetByUsername(ctx context.Context, username string) (model.Admin, error)
+	Save(ctx context.Context, admin model.Admin) error
 }
 
 type AdminsService struct {
@@ -22,3 +23,9 @@ func NewAdminsService(adminRepo AdminsRepository) *AdminsService {
 func (s *AdminsService) Create(ctx context.Context, admin model.Admin) error {
 	return s.adminsRepo.Create(ctx, admin)
 }
+
+// Save creates the admin record or updates the role of the existing one, it
+// keeps the records of accounts managed by an identity provider in sync.
+func (s *AdminsService) Save(ctx context.Context, admin model.Admin) error {
+	return s.adminsRepo.Save(ctx, admin)
+}
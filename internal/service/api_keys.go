@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+// apiKeyPrefix marks api keys, so that a leaked one is easy to recognize.
+const apiKeyPrefix = "xpl_"
+
+type APIKeysRepository interface {
+	Create(ctx context.Context, key model.APIKey) (string, error)
+	GetAll(ctx context.Context) ([]model.APIKey, error)
+	GetByKey(ctx context.Context, key string) (model.APIKey, error)
+	Revoke(ctx context.Context, id string) error
+	UpdateLastUsed(ctx context.Context, id string, lastUsedAt time.Time) error
+}
+
+type APIKeysService struct {
+	repo APIKeysRepository
+}
+
+func NewAPIKeysService(repo APIKeysRepository) *APIKeysService {
+	return &APIKeysService{repo: repo}
+}
+
+// Create issues a new api key. The key itself is returned only here, the
+// creator must have every permission it grants.
+func (s *APIKeysService) Create(ctx context.Context, creator model.Credentials, name string, permissions []model.Permission) (string, model.APIKey, error) {
+	if name == "" {
+		return "", model.APIKey{}, model.ErrEmptyAPIKeyName
+	}
+
+	if len(permissions) == 0 {
+		return "", model.APIKey{}, model.ErrWrongPermission
+	}
+	for _, p := range permissions {
+		if !p.ForAPIKeys() || !creator.Role.Can(p) {
+			return "", model.APIKey{}, fmt.Errorf("%w: %s", model.ErrWrongPermission, p)
+		}
+	}
+
+	token, err := newRandomToken()
+	if err != nil {
+		return "", model.APIKey{}, err
+	}
+	key := apiKeyPrefix + token
+
+	apiKey := model.APIKey{
+		Name:        name,
+		Prefix:      key[:len(apiKeyPrefix)+6],
+		Key:         hashToken(key),
+		Permissions: permissions,
+		CreatedBy:   creator.Username,
+		CreatedAt:   time.Now(),
+	}
+	apiKey.ID, err = s.repo.Create(ctx, apiKey)
+	if err != nil {
+		return "", model.APIKey{}, err
+	}
+
+	return key, apiKey, nil
+}
+
+func (s *APIKeysService) GetAll(ctx context.Context) ([]model.APIKey, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *APIKeysService) Revoke(ctx context.Context, id string) error {
+	return s.repo.Revoke(ctx, id)
+}
+
+// CheckAccess authenticates the api key and checks that it has every one of
+// the given permissions. Endpoints without permissions act on the signed in
+// account and are closed to api keys. The credentials carry only the key id.
+func (s *APIKeysService) CheckAccess(ctx context.Context, key string, permissions ...model.Permission) (model.Credentials, error) {
+	if len(permissions) == 0 {
+		return model.Credentials{}, model.ErrWrongRole
+	}
+
+	apiKey, err := s.repo.GetByKey(ctx, hashToken(key))
+	if errors.Is(err, model.ErrAPIKeyNotFound) {
+		return model.Credentials{}, model.ErrInvalidAccessToken
+	}
+	if err != nil {
+		return model.Credentials{}, err
+	}
+
+	if apiKey.Revoked {
+		return model.Credentials{}, fmt.Errorf("%w: api key is revoked", model.ErrInvalidAccessToken)
+	}
+
+	for _, p := range permissions {
+		if !apiKey.Can(p) {
+			return model.Credentials{}, model.ErrWrongRole
+		}
+	}
+
+	if err := s.repo.UpdateLastUsed(ctx, apiKey.ID, time.Now()); err != nil {
+		return model.Credentials{}, err
+	}
+
+	return model.Credentials{APIKeyID: apiKey.ID}, nil
+}
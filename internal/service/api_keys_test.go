@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+	"github.com/Andrei-Raev/xp-loyalty/internal/model/mocks"
+)
+
+func TestAPIKeysService(t *testing.T) {
+	ctx := context.Background()
+	moderator := model.Credentials{
+		CredentialsSecure: model.CredentialsSecure{Username: "moderator", Role: model.RoleModerator},
+	}
+
+	repo := new(mocks.APIKeysRepositoryMock)
+	var stored model.APIKey
+	repo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(model.APIKey)
+	}).Return("1", nil)
+	s := NewAPIKeysService(repo)
+
+	t.Run("permission not for api keys", func(t *testing.T) {
+		_, _, err := s.Create(ctx, moderator, "bar", []model.Permission{model.PermAdminsWrite})
+		require.ErrorIs(t, err, model.ErrWrongPermission)
+	})
+
+	t.Run("permission the creator doesn't have", func(t *testing.T) {
+		cashier := model.Credentials{CredentialsSecure: model.CredentialsSecure{Role: model.RoleCashier}}
+		_, _, err := s.Create(ctx, cashier, "bar", []model.Permission{model.PermUsersRead})
+		require.ErrorIs(t, err, model.ErrWrongPermission)
+	})
+
+	key, apiKey, err := s.Create(ctx, moderator, "bar", []model.Permission{model.PermCardsComplete})
+	require.NoError(t, err)
+	require.Equal(t, "1", apiKey.ID)
+	require.NotEqual(t, key, stored.Key, "only the hash is stored")
+	require.Equal(t, key[:len(stored.Prefix)], stored.Prefix)
+
+	stored.ID = "1"
+	repo.On("GetByKey", mock.Anything, hashToken(key)).Return(stored, nil)
+	repo.On("UpdateLastUsed", mock.Anything, "1", mock.Anything).Return(nil)
+
+	t.Run("key with permission", func(t *testing.T) {
+		credentials, err := s.CheckAccess(ctx, key, model.PermCardsComplete)
+		require.NoError(t, err)
+		require.Equal(t, "1", credentials.APIKeyID)
+	})
+
+	t.Run("key without permission", func(t *testing.T) {
+		_, err := s.CheckAccess(ctx, key, model.PermCardsStaticWrite)
+		require.ErrorIs(t, err, model.ErrWrongRole)
+	})
+
+	t.Run("endpoint of the signed in account", func(t *testing.T) {
+		_, err := s.CheckAccess(ctx, key)
+		require.ErrorIs(t, err, model.ErrWrongRole)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		repo.On("GetByKey", mock.Anything, hashToken("unknown")).Return(model.APIKey{}, model.ErrAPIKeyNotFound)
+
+		_, err := s.CheckAccess(ctx, "unknown", model.PermCardsComplete)
+		require.ErrorIs(t, err, model.ErrInvalidAccessToken)
+	})
+}
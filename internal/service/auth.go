@@ -2,29 +2,72 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
 	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+	"github.com/Andrei-Raev/xp-loyalty/pkg/config"
 	"github.com/golang-jwt/jwt"
+	"golang.org/x/crypto/bcrypt"
 )
 
+// challengeAudience is the audience of sign in challenge tokens. It keeps them
+// from being accepted as access tokens and the other way round.
+const challengeAudience = "two-factor"
+
+// legacySalt is the global salt of the old SHA-1 password hashes. It is only
+// used to verify such hashes before they are upgraded to bcrypt on sign in.
+const legacySalt = "authSecertSalt"
+
 type CredentialsRepository interface {
 	Create(ctx context.Context, credentials model.Credentials) (string, error)
 	GetByUsername(ctx context.Context, username string) (model.Credentials, error)
-	GetByCredentials(ctx context.Context, username, password string) (model.Credentials, error)
+	GetByIdentity(ctx context.Context, issuer, subject string) (model.Credentials, error)
+	UpdatePassword(ctx context.Context, id, password string) error
+	UpdateSecondFactor(ctx context.Context, id string, factor model.SecondFactor) error
+	UpdateRole(ctx context.Context, id string, role model.Role) error
+}
+
+type SessionsRepository interface {
+	Create(ctx context.Context, session model.Session) (string, error)
+	Get(ctx context.Context, id string) (model.Session, error)
+	GetByRefreshToken(ctx context.Context, refreshToken string) (model.Session, error)
+	Update(ctx context.Context, session model.Session) error
+	RevokeByUsername(ctx context.Context, username string) error
+}
+
+type PasswordResetsRepository interface {
+	Create(ctx context.Context, reset model.PasswordReset) error
+	GetByToken(ctx context.Context, token string) (model.PasswordReset, error)
+	Update(ctx context.Context, reset model.PasswordReset) error
 }
 
 type AuthService struct {
 	repo              CredentialsRepository
-	moderatorUsername string
-	moderatorPassword string
-	salt              string
-	signingKey        []byte
+	sessionsRepo      SessionsRepository
+	resetsRepo        PasswordResetsRepository
+	passwordCost      int
+	signingKeyID      string
+	signingKeys       map[string][]byte
+	issuer            string
+	audience          string
 	expireDuration    time.Duration
+	refreshDuration   time.Duration
+	resetDuration     time.Duration
+	challengeDuration time.Duration
+	totpIssuer        string
+	totpRoles         map[model.Role]bool
+	// dummyHash is compared against for unknown accounts,
+	// so they take as long to reject as a wrong password
+	dummyHash []byte
 }
 
 type AuthClaims struct {
@@ -32,26 +75,101 @@ type AuthClaims struct {
 	Credentials model.Credentials
 }
 
-func NewAuthService(credentialsRepo CredentialsRepository, moderatorUsername, moderatorPassword string) *AuthService {
+type ChallengeClaims struct {
+	jwt.StandardClaims
+	EnrollmentRequired bool `json:"enrollment_required,omitempty"`
+}
+
+func NewAuthService(credentialsRepo CredentialsRepository, sessionsRepo SessionsRepository, resetsRepo PasswordResetsRepository, cfg config.Auth) (*AuthService, error) {
+	signingKeys := make(map[string][]byte, len(cfg.SigningKeys))
+	for _, k := range cfg.SigningKeys {
+		if k.ID == "" || k.Key == "" {
+			return nil, errors.New("signing key must have id and key")
+		}
+		signingKeys[k.ID] = []byte(k.Key)
+	}
+
+	if _, ok := signingKeys[cfg.SigningKeyID]; !ok {
+		return nil, fmt.Errorf("no signing key with id %q", cfg.SigningKeyID)
+	}
+
+	passwordCost := cfg.PasswordCost
+	if passwordCost == 0 {
+		passwordCost = bcrypt.DefaultCost
+	}
+
+	expireDuration := cfg.TokenTTL.Duration
+	if expireDuration == 0 {
+		expireDuration = 15 * time.Minute
+	}
+
+	refreshDuration := cfg.RefreshTokenTTL.Duration
+	if refreshDuration == 0 {
+		refreshDuration = 30 * 24 * time.Hour
+	}
+
+	resetDuration := cfg.ResetTokenTTL.Duration
+	if resetDuration == 0 {
+		resetDuration = time.Hour
+	}
+
+	challengeDuration := cfg.TwoFactor.ChallengeTTL.Duration
+	if challengeDuration == 0 {
+		challengeDuration = 5 * time.Minute
+	}
+
+	totpRoles := make(map[model.Role]bool, len(cfg.TwoFactor.RequiredRoles))
+	for _, r := range cfg.TwoFactor.RequiredRoles {
+		totpRoles[model.Role(r)] = true
+	}
+
+	dummyHash, err := bcrypt.GenerateFromPassword([]byte(legacySalt), passwordCost)
+	if err != nil {
+		return nil, fmt.Errorf("error hashing password: %w", err)
+	}
+
 	return &AuthService{
 		repo:              credentialsRepo,
-		moderatorUsername: moderatorUsername,
-		moderatorPassword: moderatorPassword,
-		salt:              "authSecertSalt",
-		signingKey:        []byte("authSecretSigningKey"),
-		expireDuration:    24 * time.Hour,
-	}
+		sessionsRepo:      sessionsRepo,
+		resetsRepo:        resetsRepo,
+		passwordCost:      passwordCost,
+		signingKeyID:      cfg.SigningKeyID,
+		signingKeys:       signingKeys,
+		issuer:            cfg.Issuer,
+		audience:          cfg.Audience,
+		expireDuration:    expireDuration,
+		refreshDuration:   refreshDuration,
+		resetDuration:     resetDuration,
+		challengeDuration: challengeDuration,
+		totpIssuer:        cfg.TwoFactor.Issuer,
+		totpRoles:         totpRoles,
+		dummyHash:         dummyHash,
+	}, nil
 }
 
-func (s *AuthService) SignUp(ctx context.Context, credentials model.Credentials) (string, error) {
-	if credentials.Username == s.moderatorUsername {
-		return "", model.ErrUserExists
+// SeedModerator creates the moderator account on first boot. The returned id
+// is empty if the account already exists, its password is never overwritten.
+func (s *AuthService) SeedModerator(ctx context.Context, username, password string) (string, error) {
+	if username == "" || password == "" {
+		return "", nil
 	}
 
-	pwd := sha1.New()
-	pwd.Write([]byte(credentials.Password))
-	pwd.Write([]byte(s.salt))
+	credentials := model.Credentials{
+		CredentialsSecure: model.CredentialsSecure{
+			Username: username,
+			Role:     model.RoleModerator,
+		},
+		Password: password,
+	}
+
+	id, err := s.SignUp(ctx, credentials)
+	if errors.Is(err, model.ErrUserExists) {
+		return "", nil
+	}
+	return id, err
+}
 
+func (s *AuthService) SignUp(ctx context.Context, credentials model.Credentials) (string, error) {
 	_, err := s.repo.GetByUsername(ctx, credentials.Username)
 	switch {
 	case errors.Is(err, model.ErrUserNotFound):
@@ -61,45 +179,378 @@ func (s *AuthService) SignUp(ctx context.Context, credentials model.Credentials)
 		return "", err
 	}
 
-	credentials.Password = fmt.Sprintf("%x", pwd.Sum(nil))
+	credentials.Password, err = s.hashPassword(credentials.Password)
+	if err != nil {
+		return "", err
+	}
 	return s.repo.Create(ctx, credentials)
 }
 
-func (s *AuthService) SignIn(ctx context.Context, username, password string) (string, error) {
-	var err error
+// SignIn checks the password. Accounts with two-factor authentication get a
+// challenge instead of tokens, it is completed by VerifySecondFactor.
+func (s *AuthService) SignIn(ctx context.Context, username, password string) (model.Tokens, *model.Challenge, error) {
+	credentials, err := s.repo.GetByUsername(ctx, username)
+	if err != nil && !errors.Is(err, model.ErrUserNotFound) {
+		return model.Tokens{}, nil, err
+	}
+
+	// unknown accounts and accounts of an identity provider, which have no
+	// password, are rejected like a wrong password and after as much work
+	if err != nil || credentials.Issuer != "" {
+		_ = bcrypt.CompareHashAndPassword(s.dummyHash, []byte(password))
+		return model.Tokens{}, nil, model.ErrWrongPassword
+	}
+
+	if err := s.verifyPassword(ctx, credentials, password); err != nil {
+		return model.Tokens{}, nil, err
+	}
+
+	if credentials.SecondFactor.Enabled || s.totpRoles[credentials.Role] {
+		challenge, err := s.newChallenge(credentials)
+		if err != nil {
+			return model.Tokens{}, nil, err
+		}
+		return model.Tokens{}, &challenge, nil
+	}
+
+	tokens, err := s.createSession(ctx, credentials)
+	return tokens, nil, err
+}
+
+// ProvisionExternal returns the account of an identity from an external
+// provider and creates it, without a password, on first sign in. Accounts are
+// matched by the issuer and the subject of the identity, its username only
+// names a new account and must not be taken. The returned flag is set if the
+// account was created. The role given by the provider wins over the stored one.
+func (s *AuthService) ProvisionExternal(ctx context.Context, identity model.Identity) (model.Credentials, bool, error) {
+	if identity.Issuer == "" || identity.Subject == "" {
+		return model.Credentials{}, false, model.ErrInvalidIDToken
+	}
+
+	credentials, err := s.repo.GetByIdentity(ctx, identity.Issuer, identity.Subject)
+	switch {
+	case errors.Is(err, model.ErrUserNotFound):
+		return s.createExternal(ctx, identity)
+	case err != nil:
+		return model.Credentials{}, false, err
+	}
+
+	if credentials.Role != identity.Role {
+		if err := s.repo.UpdateRole(ctx, credentials.ID, identity.Role); err != nil {
+			return model.Credentials{}, false, err
+		}
+		credentials.Role = identity.Role
+	}
+
+	return credentials, false, nil
+}
+
+// createExternal creates the account of an identity seen for the first time,
+// accounts with its username, local or of another subject, are never taken over.
+func (s *AuthService) createExternal(ctx context.Context, identity model.Identity) (model.Credentials, bool, error) {
+	_, err := s.repo.GetByUsername(ctx, identity.Username)
+	switch {
+	case err == nil:
+		return model.Credentials{}, false, model.ErrUserExists
+	case !errors.Is(err, model.ErrUserNotFound):
+		return model.Credentials{}, false, err
+	}
+
 	credentials := model.Credentials{
-		Password: password,
 		CredentialsSecure: model.CredentialsSecure{
-			Username: username,
-			Role:     model.RoleModerator,
+			Username: identity.Username,
+			Role:     identity.Role,
 		},
+		Issuer:  identity.Issuer,
+		Subject: identity.Subject,
+	}
+	credentials.ID, err = s.repo.Create(ctx, credentials)
+	if err != nil {
+		return model.Credentials{}, false, err
 	}
+	return credentials, true, nil
+}
+
+// SignInExternal starts a session for an account returned by ProvisionExternal.
+// The provider is responsible for the second factor of such accounts.
+func (s *AuthService) SignInExternal(ctx context.Context, credentials model.Credentials) (model.Tokens, error) {
+	return s.createSession(ctx, credentials)
+}
 
-	if username != s.moderatorUsername || password != s.moderatorPassword {
-		pwd := sha1.New()
-		pwd.Write([]byte(password))
-		pwd.Write([]byte(s.salt))
-		password = fmt.Sprintf("%x", pwd.Sum(nil))
+// ParseChallenge validates a challenge token returned by SignIn.
+func (s *AuthService) ParseChallenge(challengeToken string) (model.Challenge, error) {
+	token, err := jwt.ParseWithClaims(challengeToken, &ChallengeClaims{}, s.signingKey)
+	if err != nil {
+		return model.Challenge{}, fmt.Errorf("%w: %s", model.ErrInvalidChallenge, err)
+	}
+
+	claims, ok := token.Claims.(*ChallengeClaims)
+	if !ok || !token.Valid {
+		return model.Challenge{}, model.ErrInvalidChallenge
+	}
+
+	if !claims.VerifyIssuer(s.issuer, s.issuer != "") ||
+		!claims.VerifyAudience(challengeAudience, true) ||
+		claims.Subject == "" {
+		return model.Challenge{}, model.ErrInvalidChallenge
+	}
+
+	return model.Challenge{
+		Token:              challengeToken,
+		Username:           claims.Subject,
+		EnrollmentRequired: claims.EnrollmentRequired,
+		ExpiresAt:          time.Unix(claims.ExpiresAt, 0),
+	}, nil
+}
+
+// VerifySecondFactor completes a sign in challenge with a TOTP or recovery
+// code. If the account has not enrolled yet the code confirms the enrollment
+// started by EnrollSecondFactor and the new recovery codes are returned too.
+func (s *AuthService) VerifySecondFactor(ctx context.Context, challenge model.Challenge, code string) (model.Tokens, []string, error) {
+	credentials, err := s.repo.GetByUsername(ctx, challenge.Username)
+	if err != nil {
+		return model.Tokens{}, nil, err
+	}
 
-		credentials, err = s.repo.GetByCredentials(ctx, username, password)
+	var recoveryCodes []string
+	if credentials.SecondFactor.Enabled {
+		factor, err := checkSecondFactor(credentials.SecondFactor, code)
 		if err != nil {
-			return "", err
+			return model.Tokens{}, nil, err
+		}
+		if err := s.repo.UpdateSecondFactor(ctx, credentials.ID, factor); err != nil {
+			return model.Tokens{}, nil, err
+		}
+	} else {
+		recoveryCodes, err = s.enableSecondFactor(ctx, credentials, code)
+		if err != nil {
+			return model.Tokens{}, nil, err
 		}
 	}
 
-	credentials.Password = ""
-	claims := AuthClaims{
-		Credentials: credentials,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(s.expireDuration).Unix(),
-		},
+	tokens, err := s.createSession(ctx, credentials)
+	if err != nil {
+		return model.Tokens{}, nil, err
 	}
+	return tokens, recoveryCodes, nil
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(s.signingKey)
+// EnrollSecondFactor starts TOTP enrollment and returns the secret together
+// with an otpauth URL for authenticator apps. Starting again replaces the
+// secret of an enrollment which is not confirmed yet.
+func (s *AuthService) EnrollSecondFactor(ctx context.Context, username string) (string, string, error) {
+	credentials, err := s.repo.GetByUsername(ctx, username)
+	if err != nil {
+		return "", "", err
+	}
+
+	if credentials.SecondFactor.Enabled {
+		return "", "", model.ErrSecondFactorEnabled
+	}
+
+	secret, err := newTOTPSecret()
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := s.repo.UpdateSecondFactor(ctx, credentials.ID, model.SecondFactor{Secret: secret}); err != nil {
+		return "", "", err
+	}
+
+	return secret, totpURL(s.totpIssuer, credentials.Username, secret), nil
+}
+
+// EnableSecondFactor confirms the enrollment with a code from the
+// authenticator app and returns the recovery codes, they are shown only once.
+func (s *AuthService) EnableSecondFactor(ctx context.Context, username, code string) ([]string, error) {
+	credentials, err := s.repo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.enableSecondFactor(ctx, credentials, code)
+}
+
+// DisableSecondFactor removes TOTP from the account after checking a TOTP or
+// recovery code. Roles which require the second factor can't disable it.
+func (s *AuthService) DisableSecondFactor(ctx context.Context, username, code string) error {
+	credentials, err := s.repo.GetByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if s.totpRoles[credentials.Role] {
+		return model.ErrSecondFactorRequired
+	}
+
+	if !credentials.SecondFactor.Enabled {
+		return model.ErrSecondFactorNotEnrolled
+	}
+
+	if _, err := checkSecondFactor(credentials.SecondFactor, code); err != nil {
+		return err
+	}
+
+	return s.repo.UpdateSecondFactor(ctx, credentials.ID, model.SecondFactor{})
+}
+
+func (s *AuthService) createSession(ctx context.Context, credentials model.Credentials) (model.Tokens, error) {
+	refreshToken, err := newRandomToken()
+	if err != nil {
+		return model.Tokens{}, err
+	}
+
+	now := time.Now()
+	session := model.Session{
+		Username:     credentials.Username,
+		RefreshToken: hashToken(refreshToken),
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(s.refreshDuration),
+	}
+	session.ID, err = s.sessionsRepo.Create(ctx, session)
+	if err != nil {
+		return model.Tokens{}, err
+	}
+
+	accessToken, err := s.newToken(credentials, session.ID)
+	if err != nil {
+		return model.Tokens{}, err
+	}
+
+	return model.Tokens{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// Refresh exchanges a refresh token for a new token pair. The refresh token is
+// rotated, so every refresh token can be used only once.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (model.Tokens, error) {
+	session, err := s.sessionsRepo.GetByRefreshToken(ctx, hashToken(refreshToken))
+	if errors.Is(err, model.ErrSessionNotFound) {
+		return model.Tokens{}, model.ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return model.Tokens{}, err
+	}
+
+	now := time.Now()
+	if session.Revoked || now.After(session.ExpiresAt) {
+		return model.Tokens{}, model.ErrInvalidRefreshToken
+	}
+
+	credentials, err := s.repo.GetByUsername(ctx, session.Username)
+	if err != nil {
+		return model.Tokens{}, err
+	}
+
+	refreshToken, err = newRandomToken()
+	if err != nil {
+		return model.Tokens{}, err
+	}
+
+	session.RefreshToken = hashToken(refreshToken)
+	session.ExpiresAt = now.Add(s.refreshDuration)
+	if err := s.sessionsRepo.Update(ctx, session); err != nil {
+		return model.Tokens{}, err
+	}
+
+	accessToken, err := s.newToken(credentials, session.ID)
+	if err != nil {
+		return model.Tokens{}, err
+	}
+
+	return model.Tokens{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// SignOut revokes the session, its access and refresh tokens stop working.
+func (s *AuthService) SignOut(ctx context.Context, sessionID string) error {
+	session, err := s.sessionsRepo.Get(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	session.Revoked = true
+	return s.sessionsRepo.Update(ctx, session)
+}
+
+func (s *AuthService) ChangePassword(ctx context.Context, username, oldPassword, newPassword string) error {
+	if newPassword == "" {
+		return model.ErrEmptyPassword
+	}
+
+	credentials, err := s.repo.GetByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if err := s.verifyPassword(ctx, credentials, oldPassword); err != nil {
+		return err
+	}
+
+	return s.setPassword(ctx, credentials, newPassword)
 }
 
-func (s *AuthService) CheckAccess(ctx context.Context, token string, role model.Role) (model.Credentials, error) {
+// IssuePasswordReset creates a single-use reset token for username. The issuer
+// must have every permission of the account it resets and some more, so
+// accounts of the same role can't take each other over.
+func (s *AuthService) IssuePasswordReset(ctx context.Context, issuer model.Credentials, username string) (string, time.Time, error) {
+	credentials, err := s.repo.GetByUsername(ctx, username)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	if !issuer.Role.Above(credentials.Role) {
+		return "", time.Time{}, model.ErrWrongRole
+	}
+
+	token, err := newRandomToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	now := time.Now()
+	reset := model.PasswordReset{
+		Username:  credentials.Username,
+		Token:     hashToken(token),
+		CreatedBy: issuer.Username,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.resetDuration),
+	}
+	if err := s.resetsRepo.Create(ctx, reset); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, reset.ExpiresAt, nil
+}
+
+func (s *AuthService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if newPassword == "" {
+		return model.ErrEmptyPassword
+	}
+
+	reset, err := s.resetsRepo.GetByToken(ctx, hashToken(token))
+	if err != nil {
+		return err
+	}
+
+	if reset.Used || time.Now().After(reset.ExpiresAt) {
+		return model.ErrInvalidResetToken
+	}
+
+	reset.Used = true
+	if err := s.resetsRepo.Update(ctx, reset); err != nil {
+		return err
+	}
+
+	credentials, err := s.repo.GetByUsername(ctx, reset.Username)
+	if err != nil {
+		return err
+	}
+
+	return s.setPassword(ctx, credentials, newPassword)
+}
+
+// CheckAccess authenticates the token and checks that its role has every one
+// of the given permissions.
+func (s *AuthService) CheckAccess(ctx context.Context, token string, permissions ...model.Permission) (model.Credentials, error) {
 	tokenParts := strings.Split(token, " ")
 	if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
 		return model.Credentials{}, model.ErrInvalidAccessToken
@@ -110,27 +561,243 @@ func (s *AuthService) CheckAccess(ctx context.Context, token string, role model.
 		return model.Credentials{}, err
 	}
 
-	if credentials.Role < role {
-		return model.Credentials{}, model.ErrWrongRole
+	session, err := s.sessionsRepo.Get(ctx, credentials.SessionID)
+	if errors.Is(err, model.ErrSessionNotFound) {
+		return model.Credentials{}, model.ErrInvalidAccessToken
+	}
+	if err != nil {
+		return model.Credentials{}, err
+	}
+
+	if session.Revoked {
+		return model.Credentials{}, fmt.Errorf("%w: session is revoked", model.ErrInvalidAccessToken)
+	}
+
+	for _, p := range permissions {
+		if !credentials.Role.Can(p) {
+			return model.Credentials{}, model.ErrWrongRole
+		}
 	}
 
 	return credentials, nil
 }
 
+func (s *AuthService) newToken(credentials model.Credentials, sessionID string) (string, error) {
+	now := time.Now()
+
+	credentials.Password = ""
+	credentials.SessionID = ""
+	credentials.SecondFactor = model.SecondFactor{}
+	claims := AuthClaims{
+		Credentials: credentials,
+		StandardClaims: jwt.StandardClaims{
+			Issuer:    s.issuer,
+			Audience:  s.audience,
+			Subject:   credentials.Username,
+			Id:        sessionID,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(s.expireDuration).Unix(),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = s.signingKeyID
+	return token.SignedString(s.signingKeys[s.signingKeyID])
+}
+
 func (s *AuthService) parseToken(accessToken string) (model.Credentials, error) {
-	token, err := jwt.ParseWithClaims(accessToken, &AuthClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return model.Credentials{}, model.ErrUnexpectedSigningMethod
+	token, err := jwt.ParseWithClaims(accessToken, &AuthClaims{}, s.signingKey)
+	if err != nil {
+		return model.Credentials{}, fmt.Errorf("%w: %s", model.ErrInvalidAccessToken, err)
+	}
+
+	claims, ok := token.Claims.(*AuthClaims)
+	if !ok || !token.Valid {
+		return model.Credentials{}, model.ErrInvalidAccessToken
+	}
+
+	if !claims.VerifyIssuer(s.issuer, s.issuer != "") ||
+		!claims.VerifyAudience(s.audience, s.audience != "") ||
+		claims.Subject != claims.Credentials.Username ||
+		claims.Id == "" {
+		return model.Credentials{}, model.ErrInvalidAccessToken
+	}
+
+	credentials := claims.Credentials
+	credentials.SessionID = claims.Id
+	return credentials, nil
+}
+
+func (s *AuthService) newChallenge(credentials model.Credentials) (model.Challenge, error) {
+	now := time.Now()
+	challenge := model.Challenge{
+		Username:           credentials.Username,
+		EnrollmentRequired: !credentials.SecondFactor.Enabled,
+		ExpiresAt:          now.Add(s.challengeDuration),
+	}
+
+	claims := ChallengeClaims{
+		EnrollmentRequired: challenge.EnrollmentRequired,
+		StandardClaims: jwt.StandardClaims{
+			Issuer:    s.issuer,
+			Audience:  challengeAudience,
+			Subject:   credentials.Username,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: challenge.ExpiresAt.Unix(),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = s.signingKeyID
+
+	var err error
+	challenge.Token, err = token.SignedString(s.signingKeys[s.signingKeyID])
+	if err != nil {
+		return model.Challenge{}, err
+	}
+	return challenge, nil
+}
+
+// signingKey is the jwt.Keyfunc of access and challenge tokens.
+func (s *AuthService) signingKey(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, model.ErrUnexpectedSigningMethod
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := s.signingKeys[kid]
+	if !ok {
+		return nil, model.ErrUnknownSigningKey
+	}
+	return key, nil
+}
+
+func (s *AuthService) enableSecondFactor(ctx context.Context, credentials model.Credentials, code string) ([]string, error) {
+	factor := credentials.SecondFactor
+	if factor.Enabled {
+		return nil, model.ErrSecondFactorEnabled
+	}
+	if factor.Secret == "" {
+		return nil, model.ErrSecondFactorNotEnrolled
+	}
+
+	step, ok := validateTOTP(factor.Secret, code, time.Now(), factor.LastStep)
+	if !ok {
+		return nil, model.ErrWrongCode
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	factor.Enabled = true
+	factor.RecoveryCodes = hashes
+	factor.LastStep = step
+	if err := s.repo.UpdateSecondFactor(ctx, credentials.ID, factor); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// checkSecondFactor accepts a TOTP code or one of the recovery codes and
+// returns the factor to store, with the step or the recovery code used up.
+func checkSecondFactor(factor model.SecondFactor, code string) (model.SecondFactor, error) {
+	if step, ok := validateTOTP(factor.Secret, code, time.Now(), factor.LastStep); ok {
+		factor.LastStep = step
+		return factor, nil
+	}
+
+	hash := hashToken(normalizeRecoveryCode(code))
+	for i, h := range factor.RecoveryCodes {
+		if subtle.ConstantTimeCompare([]byte(h), []byte(hash)) == 1 {
+			codes := make([]string, 0, len(factor.RecoveryCodes)-1)
+			codes = append(codes, factor.RecoveryCodes[:i]...)
+			factor.RecoveryCodes = append(codes, factor.RecoveryCodes[i+1:]...)
+			return factor, nil
 		}
-		return s.signingKey, nil
-	})
+	}
+
+	return model.SecondFactor{}, model.ErrWrongCode
+}
+
+func (s *AuthService) hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), s.passwordCost)
 	if err != nil {
-		return model.Credentials{}, err
+		return "", fmt.Errorf("error hashing password: %w", err)
 	}
+	return string(hash), nil
+}
 
-	if claims, ok := token.Claims.(*AuthClaims); ok && token.Valid {
-		return claims.Credentials, nil
+// verifyPassword compares password with the stored hash. Legacy SHA-1 hashes
+// and bcrypt hashes with an outdated cost are rehashed after a successful match,
+// a failed rehash is only logged since the password did match.
+func (s *AuthService) verifyPassword(ctx context.Context, credentials model.Credentials, password string) error {
+	if isLegacyHash(credentials.Password) {
+		if subtle.ConstantTimeCompare([]byte(legacyHash(password)), []byte(credentials.Password)) != 1 {
+			return model.ErrWrongPassword
+		}
+		s.upgradePassword(ctx, credentials.ID, password)
+		return nil
 	}
 
-	return model.Credentials{}, model.ErrInvalidAccessToken
+	err := bcrypt.CompareHashAndPassword([]byte(credentials.Password), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return model.ErrWrongPassword
+	}
+	if err != nil {
+		return err
+	}
+
+	if cost, err := bcrypt.Cost([]byte(credentials.Password)); err == nil && cost != s.passwordCost {
+		s.upgradePassword(ctx, credentials.ID, password)
+	}
+	return nil
+}
+
+func (s *AuthService) upgradePassword(ctx context.Context, id, password string) {
+	if err := s.rehashPassword(ctx, id, password); err != nil {
+		log.Printf("error upgrading password hash of %s: %v", id, err)
+	}
+}
+
+// setPassword replaces the password and signs the account out everywhere.
+func (s *AuthService) setPassword(ctx context.Context, credentials model.Credentials, password string) error {
+	if err := s.rehashPassword(ctx, credentials.ID, password); err != nil {
+		return err
+	}
+	return s.sessionsRepo.RevokeByUsername(ctx, credentials.Username)
+}
+
+func (s *AuthService) rehashPassword(ctx context.Context, id, password string) error {
+	hash, err := s.hashPassword(password)
+	if err != nil {
+		return err
+	}
+	return s.repo.UpdatePassword(ctx, id, hash)
+}
+
+func isLegacyHash(hash string) bool {
+	return !strings.HasPrefix(hash, "$")
+}
+
+func legacyHash(password string) string {
+	pwd := sha1.New()
+	pwd.Write([]byte(password))
+	pwd.Write([]byte(legacySalt))
+	return fmt.Sprintf("%x", pwd.Sum(nil))
+}
+
+// newRandomToken returns an opaque token for refresh and reset tokens.
+func newRandomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken returns the form in which refresh and reset tokens are stored.
+func hashToken(token string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(token)))
 }
@@ -0,0 +1,398 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+	"github.com/Andrei-Raev/xp-loyalty/internal/model/mocks"
+	"github.com/Andrei-Raev/xp-loyalty/pkg/config"
+)
+
+func testAuthConfig(keyID string) config.Auth {
+	return config.Auth{
+		PasswordCost: bcrypt.MinCost,
+		Issuer:       "xp-loyalty",
+		Audience:     "xp-loyalty-api",
+		SigningKeyID: keyID,
+		SigningKeys: []config.SigningKey{
+			{ID: "old", Key: "old-secret"},
+			{ID: "new", Key: "new-secret"},
+		},
+	}
+}
+
+func testModeratorRepo(t *testing.T) *mocks.CredentialsRepositoryMock {
+	hash, err := bcrypt.GenerateFromPassword([]byte("moderator"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	repo := new(mocks.CredentialsRepositoryMock)
+	repo.On("GetByUsername", mock.Anything, "moderator").Return(model.Credentials{
+		CredentialsSecure: model.CredentialsSecure{ID: "1", Username: "moderator", Role: model.RoleModerator},
+		Password:          string(hash),
+	}, nil)
+	return repo
+}
+
+func TestAuthService_SignIn(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		stored    string
+		password  string
+		missing   bool
+		rehashErr error
+		wantErr   error
+		rehashed  bool
+	}{
+		{
+			name:     "legacy hash is upgraded",
+			stored:   legacyHash("password"),
+			password: "password",
+			rehashed: true,
+		},
+		{
+			name:      "failed upgrade still signs in",
+			stored:    legacyHash("password"),
+			password:  "password",
+			rehashErr: errors.New("db is down"),
+			rehashed:  true,
+		},
+		{
+			name:     "unknown user looks like wrong password",
+			password: "password",
+			missing:  true,
+			wantErr:  model.ErrWrongPassword,
+		},
+		{
+			name:     "legacy hash wrong password",
+			stored:   legacyHash("password"),
+			password: "wrong",
+			wantErr:  model.ErrWrongPassword,
+		},
+		{
+			name:     "bcrypt hash with current cost",
+			stored:   string(hash),
+			password: "password",
+		},
+		{
+			name:     "bcrypt hash wrong password",
+			stored:   string(hash),
+			password: "wrong",
+			wantErr:  model.ErrWrongPassword,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.CredentialsRepositoryMock)
+			if tt.missing {
+				repo.On("GetByUsername", mock.Anything, "user").Return(model.Credentials{}, model.ErrUserNotFound)
+			} else {
+				repo.On("GetByUsername", mock.Anything, "user").Return(model.Credentials{
+					CredentialsSecure: model.CredentialsSecure{ID: "1", Username: "user", Role: model.RoleUser},
+					Password:          tt.stored,
+				}, nil)
+			}
+			repo.On("UpdatePassword", mock.Anything, "1", mock.MatchedBy(func(h string) bool {
+				return bcrypt.CompareHashAndPassword([]byte(h), []byte(tt.password)) == nil
+			})).Return(tt.rehashErr)
+
+			sessionsRepo := new(mocks.SessionsRepositoryMock)
+			sessionsRepo.On("Create", mock.Anything, mock.Anything).Return("session", nil)
+
+			s, err := NewAuthService(repo, sessionsRepo, nil, testAuthConfig("new"))
+			require.NoError(t, err)
+
+			_, _, err = s.SignIn(context.Background(), "user", tt.password)
+			require.ErrorIs(t, err, tt.wantErr)
+
+			if tt.rehashed {
+				repo.AssertCalled(t, "UpdatePassword", mock.Anything, "1", mock.Anything)
+			} else {
+				repo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAuthService_CheckAccess(t *testing.T) {
+	ctx := context.Background()
+
+	sessionsRepo := new(mocks.SessionsRepositoryMock)
+	sessionsRepo.On("Create", mock.Anything, mock.Anything).Return("session", nil)
+	sessionsRepo.On("Get", mock.Anything, "session").Return(model.Session{ID: "session"}, nil).Once()
+
+	repo := testModeratorRepo(t)
+	oldService, err := NewAuthService(repo, sessionsRepo, nil, testAuthConfig("old"))
+	require.NoError(t, err)
+	tokens, _, err := oldService.SignIn(ctx, "moderator", "moderator")
+	require.NoError(t, err)
+	oldToken := "Bearer " + tokens.AccessToken
+
+	s, err := NewAuthService(repo, sessionsRepo, nil, testAuthConfig("new"))
+	require.NoError(t, err)
+
+	t.Run("token signed with rotated key", func(t *testing.T) {
+		credentials, err := s.CheckAccess(ctx, oldToken, model.PermAdminsWrite)
+		require.NoError(t, err)
+		require.Equal(t, "moderator", credentials.Username)
+		require.Equal(t, "session", credentials.SessionID)
+	})
+
+	t.Run("role without permission", func(t *testing.T) {
+		sessionsRepo.On("Get", mock.Anything, "session").Return(model.Session{ID: "session"}, nil).Once()
+
+		_, err := s.CheckAccess(ctx, oldToken, model.Permission("unknown"))
+		require.ErrorIs(t, err, model.ErrWrongRole)
+	})
+
+	t.Run("revoked session", func(t *testing.T) {
+		sessionsRepo.On("Get", mock.Anything, "session").Return(model.Session{ID: "session", Revoked: true}, nil).Once()
+
+		_, err := s.CheckAccess(ctx, oldToken, model.PermAdminsWrite)
+		require.ErrorIs(t, err, model.ErrInvalidAccessToken)
+	})
+
+	t.Run("token signed with removed key", func(t *testing.T) {
+		cfg := testAuthConfig("new")
+		cfg.SigningKeys = cfg.SigningKeys[1:]
+		s, err := NewAuthService(repo, sessionsRepo, nil, cfg)
+		require.NoError(t, err)
+
+		_, err = s.CheckAccess(ctx, oldToken, model.PermAdminsWrite)
+		require.ErrorIs(t, err, model.ErrInvalidAccessToken)
+	})
+
+	t.Run("token for another audience", func(t *testing.T) {
+		cfg := testAuthConfig("new")
+		cfg.Audience = "another-api"
+		s, err := NewAuthService(repo, sessionsRepo, nil, cfg)
+		require.NoError(t, err)
+
+		_, err = s.CheckAccess(ctx, oldToken, model.PermAdminsWrite)
+		require.ErrorIs(t, err, model.ErrInvalidAccessToken)
+	})
+}
+
+func TestAuthService_Refresh(t *testing.T) {
+	ctx := context.Background()
+	session := model.Session{
+		ID:           "session",
+		Username:     "moderator",
+		RefreshToken: hashToken("refresh"),
+		ExpiresAt:    time.Now().Add(time.Hour),
+	}
+
+	sessionsRepo := new(mocks.SessionsRepositoryMock)
+	sessionsRepo.On("GetByRefreshToken", mock.Anything, hashToken("refresh")).Return(session, nil).Once()
+	sessionsRepo.On("GetByRefreshToken", mock.Anything, mock.Anything).Return(model.Session{}, model.ErrSessionNotFound)
+	sessionsRepo.On("Update", mock.Anything, mock.MatchedBy(func(s model.Session) bool {
+		return s.ID == "session" && s.RefreshToken != hashToken("refresh")
+	})).Return(nil).Once()
+
+	s, err := NewAuthService(testModeratorRepo(t), sessionsRepo, nil, testAuthConfig("new"))
+	require.NoError(t, err)
+
+	tokens, err := s.Refresh(ctx, "refresh")
+	require.NoError(t, err)
+	require.NotEqual(t, "refresh", tokens.RefreshToken)
+	require.NotEmpty(t, tokens.AccessToken)
+
+	_, err = s.Refresh(ctx, "refresh")
+	require.ErrorIs(t, err, model.ErrInvalidRefreshToken)
+	sessionsRepo.AssertExpectations(t)
+}
+
+func TestAuthService_SeedModerator(t *testing.T) {
+	ctx := context.Background()
+
+	repo := new(mocks.CredentialsRepositoryMock)
+	repo.On("GetByUsername", mock.Anything, "moderator").Return(model.Credentials{}, model.ErrUserNotFound).Once()
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(c model.Credentials) bool {
+		return c.Role == model.RoleModerator && bcrypt.CompareHashAndPassword([]byte(c.Password), []byte("moderator")) == nil
+	})).Return("1", nil).Once()
+	repo.On("GetByUsername", mock.Anything, "moderator").Return(model.Credentials{}, nil)
+
+	s, err := NewAuthService(repo, nil, nil, testAuthConfig("new"))
+	require.NoError(t, err)
+
+	id, err := s.SeedModerator(ctx, "moderator", "moderator")
+	require.NoError(t, err)
+	require.Equal(t, "1", id)
+
+	id, err = s.SeedModerator(ctx, "moderator", "changed")
+	require.NoError(t, err)
+	require.Empty(t, id)
+	repo.AssertExpectations(t)
+}
+
+func TestAuthService_ResetPassword(t *testing.T) {
+	ctx := context.Background()
+	admin := model.Credentials{CredentialsSecure: model.CredentialsSecure{Username: "admin", Role: model.RoleAdmin}}
+
+	repo := testModeratorRepo(t)
+	repo.On("GetByUsername", mock.Anything, "user").Return(model.Credentials{
+		CredentialsSecure: model.CredentialsSecure{ID: "2", Username: "user", Role: model.RoleUser},
+	}, nil)
+	repo.On("GetByUsername", mock.Anything, "admin2").Return(model.Credentials{
+		CredentialsSecure: model.CredentialsSecure{ID: "3", Username: "admin2", Role: model.RoleAdmin},
+	}, nil)
+	repo.On("UpdatePassword", mock.Anything, "2", mock.Anything).Return(nil).Once()
+
+	sessionsRepo := new(mocks.SessionsRepositoryMock)
+	sessionsRepo.On("RevokeByUsername", mock.Anything, "user").Return(nil).Once()
+
+	var stored model.PasswordReset
+	resetsRepo := new(mocks.PasswordResetsRepositoryMock)
+	resetsRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(model.PasswordReset)
+	}).Return(nil).Once()
+
+	s, err := NewAuthService(repo, sessionsRepo, resetsRepo, testAuthConfig("new"))
+	require.NoError(t, err)
+
+	_, _, err = s.IssuePasswordReset(ctx, admin, "moderator")
+	require.ErrorIs(t, err, model.ErrWrongRole)
+
+	_, _, err = s.IssuePasswordReset(ctx, admin, "admin2")
+	require.ErrorIs(t, err, model.ErrWrongRole)
+
+	token, _, err := s.IssuePasswordReset(ctx, admin, "user")
+	require.NoError(t, err)
+	require.Equal(t, hashToken(token), stored.Token)
+
+	resetsRepo.On("GetByToken", mock.Anything, stored.Token).Return(stored, nil).Once()
+	resetsRepo.On("Update", mock.Anything, mock.MatchedBy(func(r model.PasswordReset) bool { return r.Used })).Return(nil).Once()
+	require.NoError(t, s.ResetPassword(ctx, token, "new password"))
+
+	stored.Used = true
+	resetsRepo.On("GetByToken", mock.Anything, stored.Token).Return(stored, nil).Once()
+	require.ErrorIs(t, s.ResetPassword(ctx, token, "new password"), model.ErrInvalidResetToken)
+
+	repo.AssertExpectations(t)
+	sessionsRepo.AssertExpectations(t)
+	resetsRepo.AssertExpectations(t)
+}
+
+func TestAuthService_SecondFactor(t *testing.T) {
+	ctx := context.Background()
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	_, recoveryHashes, err := newRecoveryCodes()
+	require.NoError(t, err)
+	recoveryHashes[0] = hashToken("abcdefgh")
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("admin"), bcrypt.MinCost)
+	require.NoError(t, err)
+	credentials := model.Credentials{
+		CredentialsSecure: model.CredentialsSecure{ID: "1", Username: "admin", Role: model.RoleAdmin},
+		Password:          string(hash),
+		SecondFactor: model.SecondFactor{
+			Secret:        secret,
+			Enabled:       true,
+			RecoveryCodes: recoveryHashes,
+		},
+	}
+
+	newService := func(credentials model.Credentials) (*AuthService, *mocks.CredentialsRepositoryMock) {
+		repo := new(mocks.CredentialsRepositoryMock)
+		repo.On("GetByUsername", mock.Anything, "admin").Return(credentials, nil)
+
+		sessionsRepo := new(mocks.SessionsRepositoryMock)
+		sessionsRepo.On("Create", mock.Anything, mock.Anything).Return("session", nil)
+
+		cfg := testAuthConfig("new")
+		cfg.TwoFactor.RequiredRoles = []int{int(model.RoleModerator)}
+		s, err := NewAuthService(repo, sessionsRepo, nil, cfg)
+		require.NoError(t, err)
+		return s, repo
+	}
+
+	t.Run("sign in returns challenge", func(t *testing.T) {
+		s, _ := newService(credentials)
+
+		tokens, challenge, err := s.SignIn(ctx, "admin", "admin")
+		require.NoError(t, err)
+		require.Empty(t, tokens.AccessToken)
+		require.NotNil(t, challenge)
+		require.False(t, challenge.EnrollmentRequired)
+
+		parsed, err := s.ParseChallenge(challenge.Token)
+		require.NoError(t, err)
+		require.Equal(t, "admin", parsed.Username)
+
+		_, err = s.CheckAccess(ctx, "Bearer "+challenge.Token)
+		require.ErrorIs(t, err, model.ErrInvalidAccessToken)
+	})
+
+	t.Run("required role without enrollment", func(t *testing.T) {
+		s, _ := newService(credentials)
+		repo := testModeratorRepo(t)
+		s.repo = repo
+
+		_, challenge, err := s.SignIn(ctx, "moderator", "moderator")
+		require.NoError(t, err)
+		require.NotNil(t, challenge)
+		require.True(t, challenge.EnrollmentRequired)
+
+		require.ErrorIs(t, s.DisableSecondFactor(ctx, "moderator", ""), model.ErrSecondFactorRequired)
+	})
+
+	t.Run("totp code", func(t *testing.T) {
+		s, repo := newService(credentials)
+		code, err := totpCode(secret, time.Now().Unix()/totpPeriod)
+		require.NoError(t, err)
+		repo.On("UpdateSecondFactor", mock.Anything, "1", mock.MatchedBy(func(f model.SecondFactor) bool {
+			return f.LastStep > 0 && len(f.RecoveryCodes) == recoveryCodesNum
+		})).Return(nil).Once()
+
+		tokens, recoveryCodes, err := s.VerifySecondFactor(ctx, model.Challenge{Username: "admin"}, code)
+		require.NoError(t, err)
+		require.NotEmpty(t, tokens.AccessToken)
+		require.Empty(t, recoveryCodes)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("recovery code is used up", func(t *testing.T) {
+		s, repo := newService(credentials)
+		repo.On("UpdateSecondFactor", mock.Anything, "1", mock.MatchedBy(func(f model.SecondFactor) bool {
+			return len(f.RecoveryCodes) == recoveryCodesNum-1 && f.RecoveryCodes[0] == recoveryHashes[1]
+		})).Return(nil).Once()
+
+		_, _, err := s.VerifySecondFactor(ctx, model.Challenge{Username: "admin"}, "ABCD-EFGH")
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("wrong code", func(t *testing.T) {
+		s, _ := newService(credentials)
+
+		_, _, err := s.VerifySecondFactor(ctx, model.Challenge{Username: "admin"}, "000000x")
+		require.ErrorIs(t, err, model.ErrWrongCode)
+	})
+
+	t.Run("enrollment is confirmed by first code", func(t *testing.T) {
+		s, repo := newService(model.Credentials{
+			CredentialsSecure: credentials.CredentialsSecure,
+			SecondFactor:      model.SecondFactor{Secret: secret},
+		})
+		code, err := totpCode(secret, time.Now().Unix()/totpPeriod)
+		require.NoError(t, err)
+		repo.On("UpdateSecondFactor", mock.Anything, "1", mock.MatchedBy(func(f model.SecondFactor) bool {
+			return f.Enabled && f.Secret == secret && len(f.RecoveryCodes) == recoveryCodesNum
+		})).Return(nil).Once()
+
+		_, recoveryCodes, err := s.VerifySecondFactor(ctx, model.Challenge{Username: "admin"}, code)
+		require.NoError(t, err)
+		require.Len(t, recoveryCodes, recoveryCodesNum)
+		repo.AssertExpectations(t)
+	})
+}
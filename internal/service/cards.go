@@ -2,19 +2,27 @@ package service
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+	"github.com/Andrei-Raev/xp-loyalty/pkg/config"
 )
 
 type CardsRepo interface {
 	GetStatic(ctx context.Context, ids []string) (model.CardsStatic, error)
 	CreateStatic(ctx context.Context, card model.CardStatic) error
-	DeleteStatic(ctx context.Context, ids []string) (err error)
+	UpdateStatic(ctx context.Context, previous, card model.CardStatic) error
+	GetStaticVersions(ctx context.Context, id string) (model.CardsStatic, error)
+	GetStaticByExternalID(ctx context.Context, externalID string) (model.CardStatic, error)
+	ArchiveStatic(ctx context.Context, ids []string, archived bool, at time.Time) error
 	GetStaticByPool(ctx context.Context, pool string) (model.CardsStatic, error)
 
 	GetCardsByOwnerPool(ctx context.Context, ownerUsername, pool string) (model.Cards, error)
-	DeleteUsersPendingDailyCards(ctx context.Context, username string) error
+	DeleteUsersPendingCards(ctx context.Context, username, pool string) error
+	AddDailyIssues(ctx context.Context, issues []model.DailyIssue) error
+	GetDailyIssues(ctx context.Context, username string, since time.Time) ([]model.DailyIssue, error)
+	DeletePendingCardsByStatic(ctx context.Context, staticIDs []string) error
 	GetCardsByOwner(ctx context.Context, ownerUsername string) (model.Cards, error)
 	Get(ctx context.Context, id string) (model.Card, error)
 	Create(ctx context.Context, card model.Card) error
@@ -27,13 +35,46 @@ type AwardsRepo interface {
 	GetByUsername(ctx context.Context, username string) ([]model.UserPrize, error)
 }
 
+type CardsGoalsRepo interface {
+	GetAll(ctx context.Context) (model.Goals, error)
+}
+
+type CardsChainsRepo interface {
+	GetAll(ctx context.Context) (model.Chains, error)
+}
+
+type CardsSeasonsRepo interface {
+	GetAll(ctx context.Context) (model.Seasons, error)
+}
+
+type CardsUsersRepo interface {
+	GetByUsername(ctx context.Context, username string) (model.User, error)
+}
+
 type CardsService struct {
-	cardsRepo  CardsRepo
-	awardsRepo AwardsRepo
+	cardsRepo   CardsRepo
+	awardsRepo  AwardsRepo
+	goalsRepo   CardsGoalsRepo
+	chainsRepo  CardsChainsRepo
+	seasonsRepo CardsSeasonsRepo
+	usersRepo   CardsUsersRepo
+	selector    DailySelector
+	daily       config.User
 }
 
-func NewCardsStaticService(cardsStaticRepo CardsRepo, awardsRepo AwardsRepo) *CardsService {
-	return &CardsService{cardsRepo: cardsStaticRepo, awardsRepo: awardsRepo}
+// NewCardsStaticService returns the cards service, cards of the rotating pools
+// are drawn by selector with the settings of daily.
+func NewCardsStaticService(cardsStaticRepo CardsRepo, awardsRepo AwardsRepo, goalsRepo CardsGoalsRepo, chainsRepo CardsChainsRepo, seasonsRepo CardsSeasonsRepo, usersRepo CardsUsersRepo, selector DailySelector, daily config.User) *CardsService {
+	return &CardsService{
+		cardsRepo:   cardsStaticRepo,
+		awardsRepo:  awardsRepo,
+		goalsRepo:   goalsRepo,
+		chainsRepo:  chainsRepo,
+		seasonsRepo: seasonsRepo,
+		usersRepo:   usersRepo,
+		selector:    selector,
+		daily:       daily,
+	}
 }
 
 func (s *CardsService) ViewCard(ctx context.Context, cardID string) error {
@@ -45,28 +86,95 @@ func (s *CardsService) Create(ctx context.Context, card model.Card) error {
 	return s.cardsRepo.Create(ctx, card)
 }
 
-func (s *CardsService) GetFormattedCards(ctx context.Context, ownerUsername string) (model.Cards, model.Cards, error) {
-	pendingCards := make(model.Cards, 0, 50)
-	doneCards := make(model.Cards, 0, 50)
+// GetFormattedCards returns the cards of the user grouped by pool, the daily,
+// weekly and const ones and then one group per season, and the const chains
+// locked for them. Locked chains have no pending cards. Availability and
+// streaks are checked in the timezone of the user.
+func (s *CardsService) GetFormattedCards(ctx context.Context, user model.User) ([]model.PoolCards, []model.LockedChain, error) {
+	ownerUsername := user.Username
+	loc := user.Location(s.venueLocation())
+	pools := make([]model.PoolCards, 0, 4)
+	now := time.Now()
 
-	dailyCards, err := s.cardsRepo.GetCardsByOwnerPool(ctx, ownerUsername, model.PoolDaily)
-	if err != nil {
-		return model.Cards{}, model.Cards{}, err
+	// cards outside of their availability window are hidden until it opens
+	for _, pool := range []string{model.PoolDaily, model.PoolWeekly} {
+		cards, err := s.cardsRepo.GetCardsByOwnerPool(ctx, ownerUsername, pool)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		p := model.PoolCards{Pool: pool, Pending: make(model.Cards, 0, len(cards)), Done: make(model.Cards, 0, len(cards))}
+		for _, c := range cards {
+			if c.Done == 0 {
+				if c.Static.AvailableAt(now, loc, s.daily.ResetHour) {
+					p.Pending = append(p.Pending, c)
+				}
+			} else {
+				p.Done = append(p.Done, c)
+			}
+		}
+		pools = append(pools, p)
 	}
 
 	constCards, err := s.cardsRepo.GetCardsByOwnerPool(ctx, ownerUsername, model.PoolConst)
 	if err != nil {
-		return model.Cards{}, model.Cards{}, err
+		return nil, nil, err
 	}
 
-	for _, c := range dailyCards {
-		if c.Done == 0 {
-			pendingCards = append(pendingCards, c)
-		} else {
-			doneCards = append(doneCards, c)
+	chains, err := s.chainsRepo.GetAll(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	locked := chains.Locked(constCards.FinishedChains(), user.Level())
+	lockedChains := model.SortedLockedChains(locked)
+
+	pools = append(pools, s.formatConstCards(constCards, locked, now, loc))
+
+	seasonCards, err := s.cardsRepo.GetCardsByOwnerPool(ctx, ownerUsername, model.PoolSeason)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(seasonCards) == 0 {
+		return pools, lockedChains, nil
+	}
+
+	seasons, err := s.seasonsRepo.GetAll(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	bySeason := make(map[string]int)
+	for _, c := range seasonCards {
+		i, ok := bySeason[c.Static.Season]
+		if !ok {
+			i = len(pools)
+			bySeason[c.Static.Season] = i
+			pools = append(pools, model.PoolCards{Pool: model.PoolSeason, Season: c.Static.Season, Pending: model.Cards{}, Done: model.Cards{}})
+		}
+
+		switch {
+		case c.Done != 0:
+			pools[i].Done = append(pools[i].Done, c)
+		case seasonEnded(seasons, c.Static.Season, now):
+			pools[i].Expired = append(pools[i].Expired, c)
+		case c.Static.AvailableAt(now, loc, s.daily.ResetHour):
+			pools[i].Pending = append(pools[i].Pending, c)
 		}
 	}
 
+	return pools, lockedChains, nil
+}
+
+// formatConstCards returns the const pool of the user in loc, only the first
+// not done card of every unlocked chain is pending.
+func (s *CardsService) formatConstCards(constCards model.Cards, locked map[string]model.LockedChain, now time.Time, loc *time.Location) model.PoolCards {
+	pendingCards := make(model.Cards, 0, 50)
+	doneCards := make(model.Cards, 0, 50)
+
+	// streaks missed since the last completion are shown as lost
+	for i := range constCards {
+		constCards[i].Streak = constCards[i].CurrentStreak(now, loc, s.daily.ResetHour)
+	}
+
 	getPending := func(chain model.Cards) model.Card {
 		for i := 0; i < len(chain); i++ {
 			if chain[i].Done == 0 {
@@ -76,9 +184,17 @@ func (s *CardsService) GetFormattedCards(ctx context.Context, ownerUsername stri
 
 		return chain[len(chain)-1]
 	}
+	appendPending := func(chain model.Cards) {
+		if _, ok := locked[chain[0].Static.ChainName]; ok {
+			return
+		}
+		if c := getPending(chain); c.Static.AvailableAt(now, loc, s.daily.ResetHour) {
+			pendingCards = append(pendingCards, c)
+		}
+	}
 
 	if len(constCards) == 0 {
-		return pendingCards, doneCards, nil
+		return model.PoolCards{Pool: model.PoolConst, Pending: pendingCards, Done: doneCards}
 	}
 
 	chain := make(model.Cards, 0, 20)
@@ -98,40 +214,81 @@ func (s *CardsService) GetFormattedCards(ctx context.Context, ownerUsername stri
 			continue
 		}
 
-		pendingCards = append(pendingCards, getPending(chain))
+		appendPending(chain)
 
 		name = c.Static.ChainName
 		chain = chain[:0:20]
 		chain = append(chain, c)
 	}
 	// process last chain
-	pendingCards = append(pendingCards, getPending(chain))
+	appendPending(chain)
 
-	return pendingCards, doneCards, nil
+	return model.PoolCards{Pool: model.PoolConst, Pending: pendingCards, Done: doneCards}
 }
 
-func (s *CardsService) Update(ctx context.Context, id string, progress int, doneOption float32) (string, int, error) {
+// seasonEnded reports whether the season is over at now, cards of deleted
+// seasons are over too.
+func seasonEnded(seasons model.Seasons, name string, now time.Time) bool {
+	season, ok := seasons.Get(name)
+	return !ok || season.Ended(now)
+}
+
+// Update records progress on the card and returns its owner and the XPoints
+// earned. apiKeyID is the api key the update is made with, it is stored on the
+// card, empty for updates made with a token.
+func (s *CardsService) Update(ctx context.Context, id string, progress int, doneOption float32, stepID, apiKeyID string) (string, int, error) {
 	card, err := s.cardsRepo.Get(ctx, id)
 	if err != nil {
 		return "", 0, err
 	}
 
-	var gotAward model.Award
+	loc, err := s.userLocation(ctx, card.OwnerUsername)
+	if err != nil {
+		return "", 0, err
+	}
+
+	now := time.Now()
+	if card.Static.Pool == model.PoolSeason {
+		seasons, err := s.seasonsRepo.GetAll(ctx)
+		if err != nil {
+			return "", 0, err
+		}
+		// progress of cards of ended seasons is frozen
+		if seasonEnded(seasons, card.Static.Season, now) {
+			return "", 0, model.ErrSeasonEnded
+		}
+	}
+	if card.Static.Pool == model.PoolConst {
+		if err := s.checkChainUnlocked(ctx, card); err != nil {
+			return "", 0, err
+		}
+	}
+	if !card.Static.AvailableAt(now, loc, s.daily.ResetHour) {
+		return "", 0, model.ErrCardNotAvailable
+	}
+	if err := card.CanComplete(now); err != nil {
+		return "", 0, err
+	}
+	done := card.Done
+
+	// gotAward is set only when the update completes the card or hits a
+	// streak milestone, it gives the prize
+	var gotAward *model.Award
 
 	var XPoints int
 	switch card.Static.Type {
 	case model.TypeOrdinary:
 		XPoints = card.Static.OrdSettings.Award.XPoints
 		card.Done += 1
-		gotAward = card.Static.OrdSettings.Award
+		gotAward = &card.Static.OrdSettings.Award
 	case model.TypeProgress:
 		card.Progress += progress
 		if card.Progress >= card.Static.PrgSettings.MaxProgress {
 			XPoints = card.Static.PrgSettings.Award.XPoints
 			card.Progress = 0
 			card.Done += 1
+			gotAward = &card.Static.PrgSettings.Award
 		}
-		gotAward = card.Static.PrgSettings.Award
 	case model.TypeOptions:
 		if doneOption < card.Static.OptSettings.Options[0] {
 			return "", 0, nil
@@ -145,89 +302,165 @@ func (s *CardsService) Update(ctx context.Context, id string, progress int, done
 			if doneOption >= options[i] && doneOption < options[i+1] {
 				XPoints = awards[i].XPoints
 				card.History = append(card.History, i)
-				gotAward = awards[i]
+				gotAward = &awards[i]
 				break
 			}
 		}
 		if doneOption >= options[len(options)-1] {
 			XPoints = awards[len(awards)-1].XPoints
-			gotAward = awards[len(awards)-1]
+			gotAward = &awards[len(awards)-1]
 			card.History = append(card.History, len(options)-1)
 		}
+	case model.TypeStreak:
+		if !card.LastDoneAt.IsZero() && model.StreakDays(card.LastDoneAt, now, loc, s.daily.ResetHour) == 0 {
+			return "", 0, model.ErrStreakDoneToday
+		}
+
+		card.Streak = card.CurrentStreak(now, loc, s.daily.ResetHour) + 1
+		card.LastDoneAt = now
+
+		settings := card.Static.StreakSettings
+		if m, ok := settings.Milestone(card.Streak); ok {
+			XPoints = m.Award.XPoints
+			gotAward = &m.Award
+		}
+		if card.Streak >= settings.Days {
+			card.Done += 1
+			card.Streak = 0
+		}
+	case model.TypeChecklist:
+		settings := card.Static.ChecklistSettings
+		if _, ok := settings.Step(stepID); !ok {
+			return "", 0, model.ErrNoSuchStep
+		}
+		if card.StepChecked(stepID) {
+			return "", 0, model.ErrStepChecked
+		}
+
+		card.CheckedSteps = append(card.CheckedSteps, stepID)
+		if len(card.CheckedSteps) >= len(settings.Steps) {
+			XPoints = settings.Award.XPoints
+			gotAward = &settings.Award
+			card.CheckedSteps = nil
+			card.Done += 1
+		}
 	}
 
-	a := model.UserPrize{
-		URL:           gotAward.PrizeImageURL,
-		OwnerUsername: card.OwnerUsername,
-		Available:     true,
+	if card.Done > done {
+		card.CompletedAt = now
 	}
+	card.APIKeyID = apiKeyID
+
+	// partial progress, checklist steps and streak days between milestones
+	// give no prize, neither do awards of XPoints only
+	if gotAward != nil && (gotAward.Prize != "" || gotAward.PrizeImageURL != "") {
+		a := model.UserPrize{
+			URL:           gotAward.PrizeImageURL,
+			OwnerUsername: card.OwnerUsername,
+			Available:     true,
+		}
 
-	if err := s.awardsRepo.Add(ctx, a); err != nil {
-		return "", 0, err
+		if err := s.awardsRepo.Add(ctx, a); err != nil {
+			return "", 0, err
+		}
 	}
 
 	err = s.cardsRepo.Update(ctx, card)
 	return card.OwnerUsername, XPoints, err
 }
 
-func (s *CardsService) UpdateDailyCards(ctx context.Context, users []model.User, dcardsnum int, uniqueGoal bool) (time.Time, []int, error) {
-	updatedUsers := make([]int, 0, 5)
-	now := time.Now()
-	nowDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-
-	// fetch available daily cards
-	dailyStaticCards, err := s.cardsRepo.GetStaticByPool(ctx, model.PoolDaily)
+// checkChainUnlocked returns model.ErrChainLocked if the chain of the const
+// card is locked for its owner, cards of locked chains are not shown to them.
+func (s *CardsService) checkChainUnlocked(ctx context.Context, card model.Card) error {
+	chains, err := s.chainsRepo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	user, err := s.usersRepo.GetByUsername(ctx, card.OwnerUsername)
+	if err != nil && !errors.Is(err, model.ErrUserNotFound) {
+		return err
+	}
+	constCards, err := s.cardsRepo.GetCardsByOwnerPool(ctx, card.OwnerUsername, model.PoolConst)
 	if err != nil {
-		return time.Time{}, nil, err
+		return err
 	}
 
-	for i, u := range users {
-		t := u.LastDailyCardsUpdate.Local()
-		tDate := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if _, ok := chains.Locked(constCards.FinishedChains(), user.Level())[card.Static.ChainName]; ok {
+		return model.ErrChainLocked
+	}
+	return nil
+}
 
-		if !nowDate.After(tDate) {
-			continue
-		}
+func (s *CardsService) UpdateConstCards(ctx context.Context, users []model.User) error {
+	constStaticCards, err := s.cardsRepo.GetStaticByPool(ctx, model.PoolConst)
+	if err != nil {
+		return err
+	}
+	// const cards are kept once issued, so only the period matters here
+	constStaticCards = constStaticCards.InPeriod(time.Now())
 
-		newCards := dailyStaticCards.Random(dcardsnum, uniqueGoal)
-		if len(newCards) == 0 {
-			return time.Time{}, nil, model.ErrNoRandomCards
-		}
+	chains, err := s.chainsRepo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
 
-		err := s.cardsRepo.DeleteUsersPendingDailyCards(ctx, u.Username)
+	for _, u := range users {
+		userCards, err := s.cardsRepo.GetCardsByOwner(ctx, u.Username)
 		if err != nil {
-			return time.Time{}, nil, err
+			return err
 		}
+		locked := chains.Locked(userCards.FinishedChains(), u.Level())
 
-		for _, c := range newCards {
-			if err := s.cardsRepo.Create(ctx, c.Card(u.Username)); err != nil {
-				return time.Time{}, nil, err
+		for i, c := range constStaticCards {
+			if _, ok := locked[c.ChainName]; ok {
+				continue
+			}
+			if userCards.ContainsStatic(c) {
+				continue
+			}
+			if err := s.cardsRepo.Create(ctx, constStaticCards[i].Card(u.Username)); err != nil {
+				return err
 			}
 		}
-		updatedUsers = append(updatedUsers, i)
 	}
-
-	// return nowDate to update LastDailyCardsUpdate for users
-	return nowDate, updatedUsers, nil
+	return nil
 }
 
-func (s *CardsService) UpdateConstCards(ctx context.Context, users []model.User) error {
-	constStaticCards, err := s.cardsRepo.GetStaticByPool(ctx, model.PoolConst)
+// UpdateSeasonCards issues the cards of live seasons to users who don't have
+// them yet, every card once.
+func (s *CardsService) UpdateSeasonCards(ctx context.Context, users []model.User) error {
+	seasons, err := s.seasonsRepo.GetAll(ctx)
 	if err != nil {
 		return err
 	}
 
+	staticCards, err := s.cardsRepo.GetStaticByPool(ctx, model.PoolSeason)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	live := make(model.CardsStatic, 0, len(staticCards))
+	for _, c := range staticCards.InPeriod(now) {
+		if season, ok := seasons.Get(c.Season); ok && season.Active(now) {
+			live = append(live, c)
+		}
+	}
+	if len(live) == 0 {
+		return nil
+	}
+
 	for _, u := range users {
-		userCards, err := s.cardsRepo.GetCardsByOwner(ctx, u.Username)
+		userCards, err := s.cardsRepo.GetCardsByOwnerPool(ctx, u.Username, model.PoolSeason)
 		if err != nil {
 			return err
 		}
 
-		for i, c := range constStaticCards {
+		for _, c := range live {
 			if userCards.ContainsStatic(c) {
 				continue
 			}
-			if err := s.cardsRepo.Create(ctx, constStaticCards[i].Card(u.Username)); err != nil {
+			if err := s.cardsRepo.Create(ctx, c.Card(u.Username)); err != nil {
 				return err
 			}
 		}
@@ -237,14 +470,137 @@ func (s *CardsService) UpdateConstCards(ctx context.Context, users []model.User)
 
 func (s *CardsService) CreateStatic(ctx context.Context, card model.CardStatic) error {
 	card.ID = ""
+	if err := s.validateStatic(ctx, &card); err != nil {
+		return err
+	}
+	return s.createStatic(ctx, card)
+}
+
+func (s *CardsService) createStatic(ctx context.Context, card model.CardStatic) error {
+	card.ID = ""
+	card.Version = 1
 	card.CreatedAt = time.Now()
+	card.UpdatedAt = card.CreatedAt
 	return s.cardsRepo.CreateStatic(ctx, card)
 }
 
+// UpdateStatic stores card as the next version of the static card with the
+// same id. The previous version goes to the history, cards already issued to
+// users keep it, new cards are issued with the latest one.
+func (s *CardsService) UpdateStatic(ctx context.Context, card model.CardStatic) (model.CardStatic, error) {
+	cards, err := s.cardsRepo.GetStatic(ctx, []string{card.ID})
+	if err != nil {
+		return model.CardStatic{}, err
+	}
+	if len(cards) == 0 {
+		return model.CardStatic{}, model.ErrNoSuchCard
+	}
+
+	if err := s.validateStatic(ctx, &card); err != nil {
+		return model.CardStatic{}, err
+	}
+	return s.updateStatic(ctx, cards[0], card)
+}
+
+func (s *CardsService) updateStatic(ctx context.Context, previous, card model.CardStatic) (model.CardStatic, error) {
+	// cards created before versioning
+	if previous.Version == 0 {
+		previous.Version = 1
+	}
+
+	card.ID = previous.ID
+	card.Version = previous.Version + 1
+	card.CreatedAt = previous.CreatedAt
+	card.Archived = previous.Archived
+	card.ArchivedAt = previous.ArchivedAt
+	if card.ExternalID == "" {
+		card.ExternalID = previous.ExternalID
+	}
+	card.UpdatedAt = time.Now()
+	if err := s.cardsRepo.UpdateStatic(ctx, previous, card); err != nil {
+		return model.CardStatic{}, err
+	}
+	return card, nil
+}
+
+// validateStatic checks the card against the goals and the const pool or the
+// seasons it goes to and drops the fields of the pools it is not in.
+func (s *CardsService) validateStatic(ctx context.Context, card *model.CardStatic) error {
+	goals, err := s.goalsRepo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	var chain model.CardsStatic
+	if card.Pool == model.PoolConst {
+		if chain, err = s.cardsRepo.GetStaticByPool(ctx, model.PoolConst); err != nil {
+			return err
+		}
+	}
+	var seasons model.Seasons
+	if card.Pool == model.PoolSeason {
+		if seasons, err = s.seasonsRepo.GetAll(ctx); err != nil {
+			return err
+		}
+	}
+
+	if err := card.Validate(goals, chain, seasons); err != nil {
+		return err
+	}
+
+	dropPoolFields(card)
+	return nil
+}
+
+func dropPoolFields(card *model.CardStatic) {
+	if card.Pool != model.PoolConst {
+		card.ChainName = ""
+		card.ChainOrder = 0
+	}
+	if card.Pool != model.PoolSeason {
+		card.Season = ""
+	}
+}
+
+// GetStaticVersions returns every version of the static card, the oldest first.
+func (s *CardsService) GetStaticVersions(ctx context.Context, id string) (model.CardsStatic, error) {
+	cards, err := s.cardsRepo.GetStatic(ctx, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	if len(cards) == 0 {
+		return nil, model.ErrNoSuchCard
+	}
+
+	versions, err := s.cardsRepo.GetStaticVersions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	current := cards[0]
+	if current.Version == 0 {
+		current.Version = 1
+	}
+	return append(versions, current), nil
+}
+
 func (s *CardsService) GetStatic(ctx context.Context, ids []string) (model.CardsStatic, error) {
 	return s.cardsRepo.GetStatic(ctx, ids)
 }
 
-func (s *CardsService) DeleteStatic(ctx context.Context, ids []string) (err error) {
-	return s.cardsRepo.DeleteStatic(ctx, ids)
+// ArchiveStatic takes the static cards out of distribution. With cancelPending
+// the user cards issued from them and not done yet are removed too.
+func (s *CardsService) ArchiveStatic(ctx context.Context, ids []string, cancelPending bool) error {
+	if err := s.cardsRepo.ArchiveStatic(ctx, ids, true, time.Now()); err != nil {
+		return err
+	}
+
+	if cancelPending {
+		return s.cardsRepo.DeletePendingCardsByStatic(ctx, ids)
+	}
+	return nil
+}
+
+func (s *CardsService) UnarchiveStatic(ctx context.Context, ids []string) error {
+	return s.cardsRepo.ArchiveStatic(ctx, ids, false, time.Time{})
 }
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+// rotation is a pool whose pending cards are replaced by Num new ones every
+// period. start returns the start of the period t falls in, available filters
+// the cards live in the period starting at the given time.
+type rotation struct {
+	pool      string
+	num       int
+	start     func(t time.Time, loc *time.Location) time.Time
+	available func(cards model.CardsStatic, start time.Time) model.CardsStatic
+}
+
+// rotations returns the rotating pools, the weekly one only if users get
+// weekly cards.
+func (s *CardsService) rotations() []rotation {
+	rotations := []rotation{{
+		pool: model.PoolDaily,
+		num:  s.daily.DailyCardsNum,
+		start: func(t time.Time, loc *time.Location) time.Time {
+			return model.DailyStart(t, loc, s.daily.ResetHour)
+		},
+		available: model.CardsStatic.AvailableOn,
+	}}
+
+	if s.daily.WeeklyCardsNum > 0 {
+		weekday, err := s.daily.WeeklyReset()
+		if err != nil {
+			weekday = time.Monday
+		}
+		rotations = append(rotations, rotation{
+			pool: model.PoolWeekly,
+			num:  s.daily.WeeklyCardsNum,
+			start: func(t time.Time, loc *time.Location) time.Time {
+				return model.WeeklyStart(t, loc, weekday, s.daily.ResetHour)
+			},
+			// weekly cards stay for the whole week, so only the period matters
+			available: model.CardsStatic.InPeriod,
+		})
+	}
+	return rotations
+}
+
+// RotatePools replaces the cards of the rotating pools of users whose period
+// started after their last rotation, periods start at the reset hour in the
+// timezone of a user. It returns the rotated users with the rotation times
+// updated, they are to be saved. Pools with nothing to draw for a user are
+// skipped and keep the cards the user has, they are reported with an error
+// wrapping model.ErrNoRandomCards next to the rotated users.
+func (s *CardsService) RotatePools(ctx context.Context, users []model.User) ([]model.User, error) {
+	rotated := make([]model.User, 0, 5)
+	skipped := make(map[string]int)
+	now := time.Now()
+
+	rotations := s.rotations()
+	static := make(map[string]model.CardsStatic, len(rotations))
+	for _, r := range rotations {
+		cards, err := s.cardsRepo.GetStaticByPool(ctx, r.pool)
+		if err != nil {
+			return nil, err
+		}
+		static[r.pool] = cards
+	}
+
+	venue := s.venueLocation()
+	locations := make(map[string]*time.Location)
+	for _, u := range users {
+		loc, ok := locations[u.Timezone]
+		if !ok {
+			loc = u.Location(venue)
+			locations[u.Timezone] = loc
+		}
+
+		updated := false
+		for _, r := range rotations {
+			start := r.start(now, loc)
+			if !start.After(r.start(u.LastRotation(r.pool), loc)) {
+				continue
+			}
+
+			err := s.rotate(ctx, u.Username, r, r.available(static[r.pool], start), start, now)
+			if errors.Is(err, model.ErrNoRandomCards) {
+				skipped[r.pool]++
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			u.SetLastRotation(r.pool, now)
+			updated = true
+		}
+		if updated {
+			rotated = append(rotated, u)
+		}
+	}
+
+	if len(skipped) != 0 {
+		pools := make([]string, 0, len(skipped))
+		for _, r := range rotations {
+			if n, ok := skipped[r.pool]; ok {
+				pools = append(pools, fmt.Sprintf("%s cards of %d users", r.pool, n))
+			}
+		}
+		return rotated, fmt.Errorf("error skipped %s: %w", strings.Join(pools, ", "), model.ErrNoRandomCards)
+	}
+	return rotated, nil
+}
+
+// rotate replaces the pending cards of the user in the pool of r with the ones
+// drawn for the period starting at start. With nothing to draw the pending
+// cards are kept and model.ErrNoRandomCards is returned.
+func (s *CardsService) rotate(ctx context.Context, username string, r rotation, cards model.CardsStatic, start, now time.Time) error {
+	seed, newCards, err := s.drawPool(ctx, username, r, cards, start)
+	if err != nil {
+		return err
+	}
+	if len(newCards) == 0 {
+		return model.ErrNoRandomCards
+	}
+
+	if err := s.cardsRepo.DeleteUsersPendingCards(ctx, username, r.pool); err != nil {
+		return err
+	}
+
+	issues := make([]model.DailyIssue, len(newCards))
+	for i, c := range newCards {
+		card := c.Card(username)
+		card.Seed = seed
+		if err := s.cardsRepo.Create(ctx, card); err != nil {
+			return err
+		}
+		issues[i] = model.DailyIssue{Username: username, Pool: r.pool, StaticID: c.ID, Goal: c.Goal, IssuedAt: now, Seed: seed}
+	}
+	return s.cardsRepo.AddDailyIssues(ctx, issues)
+}
+
+// ReplayDailyCards draws the daily cards of the user on the day of date again
+// with the daily cards and the history of the user as they are now. Only the
+// calendar date of date matters, zero date is the current day of the user.
+func (s *CardsService) ReplayDailyCards(ctx context.Context, user model.User, date time.Time) (model.DailyReplay, error) {
+	username := user.Username
+	loc := user.Location(s.venueLocation())
+	daily := s.rotations()[0]
+
+	day := daily.start(time.Now(), loc)
+	if !date.IsZero() {
+		day = time.Date(date.Year(), date.Month(), date.Day(), s.daily.ResetHour, 0, 0, 0, loc)
+	}
+
+	dailyStaticCards, err := s.cardsRepo.GetStaticByPool(ctx, model.PoolDaily)
+	if err != nil {
+		return model.DailyReplay{}, err
+	}
+
+	seed, cards, err := s.drawPool(ctx, username, daily, daily.available(dailyStaticCards, day), day)
+	if err != nil {
+		return model.DailyReplay{}, err
+	}
+
+	issues, err := s.cardsRepo.GetDailyIssues(ctx, username, day)
+	if err != nil {
+		return model.DailyReplay{}, err
+	}
+	issued := make([]model.DailyIssue, 0, len(issues))
+	for _, i := range issues {
+		if i.Pool == model.PoolDaily && i.IssuedAt.Before(day.AddDate(0, 0, 1)) {
+			issued = append(issued, i)
+		}
+	}
+
+	return model.DailyReplay{Username: username, Day: day, Seed: seed, Cards: cards, Issued: issued}, nil
+}
+
+// venueLocation returns the venue timezone, the server one if it is unknown.
+func (s *CardsService) venueLocation() *time.Location {
+	loc, err := s.daily.Location()
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// userLocation returns the timezone of the user, the venue one if they have no
+// user record.
+func (s *CardsService) userLocation(ctx context.Context, username string) (*time.Location, error) {
+	user, err := s.usersRepo.GetByUsername(ctx, username)
+	if errors.Is(err, model.ErrUserNotFound) {
+		return s.venueLocation(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user.Location(s.venueLocation()), nil
+}
+
+// drawPool picks the cards of the user in the pool of r for the period
+// starting at start from cards. The draw only depends on its seed, the cards
+// and the history of the user in the pool before start, so it can be replayed.
+func (s *CardsService) drawPool(ctx context.Context, username string, r rotation, cards model.CardsStatic, start time.Time) (int64, []model.CardStatic, error) {
+	selector := s.selector
+	if selector == nil {
+		selector = UniformSelector{}
+	}
+
+	sorted := make(model.CardsStatic, len(cards))
+	copy(sorted, cards)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	seed := DrawSeed(s.daily.DailySelection.SeedSecret, r.pool, username, start)
+	draw := DailyDraw{
+		Cards:      sorted,
+		Num:        r.num,
+		UniqueGoal: s.daily.UniqueGoals,
+		Now:        start,
+		Rand:       rand.New(rand.NewSource(seed)),
+	}
+
+	if days := selector.HistoryDays(); days > 0 {
+		issues, err := s.cardsRepo.GetDailyIssues(ctx, username, start.AddDate(0, 0, -days))
+		if err != nil {
+			return 0, nil, err
+		}
+		for _, i := range issues {
+			if i.Pool == r.pool && i.IssuedAt.Before(start) {
+				draw.Issued = append(draw.Issued, i)
+			}
+		}
+
+		userCards, err := s.cardsRepo.GetCardsByOwnerPool(ctx, username, r.pool)
+		if err != nil {
+			return 0, nil, err
+		}
+		for _, c := range userCards {
+			if c.Done > 0 && c.CompletedAt.Before(start) {
+				draw.UserCards = append(draw.UserCards, c)
+			}
+		}
+	}
+
+	return seed, selector.Select(draw), nil
+}
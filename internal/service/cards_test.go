@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/Andrei-Raev/xp-loyalty/internal/model"
 	"github.com/Andrei-Raev/xp-loyalty/internal/model/mocks"
+	"github.com/Andrei-Raev/xp-loyalty/pkg/config"
 )
 
 func TestCardsService_GetFormattedCards(t *testing.T) {
@@ -135,12 +137,20 @@ func TestCardsService_GetFormattedCards(t *testing.T) {
 	cardsRepo := new(mocks.CardsRepositoryMock)
 	cardsRepo.On("GetCardsByOwnerPool", mock.Anything, mock.Anything, model.PoolDaily).Return(dailyCards, nil)
 	cardsRepo.On("GetCardsByOwnerPool", mock.Anything, mock.Anything, model.PoolConst).Return(constCards, nil)
+	cardsRepo.On("GetCardsByOwnerPool", mock.Anything, mock.Anything, model.PoolWeekly).Return(model.Cards{}, nil)
+	cardsRepo.On("GetCardsByOwnerPool", mock.Anything, mock.Anything, model.PoolSeason).Return(model.Cards{}, nil)
 
 	t.Run("the only case", func(t *testing.T) {
-		s := &CardsService{cardsRepo: cardsRepo}
-		gotPending, gotDone, err := s.GetFormattedCards(context.Background(), "user")
+		s := &CardsService{cardsRepo: cardsRepo, chainsRepo: newChainsRepo(nil)}
+		pools, _, err := s.GetFormattedCards(context.Background(), model.User{CredentialsSecure: model.CredentialsSecure{Username: "user"}})
 		assert.NoError(t, err)
 
+		var gotPending, gotDone model.Cards
+		for _, p := range pools {
+			gotPending = append(gotPending, p.Pending...)
+			gotDone = append(gotDone, p.Done...)
+		}
+
 		require.Equal(t, len(wantPending), len(gotPending), "test pending cards len")
 		require.Equal(t, len(wantDone), len(gotDone), "test done cards len")
 
@@ -605,8 +615,8 @@ func TestCardsService_Update(t *testing.T) {
 	for _, tt := range tests {
 		call1 := cardsRepo.On("Get", mock.Anything, tt.args.id).Return(tt.repo, nil).Once()
 		call2 := cardsRepo.On("Update", mock.Anything, tt.update).Return(nil).Maybe()
-		s := &CardsService{cardsRepo: tt.fields.cardsRepo}
-		_, got, err := s.Update(tt.args.ctx, tt.args.id, tt.args.progress, tt.args.doneOption)
+		s := &CardsService{cardsRepo: tt.fields.cardsRepo, usersRepo: newUsersRepo()}
+		_, got, err := s.Update(tt.args.ctx, tt.args.id, tt.args.progress, tt.args.doneOption, "", "")
 
 		t.Run(tt.name, func(t *testing.T) {
 			require.NoError(t, err)
@@ -708,7 +718,7 @@ func TestCardsService_UpdateConstCards(t *testing.T) {
 	cardsRepo.On("Create", mock.Anything, test.wantUser2[0]).Return(nil)
 	cardsRepo.On("Create", mock.Anything, test.wantUser3[0]).Return(nil)
 	cardsRepo.On("Create", mock.Anything, test.wantUser3[1]).Return(nil)
-	s := &CardsService{cardsRepo: cardsRepo}
+	s := &CardsService{cardsRepo: cardsRepo, chainsRepo: newChainsRepo(nil)}
 
 	t.Run(test.name, func(t *testing.T) {
 		err := s.UpdateConstCards(test.args.ctx, test.args.users)
@@ -716,3 +726,666 @@ func TestCardsService_UpdateConstCards(t *testing.T) {
 		cardsRepo.AssertExpectations(t)
 	})
 }
+
+func TestCardsService_UpdateStatic(t *testing.T) {
+	ctx := context.Background()
+	ordinary := &model.OrdSettings{Award: model.Award{XPoints: 10}}
+	legacy := model.CardStatic{ID: "1", Title: "titel", Goal: model.GoalBuyFood, Type: model.TypeOrdinary, Pool: model.PoolDaily, OrdSettings: ordinary}
+
+	cardsRepo := new(mocks.CardsRepositoryMock)
+	cardsRepo.On("GetStatic", mock.Anything, []string{"1"}).Return(model.CardsStatic{legacy}, nil)
+	cardsRepo.On("GetStatic", mock.Anything, []string{"2"}).Return(model.CardsStatic{}, nil)
+	s := &CardsService{cardsRepo: cardsRepo, goalsRepo: defaultGoalsRepo()}
+
+	t.Run("next version", func(t *testing.T) {
+		cardsRepo.On("UpdateStatic", mock.Anything,
+			mock.MatchedBy(func(c model.CardStatic) bool { return c.Version == 1 && c.Title == "titel" }),
+			mock.MatchedBy(func(c model.CardStatic) bool { return c.Version == 2 && c.Title == "title" }),
+		).Return(nil).Once()
+
+		card := legacy
+		card.Title = "title"
+		card, err := s.UpdateStatic(ctx, card)
+		require.NoError(t, err)
+		assert.Equal(t, 2, card.Version)
+		assert.False(t, card.UpdatedAt.IsZero())
+	})
+
+	t.Run("no such card", func(t *testing.T) {
+		_, err := s.UpdateStatic(ctx, model.CardStatic{ID: "2"})
+		require.ErrorIs(t, err, model.ErrNoSuchCard)
+	})
+
+	t.Run("invalid card", func(t *testing.T) {
+		card := legacy
+		card.OrdSettings = nil
+
+		_, err := s.UpdateStatic(ctx, card)
+		require.ErrorIs(t, err, model.ErrInvalidCard)
+	})
+
+	t.Run("history ends with current version", func(t *testing.T) {
+		cardsRepo.On("GetStaticVersions", mock.Anything, "1").Return(model.CardsStatic{}, nil).Once()
+
+		versions, err := s.GetStaticVersions(ctx, "1")
+		require.NoError(t, err)
+		require.Len(t, versions, 1)
+		assert.Equal(t, 1, versions[0].Version)
+	})
+
+	cardsRepo.AssertExpectations(t)
+}
+
+func TestCardsService_ArchiveStatic(t *testing.T) {
+	ctx := context.Background()
+	ids := []string{"1", "2"}
+
+	t.Run("keep pending cards", func(t *testing.T) {
+		cardsRepo := new(mocks.CardsRepositoryMock)
+		cardsRepo.On("ArchiveStatic", mock.Anything, ids, true, mock.AnythingOfType("time.Time")).Return(nil).Once()
+		s := &CardsService{cardsRepo: cardsRepo}
+
+		require.NoError(t, s.ArchiveStatic(ctx, ids, false))
+		cardsRepo.AssertExpectations(t)
+		cardsRepo.AssertNotCalled(t, "DeletePendingCardsByStatic", mock.Anything, mock.Anything)
+	})
+
+	t.Run("cancel pending cards", func(t *testing.T) {
+		cardsRepo := new(mocks.CardsRepositoryMock)
+		cardsRepo.On("ArchiveStatic", mock.Anything, ids, true, mock.AnythingOfType("time.Time")).Return(nil).Once()
+		cardsRepo.On("DeletePendingCardsByStatic", mock.Anything, ids).Return(nil).Once()
+		s := &CardsService{cardsRepo: cardsRepo}
+
+		require.NoError(t, s.ArchiveStatic(ctx, ids, true))
+		cardsRepo.AssertExpectations(t)
+	})
+
+	t.Run("unarchive", func(t *testing.T) {
+		cardsRepo := new(mocks.CardsRepositoryMock)
+		cardsRepo.On("ArchiveStatic", mock.Anything, ids, false, time.Time{}).Return(nil).Once()
+		s := &CardsService{cardsRepo: cardsRepo}
+
+		require.NoError(t, s.UnarchiveStatic(ctx, ids))
+		cardsRepo.AssertExpectations(t)
+	})
+
+	t.Run("edit keeps archived state", func(t *testing.T) {
+		archivedAt := time.Now()
+		cardsRepo := new(mocks.CardsRepositoryMock)
+		card := model.CardStatic{ID: "1", Goal: model.GoalPlayMore, Type: model.TypeOrdinary, Pool: model.PoolDaily, OrdSettings: &model.OrdSettings{}}
+		previous := card
+		previous.Version, previous.Archived, previous.ArchivedAt = 1, true, archivedAt
+		cardsRepo.On("GetStatic", mock.Anything, []string{"1"}).Return(model.CardsStatic{previous}, nil)
+		cardsRepo.On("UpdateStatic", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+		s := &CardsService{cardsRepo: cardsRepo, goalsRepo: defaultGoalsRepo()}
+
+		card, err := s.UpdateStatic(ctx, card)
+		require.NoError(t, err)
+		assert.True(t, card.Archived)
+		assert.Equal(t, archivedAt, card.ArchivedAt)
+	})
+}
+
+func TestCardsService_CreateStaticValidation(t *testing.T) {
+	ctx := context.Background()
+	chain := model.CardsStatic{{ID: "1", Pool: model.PoolConst, ChainName: "first steps", ChainOrder: 0}}
+
+	cardsRepo := new(mocks.CardsRepositoryMock)
+	cardsRepo.On("GetStaticByPool", mock.Anything, model.PoolConst).Return(chain, nil)
+	s := &CardsService{cardsRepo: cardsRepo, goalsRepo: defaultGoalsRepo()}
+
+	card := model.CardStatic{
+		Goal:        model.GoalSocialActivity,
+		Type:        model.TypeProgress,
+		Pool:        model.PoolConst,
+		ChainName:   "first steps",
+		ChainOrder:  0,
+		PrgSettings: &model.PrgSettings{MaxProgress: 3},
+	}
+
+	t.Run("colliding chain order", func(t *testing.T) {
+		err := s.CreateStatic(ctx, card)
+		var verr *model.ValidationError
+		require.ErrorAs(t, err, &verr)
+		require.Len(t, verr.Violations, 1)
+		assert.Equal(t, "chain_order", verr.Violations[0].Field)
+	})
+
+	t.Run("next chain order", func(t *testing.T) {
+		card := card
+		card.ChainOrder = 1
+		cardsRepo.On("CreateStatic", mock.Anything, mock.MatchedBy(func(c model.CardStatic) bool {
+			return c.ChainOrder == 1 && c.Version == 1
+		})).Return(nil).Once()
+
+		require.NoError(t, s.CreateStatic(ctx, card))
+	})
+
+	cardsRepo.AssertExpectations(t)
+}
+
+func TestCardsService_UpdateStreak(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	yesterday := now.AddDate(0, 0, -1)
+
+	static := model.CardStatic{
+		Type: model.TypeStreak,
+		StreakSettings: &model.StreakSettings{
+			Days:      3,
+			GraceDays: 1,
+			Milestones: []model.Milestone{
+				{Day: 2, Award: model.Award{XPoints: 10}},
+				{Day: 3, Award: model.Award{XPoints: 30, PrizeImageURL: "http://img/mug"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		card       model.Card
+		wantXP     int
+		wantStreak int
+		wantDone   int
+		wantErr    error
+	}{
+		{
+			name:       "first day",
+			card:       model.Card{ID: "1", Static: static},
+			wantStreak: 1,
+		},
+		{
+			name:       "milestone",
+			card:       model.Card{ID: "1", Static: static, Streak: 1, LastDoneAt: yesterday},
+			wantXP:     10,
+			wantStreak: 2,
+		},
+		{
+			name:       "within grace days",
+			card:       model.Card{ID: "1", Static: static, Streak: 1, LastDoneAt: now.AddDate(0, 0, -2)},
+			wantXP:     10,
+			wantStreak: 2,
+		},
+		{
+			name:       "broken streak",
+			card:       model.Card{ID: "1", Static: static, Streak: 2, LastDoneAt: now.AddDate(0, 0, -3)},
+			wantStreak: 1,
+		},
+		{
+			name:     "last day completes the card",
+			card:     model.Card{ID: "1", Static: static, Streak: 2, LastDoneAt: yesterday},
+			wantXP:   30,
+			wantDone: 1,
+		},
+		{
+			name:    "done today",
+			card:    model.Card{ID: "1", Static: static, Streak: 1, LastDoneAt: now},
+			wantErr: model.ErrStreakDoneToday,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cardsRepo := new(mocks.CardsRepositoryMock)
+			cardsRepo.On("Get", mock.Anything, "1").Return(tt.card, nil)
+			cardsRepo.On("Update", mock.Anything, mock.MatchedBy(func(c model.Card) bool {
+				return c.Streak == tt.wantStreak && c.Done == tt.wantDone && !c.LastDoneAt.Before(now)
+			})).Return(nil)
+			awardsRepo := new(mocks.AwardsRepositoryMock)
+			awardsRepo.On("Add", mock.Anything, mock.Anything).Return(nil)
+			s := &CardsService{cardsRepo: cardsRepo, awardsRepo: awardsRepo, usersRepo: newUsersRepo()}
+
+			_, xp, err := s.Update(ctx, "1", 0, 0, "", "")
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				cardsRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantXP, xp)
+			cardsRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCardsService_UpdateChecklist(t *testing.T) {
+	ctx := context.Background()
+	static := model.CardStatic{
+		Type: model.TypeChecklist,
+		ChecklistSettings: &model.ChecklistSettings{
+			Steps: []model.Step{{ID: "mojito", Title: "Mojito"}, {ID: "negroni", Title: "Negroni"}},
+			Award: model.Award{XPoints: 40},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		card      model.Card
+		stepID    string
+		wantXP    int
+		wantSteps []string
+		wantDone  int
+		wantErr   error
+	}{
+		{
+			name:      "first step",
+			card:      model.Card{ID: "1", Static: static},
+			stepID:    "negroni",
+			wantSteps: []string{"negroni"},
+		},
+		{
+			name:     "last step gives the award",
+			card:     model.Card{ID: "1", Static: static, CheckedSteps: []string{"negroni"}},
+			stepID:   "mojito",
+			wantXP:   40,
+			wantDone: 1,
+		},
+		{
+			name:    "checked step",
+			card:    model.Card{ID: "1", Static: static, CheckedSteps: []string{"negroni"}},
+			stepID:  "negroni",
+			wantErr: model.ErrStepChecked,
+		},
+		{
+			name:    "unknown step",
+			card:    model.Card{ID: "1", Static: static},
+			stepID:  "spritz",
+			wantErr: model.ErrNoSuchStep,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cardsRepo := new(mocks.CardsRepositoryMock)
+			cardsRepo.On("Get", mock.Anything, "1").Return(tt.card, nil)
+			cardsRepo.On("Update", mock.Anything, mock.MatchedBy(func(c model.Card) bool {
+				return assert.ObjectsAreEqual(tt.wantSteps, c.CheckedSteps) && c.Done == tt.wantDone
+			})).Return(nil)
+			awardsRepo := new(mocks.AwardsRepositoryMock)
+			awardsRepo.On("Add", mock.Anything, mock.Anything).Return(nil)
+			s := &CardsService{cardsRepo: cardsRepo, awardsRepo: awardsRepo, usersRepo: newUsersRepo()}
+
+			_, xp, err := s.Update(ctx, "1", 0, 0, tt.stepID, "")
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				cardsRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantXP, xp)
+			cardsRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCardsService_UpdateChecklistPrize(t *testing.T) {
+	ctx := context.Background()
+	award := model.Award{XPoints: 40, Prize: "cocktail", PrizeImageURL: "cocktail.png"}
+	card := model.Card{ID: "1", OwnerUsername: "user", Static: model.CardStatic{
+		Type: model.TypeChecklist,
+		ChecklistSettings: &model.ChecklistSettings{
+			Steps: []model.Step{{ID: "mojito"}, {ID: "negroni"}, {ID: "spritz"}},
+			Award: award,
+		},
+	}}
+
+	cardsRepo := new(mocks.CardsRepositoryMock)
+	cardsRepo.On("Get", mock.Anything, "1").Return(card, nil).Once()
+	cardsRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	awardsRepo := new(mocks.AwardsRepositoryMock)
+	s := &CardsService{cardsRepo: cardsRepo, awardsRepo: awardsRepo, usersRepo: newUsersRepo()}
+
+	_, xp, err := s.Update(ctx, "1", 0, 0, "negroni", "")
+	require.NoError(t, err)
+	assert.Zero(t, xp)
+	awardsRepo.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+
+	card.CheckedSteps = []string{"mojito", "negroni"}
+	cardsRepo.On("Get", mock.Anything, "1").Return(card, nil).Once()
+	awardsRepo.On("Add", mock.Anything, model.UserPrize{URL: "cocktail.png", OwnerUsername: "user", Available: true}).Return(nil).Once()
+
+	_, xp, err = s.Update(ctx, "1", 0, 0, "spritz", "")
+	require.NoError(t, err)
+	assert.Equal(t, 40, xp)
+	awardsRepo.AssertExpectations(t)
+}
+
+func TestCardsService_UpdateProgressPrize(t *testing.T) {
+	ctx := context.Background()
+	award := model.Award{XPoints: 30, PrizeImageURL: "games.png"}
+	card := model.Card{ID: "1", OwnerUsername: "user", Static: model.CardStatic{
+		Type:        model.TypeProgress,
+		PrgSettings: &model.PrgSettings{Award: award, MaxProgress: 3},
+	}}
+
+	cardsRepo := new(mocks.CardsRepositoryMock)
+	cardsRepo.On("Get", mock.Anything, "1").Return(card, nil).Once()
+	cardsRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	awardsRepo := new(mocks.AwardsRepositoryMock)
+	s := &CardsService{cardsRepo: cardsRepo, awardsRepo: awardsRepo, usersRepo: newUsersRepo()}
+
+	_, xp, err := s.Update(ctx, "1", 1, 0, "", "")
+	require.NoError(t, err)
+	assert.Zero(t, xp)
+	awardsRepo.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+
+	card.Progress = 2
+	cardsRepo.On("Get", mock.Anything, "1").Return(card, nil).Once()
+	awardsRepo.On("Add", mock.Anything, model.UserPrize{URL: "games.png", OwnerUsername: "user", Available: true}).Return(nil).Once()
+
+	_, xp, err = s.Update(ctx, "1", 1, 0, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, 30, xp)
+	awardsRepo.AssertExpectations(t)
+}
+
+func TestCardsService_UpdateImageOnlyPrize(t *testing.T) {
+	card := model.Card{ID: "1", OwnerUsername: "user", Static: model.CardStatic{
+		Type:        model.TypeOrdinary,
+		OrdSettings: &model.OrdSettings{Award: model.Award{XPoints: 10, PrizeImageURL: "cookie.png"}},
+	}}
+
+	cardsRepo := new(mocks.CardsRepositoryMock)
+	cardsRepo.On("Get", mock.Anything, "1").Return(card, nil)
+	cardsRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	awardsRepo := new(mocks.AwardsRepositoryMock)
+	awardsRepo.On("Add", mock.Anything, model.UserPrize{URL: "cookie.png", OwnerUsername: "user", Available: true}).Return(nil).Once()
+	s := &CardsService{cardsRepo: cardsRepo, awardsRepo: awardsRepo, usersRepo: newUsersRepo()}
+
+	_, xp, err := s.Update(context.Background(), "1", 0, 0, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, 10, xp)
+	awardsRepo.AssertExpectations(t)
+}
+
+func TestCardsService_UpdateOutsideAvailability(t *testing.T) {
+	card := model.Card{
+		ID: "1",
+		Static: model.CardStatic{
+			Type:         model.TypeOrdinary,
+			OrdSettings:  &model.OrdSettings{},
+			Availability: &model.Availability{EndsAt: time.Now().Add(-time.Hour)},
+		},
+	}
+	cardsRepo := new(mocks.CardsRepositoryMock)
+	cardsRepo.On("Get", mock.Anything, "1").Return(card, nil)
+	s := &CardsService{cardsRepo: cardsRepo, usersRepo: newUsersRepo()}
+
+	_, _, err := s.Update(context.Background(), "1", 0, 0, "", "")
+	require.ErrorIs(t, err, model.ErrCardNotAvailable)
+	cardsRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestCardsService_UpdateInUserTimezone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	hour := time.Now().In(tokyo).Hour()
+
+	card := model.Card{
+		ID:            "1",
+		OwnerUsername: "tokyo",
+		Static: model.CardStatic{
+			Type:         model.TypeOrdinary,
+			OrdSettings:  &model.OrdSettings{Award: model.Award{XPoints: 10}},
+			Availability: &model.Availability{FromHour: hour, ToHour: (hour + 1) % 24},
+		},
+	}
+	cardsRepo := new(mocks.CardsRepositoryMock)
+	cardsRepo.On("Get", mock.Anything, "1").Return(card, nil)
+	cardsRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	t.Run("hours of the user", func(t *testing.T) {
+		user := model.User{CredentialsSecure: model.CredentialsSecure{Username: "tokyo"}, Timezone: "Asia/Tokyo"}
+		s := &CardsService{cardsRepo: cardsRepo, usersRepo: newUsersRepo(user), daily: config.User{Timezone: "UTC"}}
+
+		_, xp, err := s.Update(context.Background(), "1", 0, 0, "", "")
+		require.NoError(t, err)
+		assert.Equal(t, 10, xp)
+	})
+
+	t.Run("hours of the venue", func(t *testing.T) {
+		user := model.User{CredentialsSecure: model.CredentialsSecure{Username: "tokyo"}}
+		s := &CardsService{cardsRepo: cardsRepo, usersRepo: newUsersRepo(user), daily: config.User{Timezone: "UTC"}}
+
+		_, _, err := s.Update(context.Background(), "1", 0, 0, "", "")
+		require.ErrorIs(t, err, model.ErrCardNotAvailable)
+	})
+}
+
+func TestCardsService_UpdateAPIKey(t *testing.T) {
+	card := model.Card{
+		ID:       "1",
+		Static:   model.CardStatic{Type: model.TypeOrdinary, OrdSettings: &model.OrdSettings{Award: model.Award{XPoints: 10}}},
+		APIKeyID: "pos",
+	}
+
+	for _, apiKeyID := range []string{"kiosk", ""} {
+		cardsRepo := new(mocks.CardsRepositoryMock)
+		cardsRepo.On("Get", mock.Anything, "1").Return(card, nil)
+		cardsRepo.On("Update", mock.Anything, mock.MatchedBy(func(c model.Card) bool {
+			return c.Done == 1 && c.APIKeyID == apiKeyID
+		})).Return(nil).Once()
+		s := &CardsService{cardsRepo: cardsRepo, usersRepo: newUsersRepo()}
+
+		_, _, err := s.Update(context.Background(), "1", 0, 0, "", apiKeyID)
+		require.NoError(t, err)
+		cardsRepo.AssertExpectations(t)
+	}
+}
+
+func TestCardsService_UpdateRepeat(t *testing.T) {
+	ctx := context.Background()
+	static := model.CardStatic{
+		Type:        model.TypeOrdinary,
+		OrdSettings: &model.OrdSettings{Award: model.Award{XPoints: 10}},
+		Repeat:      &model.RepeatSettings{MaxCompletions: 2, Cooldown: model.Duration{Duration: time.Hour}},
+	}
+
+	t.Run("completion is recorded", func(t *testing.T) {
+		cardsRepo := new(mocks.CardsRepositoryMock)
+		cardsRepo.On("Get", mock.Anything, "1").Return(model.Card{ID: "1", Static: static}, nil)
+		cardsRepo.On("Update", mock.Anything, mock.MatchedBy(func(c model.Card) bool {
+			return c.Done == 1 && !c.CompletedAt.IsZero()
+		})).Return(nil).Once()
+		awardsRepo := new(mocks.AwardsRepositoryMock)
+		awardsRepo.On("Add", mock.Anything, mock.Anything).Return(nil)
+		s := &CardsService{cardsRepo: cardsRepo, awardsRepo: awardsRepo, usersRepo: newUsersRepo()}
+
+		_, xp, err := s.Update(ctx, "1", 0, 0, "", "")
+		require.NoError(t, err)
+		assert.Equal(t, 10, xp)
+		cardsRepo.AssertExpectations(t)
+	})
+
+	t.Run("cooldown", func(t *testing.T) {
+		card := model.Card{ID: "1", Static: static, Done: 1, CompletedAt: time.Now()}
+		cardsRepo := new(mocks.CardsRepositoryMock)
+		cardsRepo.On("Get", mock.Anything, "1").Return(card, nil)
+		s := &CardsService{cardsRepo: cardsRepo, usersRepo: newUsersRepo()}
+
+		_, _, err := s.Update(ctx, "1", 0, 0, "", "")
+		var limitErr *model.CompletionLimitError
+		require.ErrorAs(t, err, &limitErr)
+		assert.False(t, limitErr.RetryAt.IsZero())
+		cardsRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCardsService_RotatePoolsAvailability(t *testing.T) {
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	static := model.CardsStatic{
+		{ID: "1", Goal: model.GoalBuyFood},
+		{ID: "2", Goal: model.GoalBuyDrink, Availability: &model.Availability{StartsAt: tomorrow}},
+	}
+	cardsRepo := new(mocks.CardsRepositoryMock)
+	cardsRepo.On("GetStaticByPool", mock.Anything, model.PoolDaily).Return(static, nil)
+	cardsRepo.On("DeleteUsersPendingCards", mock.Anything, "user", model.PoolDaily).Return(nil)
+	cardsRepo.On("Create", mock.Anything, mock.MatchedBy(func(c model.Card) bool {
+		return c.Static.ID == "1"
+	})).Return(nil).Once()
+	cardsRepo.On("AddDailyIssues", mock.Anything, mock.MatchedBy(func(issues []model.DailyIssue) bool {
+		return len(issues) == 1 && issues[0].StaticID == "1" && issues[0].Username == "user"
+	})).Return(nil).Once()
+	s := &CardsService{cardsRepo: cardsRepo, daily: config.User{DailyCardsNum: 1}}
+
+	users := []model.User{{CredentialsSecure: model.CredentialsSecure{Username: "user"}}}
+	updated, err := s.RotatePools(context.Background(), users)
+	require.NoError(t, err)
+	require.Len(t, updated, 1)
+	assert.False(t, updated[0].LastDailyCardsUpdate.IsZero())
+
+	s.daily.DailyCardsNum = 2
+	_, err = s.RotatePools(context.Background(), users)
+	assert.ErrorIs(t, err, model.ErrNoRandomCards)
+	cardsRepo.AssertExpectations(t)
+}
+
+func TestCardsService_UpdateConstCardsLockedChains(t *testing.T) {
+	static := model.CardsStatic{
+		{ID: "1", Pool: model.PoolConst, ChainName: "first steps"},
+		{ID: "2", Pool: model.PoolConst, ChainName: "regular"},
+		{ID: "3", Pool: model.PoolConst, ChainName: "vip"},
+	}
+	chains := model.Chains{
+		{Name: "regular", Requires: []string{"first steps"}},
+		{Name: "vip", MinLevel: 2},
+	}
+	users := []model.User{
+		{CredentialsSecure: model.CredentialsSecure{Username: "newbie"}},
+		{CredentialsSecure: model.CredentialsSecure{Username: "regular"}, XPoints: model.XPointsPerLevel},
+	}
+
+	cardsRepo := new(mocks.CardsRepositoryMock)
+	cardsRepo.On("GetStaticByPool", mock.Anything, model.PoolConst).Return(static, nil)
+	cardsRepo.On("GetCardsByOwner", mock.Anything, "newbie").Return(model.Cards{}, nil)
+	cardsRepo.On("GetCardsByOwner", mock.Anything, "regular").Return(model.Cards{{Static: static[0], Done: 1}}, nil)
+
+	issued := make(map[string][]string)
+	cardsRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		c := args.Get(1).(model.Card)
+		issued[c.OwnerUsername] = append(issued[c.OwnerUsername], c.Static.ID)
+	}).Return(nil)
+	s := &CardsService{cardsRepo: cardsRepo, chainsRepo: newChainsRepo(chains)}
+
+	require.NoError(t, s.UpdateConstCards(context.Background(), users))
+	assert.Equal(t, map[string][]string{"newbie": {"1"}, "regular": {"2", "3"}}, issued)
+}
+
+func TestCardsService_UpdateLockedChain(t *testing.T) {
+	first := model.CardStatic{ID: "1", Type: model.TypeOrdinary, Pool: model.PoolConst, ChainName: "first steps", OrdSettings: &model.OrdSettings{}}
+	regular := model.CardStatic{ID: "2", Type: model.TypeOrdinary, Pool: model.PoolConst, ChainName: "regular", OrdSettings: &model.OrdSettings{Award: model.Award{XPoints: 10}}}
+	chains := model.Chains{{Name: "regular", Requires: []string{"first steps"}}}
+	card := model.Card{ID: "2", OwnerUsername: "user", Static: regular}
+
+	for name, tc := range map[string]struct {
+		constCards model.Cards
+		err        error
+	}{
+		"locked":   {constCards: model.Cards{{Static: first}, card}, err: model.ErrChainLocked},
+		"unlocked": {constCards: model.Cards{{Static: first, Done: 1}, card}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cardsRepo := new(mocks.CardsRepositoryMock)
+			cardsRepo.On("Get", mock.Anything, "2").Return(card, nil)
+			cardsRepo.On("GetCardsByOwnerPool", mock.Anything, "user", model.PoolConst).Return(tc.constCards, nil)
+			cardsRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+			s := &CardsService{cardsRepo: cardsRepo, chainsRepo: newChainsRepo(chains), usersRepo: newUsersRepo()}
+
+			_, xp, err := s.Update(context.Background(), "2", 0, 0, "", "")
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				cardsRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 10, xp)
+		})
+	}
+}
+
+func newChainsRepo(chains model.Chains) *mocks.ChainsRepositoryMock {
+	chainsRepo := new(mocks.ChainsRepositoryMock)
+	chainsRepo.On("GetAll", mock.Anything).Return(chains, nil)
+	return chainsRepo
+}
+
+func newSeasonsRepo(seasons model.Seasons) *mocks.SeasonsRepositoryMock {
+	seasonsRepo := new(mocks.SeasonsRepositoryMock)
+	seasonsRepo.On("GetAll", mock.Anything).Return(seasons, nil)
+	return seasonsRepo
+}
+
+// newUsersRepo returns users, other accounts have no user record.
+func newUsersRepo(users ...model.User) *mocks.UsersRepositoryMock {
+	usersRepo := new(mocks.UsersRepositoryMock)
+	for _, u := range users {
+		usersRepo.On("GetByUsername", mock.Anything, u.Username).Return(u, nil)
+	}
+	usersRepo.On("GetByUsername", mock.Anything, mock.Anything).Return(model.User{}, model.ErrUserNotFound)
+	return usersRepo
+}
+
+func defaultGoalsRepo() *mocks.GoalsRepositoryMock {
+	goalsRepo := new(mocks.GoalsRepositoryMock)
+	goalsRepo.On("GetAll", mock.Anything).Return(model.DefaultGoals, nil)
+	return goalsRepo
+}
+
+func TestCardsService_UpdateSeasonCards(t *testing.T) {
+	now := time.Now()
+	seasons := model.Seasons{
+		{Name: "past", StartsAt: now.AddDate(0, -2, 0), EndsAt: now.AddDate(0, -1, 0)},
+		{Name: "live", StartsAt: now.AddDate(0, -1, 0), EndsAt: now.AddDate(0, 1, 0)},
+	}
+	static := model.CardsStatic{
+		{ID: "1", Pool: model.PoolSeason, Season: "past"},
+		{ID: "2", Pool: model.PoolSeason, Season: "live"},
+		{ID: "3", Pool: model.PoolSeason, Season: "live"},
+	}
+
+	cardsRepo := new(mocks.CardsRepositoryMock)
+	cardsRepo.On("GetStaticByPool", mock.Anything, model.PoolSeason).Return(static, nil)
+	cardsRepo.On("GetCardsByOwnerPool", mock.Anything, "user", model.PoolSeason).Return(model.Cards{{Static: static[1]}}, nil)
+	cardsRepo.On("Create", mock.Anything, static[2].Card("user")).Return(nil).Once()
+	s := &CardsService{cardsRepo: cardsRepo, seasonsRepo: newSeasonsRepo(seasons)}
+
+	users := []model.User{{CredentialsSecure: model.CredentialsSecure{Username: "user"}}}
+	require.NoError(t, s.UpdateSeasonCards(context.Background(), users))
+	cardsRepo.AssertExpectations(t)
+}
+
+func TestCardsService_SeasonEnded(t *testing.T) {
+	now := time.Now()
+	seasons := model.Seasons{
+		{Name: "past", StartsAt: now.AddDate(0, -2, 0), EndsAt: now.AddDate(0, -1, 0)},
+		{Name: "live", StartsAt: now.AddDate(0, -1, 0), EndsAt: now.AddDate(0, 1, 0)},
+	}
+	ordinary := &model.OrdSettings{Award: model.Award{XPoints: 10}}
+	pastCard := model.Card{ID: "1", Static: model.CardStatic{ID: "1", Type: model.TypeOrdinary, Pool: model.PoolSeason, Season: "past", OrdSettings: ordinary}}
+	liveCard := model.Card{ID: "2", Static: model.CardStatic{ID: "2", Type: model.TypeOrdinary, Pool: model.PoolSeason, Season: "live", OrdSettings: ordinary}}
+	doneCard := model.Card{ID: "3", Static: pastCard.Static, Done: 1}
+
+	cardsRepo := new(mocks.CardsRepositoryMock)
+	cardsRepo.On("GetCardsByOwnerPool", mock.Anything, "user", model.PoolSeason).Return(model.Cards{pastCard, liveCard, doneCard}, nil)
+	cardsRepo.On("GetCardsByOwnerPool", mock.Anything, "user", mock.Anything).Return(model.Cards{}, nil)
+	cardsRepo.On("Get", mock.Anything, "1").Return(pastCard, nil)
+	s := &CardsService{cardsRepo: cardsRepo, chainsRepo: newChainsRepo(nil), seasonsRepo: newSeasonsRepo(seasons), usersRepo: newUsersRepo()}
+
+	t.Run("grouped by season", func(t *testing.T) {
+		pools, _, err := s.GetFormattedCards(context.Background(), model.User{CredentialsSecure: model.CredentialsSecure{Username: "user"}})
+		require.NoError(t, err)
+		require.Len(t, pools, 5)
+		assert.Equal(t, []string{model.PoolDaily, model.PoolWeekly, model.PoolConst}, []string{pools[0].Pool, pools[1].Pool, pools[2].Pool})
+
+		assert.Equal(t, model.PoolCards{
+			Pool: model.PoolSeason, Season: "past", Pending: model.Cards{}, Done: model.Cards{doneCard}, Expired: model.Cards{pastCard},
+		}, pools[3])
+		assert.Equal(t, model.PoolCards{
+			Pool: model.PoolSeason, Season: "live", Pending: model.Cards{liveCard}, Done: model.Cards{},
+		}, pools[4])
+	})
+
+	t.Run("progress is frozen", func(t *testing.T) {
+		_, _, err := s.Update(context.Background(), "1", 0, 0, "", "")
+		assert.ErrorIs(t, err, model.ErrSeasonEnded)
+		cardsRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
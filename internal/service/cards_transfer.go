@@ -0,0 +1,720 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+// csvHeader is the column order of exported csv files. Awards are flattened
+// into the xpoints, prize and prize_image_url columns, options and streak cards
+// keep one value per option or milestone separated by csvListSep, so do the
+// steps of checklist cards. A csvListSep or a backslash inside a value is
+// escaped with a backslash. The availability window takes the starts_at,
+// ends_at, weekdays, from_hour and to_hour columns, times are in RFC 3339. The
+// repeat settings take max_completions and cooldown, such as "4h". An empty
+// weight is the default one, season is the season of cards of the season pool.
+var csvHeader = []string{
+	"external_id", "title", "short_description", "long_description", "goal", "type", "pool",
+	"background_url", "chain_name", "chain_order", "max_progress", "options",
+	"streak_days", "grace_days", "milestones", "step_ids", "step_titles", "xpoints", "prize", "prize_image_url",
+	"starts_at", "ends_at", "weekdays", "from_hour", "to_hour", "max_completions", "cooldown",
+	"weight", "season",
+}
+
+const csvListSep = ";"
+
+// ExportStatic writes every static card, archived ones too, in the format.
+func (s *CardsService) ExportStatic(ctx context.Context, w io.Writer, format string) error {
+	cards, err := s.cardsRepo.GetStatic(ctx, []string{"*"})
+	if err != nil {
+		return err
+	}
+	return encodeCards(w, format, cards)
+}
+
+// importedCard is a valid card of an import file with the card it replaces.
+type importedCard struct {
+	card     model.CardStatic
+	previous *model.CardStatic
+}
+
+// ImportStatic reads static cards in the format and validates all of them
+// first. Cards are matched by external id, in the create mode a card which
+// exists is a violation, in the upsert mode it gets a new version. Nothing is
+// written on a dry run or if any card is invalid, the latter returns
+// model.ErrInvalidImport together with the report.
+func (s *CardsService) ImportStatic(ctx context.Context, r io.Reader, format, mode string, dryRun bool) (model.ImportReport, error) {
+	if mode != model.ImportCreate && mode != model.ImportUpsert {
+		return model.ImportReport{}, model.ErrUnknownImportMode
+	}
+
+	records, err := decodeCards(r, format)
+	if err != nil {
+		return model.ImportReport{}, err
+	}
+
+	goals, err := s.goalsRepo.GetAll(ctx)
+	if err != nil {
+		return model.ImportReport{}, err
+	}
+	chain, err := s.cardsRepo.GetStaticByPool(ctx, model.PoolConst)
+	if err != nil {
+		return model.ImportReport{}, err
+	}
+	seasons, err := s.seasonsRepo.GetAll(ctx)
+	if err != nil {
+		return model.ImportReport{}, err
+	}
+
+	report := model.ImportReport{DryRun: dryRun, Rows: make([]model.ImportRow, 0, len(records))}
+	imported := make([]importedCard, 0, len(records))
+	rows := make(map[string]int, len(records))
+
+	for i, record := range records {
+		row := model.ImportRow{Row: i + 1, ExternalID: record.ExternalID, Violations: record.violations}
+		violation := func(field, message string) {
+			row.Violations = append(row.Violations, model.Violation{Field: field, Message: message})
+		}
+		card := record.card()
+
+		switch n, ok := rows[card.ExternalID]; {
+		case card.ExternalID == "":
+			violation("external_id", "is required")
+		case ok:
+			violation("external_id", fmt.Sprintf("is repeated, first in row %d", n))
+		default:
+			rows[card.ExternalID] = row.Row
+		}
+
+		var previous *model.CardStatic
+		if card.ExternalID != "" {
+			if previous, err = s.getStaticByExternalID(ctx, card.ExternalID); err != nil {
+				return model.ImportReport{}, err
+			}
+		}
+
+		// new cards get a temporary id to tell them apart in the chains
+		card.ID = "row:" + strconv.Itoa(row.Row)
+		row.Action = model.ImportCreated
+		if previous != nil {
+			card.ID = previous.ID
+			row.Action = model.ImportUpdated
+			if mode == model.ImportCreate {
+				violation("external_id", "card exists, import in the upsert mode to update it")
+			}
+		}
+
+		var verr *model.ValidationError
+		if err := card.Validate(goals, chain, seasons); errors.As(err, &verr) {
+			row.Violations = append(row.Violations, verr.Violations...)
+		}
+
+		if len(row.Violations) != 0 {
+			row.Action = ""
+			report.Invalid++
+			report.Rows = append(report.Rows, row)
+			continue
+		}
+
+		chain = chain.Replace(card)
+		dropPoolFields(&card)
+		imported = append(imported, importedCard{card: card, previous: previous})
+		if previous != nil {
+			report.Updated++
+		} else {
+			report.Created++
+		}
+		report.Rows = append(report.Rows, row)
+	}
+
+	if report.Invalid != 0 {
+		return report, model.ErrInvalidImport
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	for _, c := range imported {
+		if c.previous == nil {
+			err = s.createStatic(ctx, c.card)
+		} else {
+			_, err = s.updateStatic(ctx, *c.previous, c.card)
+		}
+		if err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// getStaticByExternalID returns nil if there is no such card. Exported cards
+// without an external id are keyed by their id, so it is tried too.
+func (s *CardsService) getStaticByExternalID(ctx context.Context, externalID string) (*model.CardStatic, error) {
+	card, err := s.cardsRepo.GetStaticByExternalID(ctx, externalID)
+	if err == nil {
+		return &card, nil
+	}
+	if !errors.Is(err, model.ErrNoSuchCard) {
+		return nil, err
+	}
+
+	cards, err := s.cardsRepo.GetStatic(ctx, []string{externalID})
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range cards {
+		if c.ID == externalID {
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+// cardRecord is a static card in import and export files. It has the shape
+// of the create card request plus the external id.
+type cardRecord struct {
+	ExternalID        string                   `json:"external_id" yaml:"external_id"`
+	Title             string                   `json:"title" yaml:"title"`
+	ShortDescription  string                   `json:"short_description" yaml:"short_description"`
+	LongDescription   string                   `json:"long_description" yaml:"long_description"`
+	Goal              string                   `json:"goal" yaml:"goal"`
+	Type              string                   `json:"type" yaml:"type"`
+	Pool              string                   `json:"pool" yaml:"pool"`
+	BackgroundURL     string                   `json:"background_url,omitempty" yaml:"background_url,omitempty"`
+	ChainName         string                   `json:"chain_name,omitempty" yaml:"chain_name,omitempty"`
+	ChainOrder        *int                     `json:"chain_order,omitempty" yaml:"chain_order,omitempty"`
+	Season            string                   `json:"season,omitempty" yaml:"season,omitempty"`
+	OrdSettings       *model.OrdSettings       `json:"ordinary_settings,omitempty" yaml:"ordinary_settings,omitempty"`
+	PrgSettings       *model.PrgSettings       `json:"progress_settings,omitempty" yaml:"progress_settings,omitempty"`
+	OptSettings       *model.OptSettings       `json:"options_settings,omitempty" yaml:"options_settings,omitempty"`
+	StreakSettings    *model.StreakSettings    `json:"streak_settings,omitempty" yaml:"streak_settings,omitempty"`
+	ChecklistSettings *model.ChecklistSettings `json:"checklist_settings,omitempty" yaml:"checklist_settings,omitempty"`
+	Availability      *model.Availability      `json:"availability,omitempty" yaml:"availability,omitempty"`
+	Repeat            *model.RepeatSettings    `json:"repeat_settings,omitempty" yaml:"repeat_settings,omitempty"`
+	Weight            int                      `json:"weight,omitempty" yaml:"weight,omitempty"`
+
+	// violations found while reading the record
+	violations []model.Violation
+}
+
+// toCardRecord exports card, cards created without an external id are keyed by
+// their id.
+func toCardRecord(card model.CardStatic) cardRecord {
+	r := cardRecord{
+		ExternalID:        card.ExternalID,
+		Title:             card.Title,
+		ShortDescription:  card.ShortDescription,
+		LongDescription:   card.LongDescription,
+		Goal:              card.Goal,
+		Type:              card.Type,
+		Pool:              card.Pool,
+		BackgroundURL:     card.BackgroundURL,
+		OrdSettings:       card.OrdSettings,
+		PrgSettings:       card.PrgSettings,
+		OptSettings:       card.OptSettings,
+		StreakSettings:    card.StreakSettings,
+		ChecklistSettings: card.ChecklistSettings,
+		Availability:      card.Availability,
+		Repeat:            card.Repeat,
+		Weight:            card.Weight,
+		Season:            card.Season,
+	}
+	if r.ExternalID == "" {
+		r.ExternalID = card.ID
+	}
+
+	if card.Pool == model.PoolConst {
+		chainOrder := card.ChainOrder
+		r.ChainName = card.ChainName
+		r.ChainOrder = &chainOrder
+	}
+	return r
+}
+
+// card returns the static card of the record, a missing chain order is
+// negative.
+func (r cardRecord) card() model.CardStatic {
+	chainOrder := -1
+	if r.ChainOrder != nil {
+		chainOrder = *r.ChainOrder
+	}
+
+	return model.CardStatic{
+		ExternalID:        r.ExternalID,
+		Title:             r.Title,
+		ShortDescription:  r.ShortDescription,
+		LongDescription:   r.LongDescription,
+		Goal:              r.Goal,
+		Type:              r.Type,
+		Pool:              r.Pool,
+		BackgroundURL:     r.BackgroundURL,
+		ChainName:         r.ChainName,
+		ChainOrder:        chainOrder,
+		Season:            r.Season,
+		OrdSettings:       r.OrdSettings,
+		PrgSettings:       r.PrgSettings,
+		OptSettings:       r.OptSettings,
+		StreakSettings:    r.StreakSettings,
+		ChecklistSettings: r.ChecklistSettings,
+		Availability:      r.Availability,
+		Repeat:            r.Repeat,
+		Weight:            r.Weight,
+	}
+}
+
+func encodeCards(w io.Writer, format string, cards model.CardsStatic) error {
+	records := make([]cardRecord, len(cards))
+	for i, c := range cards {
+		records[i] = toCardRecord(c)
+	}
+
+	switch format {
+	case model.FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	case model.FormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(records); err != nil {
+			return err
+		}
+		return enc.Close()
+	case model.FormatCSV:
+		return encodeCardsCSV(w, records)
+	}
+	return model.ErrUnknownFormat
+}
+
+func decodeCards(r io.Reader, format string) ([]cardRecord, error) {
+	var records []cardRecord
+
+	switch format {
+	case model.FormatJSON:
+		dec := json.NewDecoder(r)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&records); err != nil {
+			return nil, fmt.Errorf("%w: %s", model.ErrInvalidImportFile, err)
+		}
+	case model.FormatYAML:
+		dec := yaml.NewDecoder(r)
+		dec.KnownFields(true)
+		if err := dec.Decode(&records); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("%w: %s", model.ErrInvalidImportFile, err)
+		}
+	case model.FormatCSV:
+		var err error
+		if records, err = decodeCardsCSV(r); err != nil {
+			return nil, fmt.Errorf("%w: %s", model.ErrInvalidImportFile, err)
+		}
+	default:
+		return nil, model.ErrUnknownFormat
+	}
+
+	return records, nil
+}
+
+func encodeCardsCSV(w io.Writer, records []cardRecord) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, r := range records {
+		var chainOrder, maxProgress, options, streakDays, graceDays, milestones, stepIDs, stepTitles string
+		if r.ChainOrder != nil {
+			chainOrder = strconv.Itoa(*r.ChainOrder)
+		}
+
+		var awards []model.Award
+		switch {
+		case r.OrdSettings != nil:
+			awards = []model.Award{r.OrdSettings.Award}
+		case r.PrgSettings != nil:
+			awards = []model.Award{r.PrgSettings.Award}
+			maxProgress = strconv.Itoa(r.PrgSettings.MaxProgress)
+		case r.OptSettings != nil:
+			awards = r.OptSettings.Awards
+			opts := make([]string, len(r.OptSettings.Options))
+			for i, o := range r.OptSettings.Options {
+				opts[i] = strconv.FormatFloat(float64(o), 'f', -1, 32)
+			}
+			options = joinCSVList(opts)
+		case r.StreakSettings != nil:
+			streakDays = strconv.Itoa(r.StreakSettings.Days)
+			graceDays = strconv.Itoa(r.StreakSettings.GraceDays)
+			days := make([]string, len(r.StreakSettings.Milestones))
+			for i, m := range r.StreakSettings.Milestones {
+				days[i] = strconv.Itoa(m.Day)
+				awards = append(awards, m.Award)
+			}
+			milestones = joinCSVList(days)
+		case r.ChecklistSettings != nil:
+			awards = []model.Award{r.ChecklistSettings.Award}
+			ids := make([]string, len(r.ChecklistSettings.Steps))
+			titles := make([]string, len(r.ChecklistSettings.Steps))
+			for i, step := range r.ChecklistSettings.Steps {
+				ids[i] = step.ID
+				titles[i] = step.Title
+			}
+			stepIDs = joinCSVList(ids)
+			stepTitles = joinCSVList(titles)
+		}
+
+		xpoints := make([]string, len(awards))
+		prizes := make([]string, len(awards))
+		images := make([]string, len(awards))
+		for i, a := range awards {
+			xpoints[i] = strconv.Itoa(a.XPoints)
+			prizes[i] = a.Prize
+			images[i] = a.PrizeImageURL
+		}
+
+		var startsAt, endsAt, weekdays, fromHour, toHour string
+		if a := r.Availability; a != nil {
+			if !a.StartsAt.IsZero() {
+				startsAt = a.StartsAt.Format(time.RFC3339)
+			}
+			if !a.EndsAt.IsZero() {
+				endsAt = a.EndsAt.Format(time.RFC3339)
+			}
+			days := make([]string, len(a.Weekdays))
+			for i, d := range a.Weekdays {
+				days[i] = strconv.Itoa(int(d))
+			}
+			weekdays = joinCSVList(days)
+			fromHour = strconv.Itoa(a.FromHour)
+			toHour = strconv.Itoa(a.ToHour)
+		}
+
+		var maxCompletions, cooldown string
+		if s := r.Repeat; s != nil {
+			maxCompletions = strconv.Itoa(s.MaxCompletions)
+			cooldown = s.Cooldown.String()
+		}
+
+		var weight string
+		if r.Weight != 0 {
+			weight = strconv.Itoa(r.Weight)
+		}
+
+		err := cw.Write([]string{
+			r.ExternalID, r.Title, r.ShortDescription, r.LongDescription, r.Goal, r.Type, r.Pool,
+			r.BackgroundURL, r.ChainName, chainOrder, maxProgress, options, streakDays, graceDays, milestones, stepIDs, stepTitles,
+			joinCSVList(xpoints), joinCSVList(prizes), joinCSVList(images),
+			startsAt, endsAt, weekdays, fromHour, toHour, maxCompletions, cooldown, weight, r.Season,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// decodeCardsCSV reads the columns by the header, so they may come in any
+// order. Cells which can't be parsed are reported as violations of the row.
+func decodeCardsCSV(r io.Reader) ([]cardRecord, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, h := range header {
+		columns[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, h := range []string{"external_id", "title", "goal", "type", "pool"} {
+		if _, ok := columns[h]; !ok {
+			return nil, fmt.Errorf("no %s column", h)
+		}
+	}
+
+	var records []cardRecord
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, csvRecord(row, columns))
+	}
+}
+
+func csvRecord(row []string, columns map[string]int) cardRecord {
+	cell := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	r := cardRecord{
+		ExternalID:       cell("external_id"),
+		Title:            cell("title"),
+		ShortDescription: cell("short_description"),
+		LongDescription:  cell("long_description"),
+		Goal:             cell("goal"),
+		Type:             cell("type"),
+		Pool:             cell("pool"),
+		BackgroundURL:    cell("background_url"),
+		ChainName:        cell("chain_name"),
+		Season:           cell("season"),
+	}
+	violation := func(field, message string) {
+		r.violations = append(r.violations, model.Violation{Field: field, Message: message})
+	}
+
+	if v := cell("chain_order"); v != "" {
+		chainOrder, err := strconv.Atoi(v)
+		if err != nil {
+			violation("chain_order", "must be an integer")
+		}
+		r.ChainOrder = &chainOrder
+	}
+
+	if v := cell("weight"); v != "" {
+		weight, err := strconv.Atoi(v)
+		if err != nil {
+			violation("weight", "must be an integer")
+		}
+		r.Weight = weight
+	}
+
+	r.Availability = csvAvailability(cell, violation)
+	r.Repeat = csvRepeat(cell, violation)
+
+	xpoints := splitCSVList(cell("xpoints"))
+	prizes := splitCSVList(cell("prize"))
+	images := splitCSVList(cell("prize_image_url"))
+	if (len(prizes) != 0 && len(prizes) != len(xpoints)) || (len(images) != 0 && len(images) != len(xpoints)) {
+		violation("prize", "xpoints, prize and prize_image_url must have the same number of values")
+	}
+
+	awards := make([]model.Award, len(xpoints))
+	var badXPoints bool
+	for i, v := range xpoints {
+		xp, err := strconv.Atoi(v)
+		badXPoints = badXPoints || err != nil
+		awards[i].XPoints = xp
+		if i < len(prizes) {
+			awards[i].Prize = prizes[i]
+		}
+		if i < len(images) {
+			awards[i].PrizeImageURL = images[i]
+		}
+	}
+	if badXPoints {
+		violation("xpoints", "must be integers")
+	}
+
+	single := func() model.Award {
+		if len(awards) != 1 {
+			violation("xpoints", "must have exactly one value for "+r.Type+" cards")
+			return model.Award{}
+		}
+		return awards[0]
+	}
+
+	switch r.Type {
+	case model.TypeOrdinary:
+		r.OrdSettings = &model.OrdSettings{Award: single()}
+	case model.TypeProgress:
+		maxProgress, err := strconv.Atoi(cell("max_progress"))
+		if err != nil {
+			violation("max_progress", "must be an integer")
+		}
+		r.PrgSettings = &model.PrgSettings{Award: single(), MaxProgress: maxProgress}
+	case model.TypeOptions:
+		values := splitCSVList(cell("options"))
+		options := make([]float32, len(values))
+		var badOptions bool
+		for i, v := range values {
+			o, err := strconv.ParseFloat(v, 32)
+			badOptions = badOptions || err != nil
+			options[i] = float32(o)
+		}
+		if badOptions {
+			violation("options", "must be numbers")
+		}
+		r.OptSettings = &model.OptSettings{Awards: awards, Options: options}
+	case model.TypeStreak:
+		days, err := strconv.Atoi(cell("streak_days"))
+		if err != nil {
+			violation("streak_days", "must be an integer")
+		}
+		var graceDays int
+		if v := cell("grace_days"); v != "" {
+			if graceDays, err = strconv.Atoi(v); err != nil {
+				violation("grace_days", "must be an integer")
+			}
+		}
+
+		values := splitCSVList(cell("milestones"))
+		if len(values) != len(awards) {
+			violation("milestones", "must have one value per award")
+		}
+		milestones := make([]model.Milestone, len(values))
+		var badMilestones bool
+		for i, v := range values {
+			day, err := strconv.Atoi(v)
+			badMilestones = badMilestones || err != nil
+			milestones[i].Day = day
+			if i < len(awards) {
+				milestones[i].Award = awards[i]
+			}
+		}
+		if badMilestones {
+			violation("milestones", "must be integers")
+		}
+		r.StreakSettings = &model.StreakSettings{Days: days, GraceDays: graceDays, Milestones: milestones}
+	case model.TypeChecklist:
+		ids := splitCSVList(cell("step_ids"))
+		titles := splitCSVList(cell("step_titles"))
+		if len(titles) != len(ids) {
+			violation("step_titles", "must have one value per step id")
+		}
+		steps := make([]model.Step, len(ids))
+		for i, id := range ids {
+			steps[i].ID = id
+			if i < len(titles) {
+				steps[i].Title = titles[i]
+			}
+		}
+		r.ChecklistSettings = &model.ChecklistSettings{Steps: steps, Award: single()}
+	}
+
+	return r
+}
+
+// csvAvailability reads the availability window, it is nil when all of its
+// cells are empty.
+func csvAvailability(cell func(string) string, violation func(field, message string)) *model.Availability {
+	empty := true
+	for _, c := range []string{"starts_at", "ends_at", "weekdays", "from_hour", "to_hour"} {
+		empty = empty && cell(c) == ""
+	}
+	if empty {
+		return nil
+	}
+
+	a := new(model.Availability)
+	for _, c := range []struct {
+		name string
+		t    *time.Time
+	}{{"starts_at", &a.StartsAt}, {"ends_at", &a.EndsAt}} {
+		if v := cell(c.name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				violation(c.name, "must be a time in RFC 3339")
+			}
+			*c.t = t
+		}
+	}
+
+	var badWeekdays bool
+	for _, v := range splitCSVList(cell("weekdays")) {
+		d, err := strconv.Atoi(v)
+		badWeekdays = badWeekdays || err != nil
+		a.Weekdays = append(a.Weekdays, time.Weekday(d))
+	}
+	if badWeekdays {
+		violation("weekdays", "must be integers")
+	}
+
+	for _, c := range []struct {
+		name string
+		hour *int
+	}{{"from_hour", &a.FromHour}, {"to_hour", &a.ToHour}} {
+		if v := cell(c.name); v != "" {
+			h, err := strconv.Atoi(v)
+			if err != nil {
+				violation(c.name, "must be an integer")
+			}
+			*c.hour = h
+		}
+	}
+	return a
+}
+
+// csvRepeat reads the repeat settings, they are nil when both cells are empty.
+func csvRepeat(cell func(string) string, violation func(field, message string)) *model.RepeatSettings {
+	maxCompletions, cooldown := cell("max_completions"), cell("cooldown")
+	if maxCompletions == "" && cooldown == "" {
+		return nil
+	}
+
+	s := new(model.RepeatSettings)
+	if maxCompletions != "" {
+		n, err := strconv.Atoi(maxCompletions)
+		if err != nil {
+			violation("max_completions", "must be an integer")
+		}
+		s.MaxCompletions = n
+	}
+	if cooldown != "" {
+		if err := s.Cooldown.UnmarshalText([]byte(cooldown)); err != nil {
+			violation("cooldown", "must be a duration such as 4h or 30m")
+		}
+	}
+	return s
+}
+
+var csvListEscaper = strings.NewReplacer(`\`, `\\`, csvListSep, `\`+csvListSep)
+
+func joinCSVList(values []string) string {
+	escaped := make([]string, len(values))
+	for i, v := range values {
+		escaped[i] = csvListEscaper.Replace(v)
+	}
+	return strings.Join(escaped, csvListSep)
+}
+
+// splitCSVList is the reverse of joinCSVList, a backslash takes the next
+// character as it is.
+func splitCSVList(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	var (
+		values []string
+		value  strings.Builder
+	)
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			i++
+			value.WriteByte(s[i])
+		case strings.HasPrefix(s[i:], csvListSep):
+			values = append(values, strings.TrimSpace(value.String()))
+			value.Reset()
+			i += len(csvListSep) - 1
+		default:
+			value.WriteByte(s[i])
+		}
+	}
+	return append(values, strings.TrimSpace(value.String()))
+}
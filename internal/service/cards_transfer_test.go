@@ -0,0 +1,213 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+	"github.com/Andrei-Raev/xp-loyalty/internal/model/mocks"
+)
+
+func transferCards() model.CardsStatic {
+	return model.CardsStatic{
+		{
+			ID: "1", ExternalID: "coffee", Title: "Coffee", Goal: model.GoalBuyDrink, Type: model.TypeOrdinary, Pool: model.PoolDaily,
+			OrdSettings: &model.OrdSettings{Award: model.Award{XPoints: 10, Prize: "cookie", PrizeImageURL: "http://img/cookie"}},
+			Repeat:      &model.RepeatSettings{MaxCompletions: 3, Cooldown: model.Duration{Duration: 4 * time.Hour}},
+		},
+		{
+			ID: "2", Title: "Three games", Goal: model.GoalPlayMore, Type: model.TypeProgress, Pool: model.PoolConst,
+			ChainName: "first steps", ChainOrder: 0,
+			PrgSettings: &model.PrgSettings{Award: model.Award{XPoints: 30}, MaxProgress: 3},
+		},
+		{
+			ID: "3", ExternalID: "score", Title: "Score", Goal: model.GoalPlayMore, Type: model.TypeOptions, Pool: model.PoolDaily,
+			OptSettings: &model.OptSettings{
+				Options: []float32{1, 2.5},
+				Awards:  []model.Award{{XPoints: 5, Prize: "a"}, {XPoints: 15, Prize: "b"}},
+			},
+		},
+		{
+			ID: "4", ExternalID: "week", Title: "Week of coffee", Goal: model.GoalBuyDrink, Type: model.TypeStreak, Pool: model.PoolConst,
+			ChainName: "week", ChainOrder: 0,
+			StreakSettings: &model.StreakSettings{
+				Days: 7, GraceDays: 1,
+				Milestones: []model.Milestone{{Day: 3, Award: model.Award{XPoints: 10}}, {Day: 7, Award: model.Award{XPoints: 50, Prize: "mug"}}},
+			},
+		},
+		{
+			ID: "5", ExternalID: "cocktails", Title: "Try three cocktails", Goal: model.GoalBuyDrink, Type: model.TypeChecklist, Pool: model.PoolDaily,
+			ChecklistSettings: &model.ChecklistSettings{
+				Steps: []model.Step{{ID: "mojito", Title: "Mojito"}, {ID: "negroni", Title: "Negroni"}, {ID: "spritz", Title: "Spritz"}},
+				Award: model.Award{XPoints: 40, Prize: "cocktail"},
+			},
+			Availability: &model.Availability{
+				StartsAt: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+				EndsAt:   time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC),
+				Weekdays: []time.Weekday{time.Friday, time.Saturday},
+				FromHour: 18,
+				ToHour:   2,
+			},
+		},
+	}
+}
+
+func TestCardsTransfer_RoundTrip(t *testing.T) {
+	for _, format := range []string{model.FormatJSON, model.FormatCSV, model.FormatYAML} {
+		t.Run(format, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			require.NoError(t, encodeCards(buf, format, transferCards()))
+
+			records, err := decodeCards(buf, format)
+			require.NoError(t, err)
+			require.Len(t, records, 5)
+
+			for i, c := range transferCards() {
+				if c.ExternalID == "" {
+					c.ExternalID = c.ID
+				}
+				c.ID = ""
+
+				got := records[i].card()
+				if c.Pool != model.PoolConst {
+					c.ChainOrder = -1
+				}
+				assert.Empty(t, records[i].violations)
+				assert.Equal(t, c, got)
+			}
+		})
+	}
+}
+
+func TestCardsTransfer_CSVListEscaping(t *testing.T) {
+	card := model.CardStatic{
+		ExternalID: "mixes", Title: "Mixes", Goal: model.GoalBuyDrink, Type: model.TypeChecklist, Pool: model.PoolDaily,
+		ChainOrder: -1,
+		ChecklistSettings: &model.ChecklistSettings{
+			Steps: []model.Step{{ID: "gin;tonic", Title: "Gin; tonic"}, {ID: `rum\cola`, Title: `Rum \ cola;`}},
+			Award: model.Award{XPoints: 40, Prize: `two; drinks \o/`, PrizeImageURL: "http://img/a;b"},
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, encodeCards(buf, model.FormatCSV, model.CardsStatic{card}))
+
+	records, err := decodeCards(buf, model.FormatCSV)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Empty(t, records[0].violations)
+	assert.Equal(t, card, records[0].card())
+}
+
+func TestCardsTransfer_CSVViolations(t *testing.T) {
+	file := "external_id,title,goal,type,pool,chain_order,max_progress,xpoints,prize\n" +
+		"a,A,food,progress,daily,,x,1;2,p\n" +
+		"b,B,food,ordinary,const,first,,7,\n"
+
+	records, err := decodeCards(strings.NewReader(file), model.FormatCSV)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	fields := func(r cardRecord) []string {
+		var f []string
+		for _, v := range r.violations {
+			f = append(f, v.Field)
+		}
+		return f
+	}
+	assert.Equal(t, []string{"prize", "max_progress", "xpoints"}, fields(records[0]))
+	assert.Equal(t, []string{"chain_order"}, fields(records[1]))
+
+	_, err = decodeCards(strings.NewReader("title,goal\nA,food\n"), model.FormatCSV)
+	assert.ErrorIs(t, err, model.ErrInvalidImportFile)
+}
+
+func TestCardsService_ImportStatic(t *testing.T) {
+	ctx := context.Background()
+	existing := transferCards()[0]
+	existing.Version = 1
+	file := `
+- external_id: coffee
+  title: Coffee and cake
+  goal: drink
+  type: ordinary
+  pool: daily
+  ordinary_settings:
+    award:
+      XPoints: 20
+- external_id: karaoke
+  title: Sing
+  goal: play
+  type: ordinary
+  pool: const
+  chain_name: first steps
+  chain_order: 0
+  ordinary_settings:
+    award:
+      XPoints: 5
+`
+
+	newService := func() (*CardsService, *mocks.CardsRepositoryMock) {
+		cardsRepo := new(mocks.CardsRepositoryMock)
+		cardsRepo.On("GetStaticByPool", mock.Anything, model.PoolConst).Return(model.CardsStatic{}, nil)
+		cardsRepo.On("GetStaticByExternalID", mock.Anything, "coffee").Return(existing, nil)
+		cardsRepo.On("GetStaticByExternalID", mock.Anything, "karaoke").Return(model.CardStatic{}, model.ErrNoSuchCard)
+		cardsRepo.On("GetStatic", mock.Anything, []string{"karaoke"}).Return(model.CardsStatic{}, nil)
+		return &CardsService{cardsRepo: cardsRepo, goalsRepo: defaultGoalsRepo(), seasonsRepo: newSeasonsRepo(nil)}, cardsRepo
+	}
+
+	t.Run("create mode rejects existing cards", func(t *testing.T) {
+		s, _ := newService()
+
+		report, err := s.ImportStatic(ctx, strings.NewReader(file), model.FormatYAML, model.ImportCreate, false)
+		require.ErrorIs(t, err, model.ErrInvalidImport)
+		assert.Equal(t, 1, report.Invalid)
+		assert.Equal(t, "external_id", report.Rows[0].Violations[0].Field)
+		assert.Equal(t, model.ImportCreated, report.Rows[1].Action)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		s, cardsRepo := newService()
+
+		report, err := s.ImportStatic(ctx, strings.NewReader(file), model.FormatYAML, model.ImportUpsert, true)
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, 1, report.Updated)
+		cardsRepo.AssertNotCalled(t, "CreateStatic", mock.Anything, mock.Anything)
+		cardsRepo.AssertNotCalled(t, "UpdateStatic", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("upsert", func(t *testing.T) {
+		s, cardsRepo := newService()
+		cardsRepo.On("UpdateStatic", mock.Anything, existing, mock.MatchedBy(func(c model.CardStatic) bool {
+			return c.ID == "1" && c.Version == 2 && c.Title == "Coffee and cake"
+		})).Return(nil).Once()
+		cardsRepo.On("CreateStatic", mock.Anything, mock.MatchedBy(func(c model.CardStatic) bool {
+			return c.ID == "" && c.ExternalID == "karaoke" && c.ChainOrder == 0
+		})).Return(nil).Once()
+
+		report, err := s.ImportStatic(ctx, strings.NewReader(file), model.FormatYAML, model.ImportUpsert, false)
+		require.NoError(t, err)
+		assert.Equal(t, 0, report.Invalid)
+		cardsRepo.AssertExpectations(t)
+	})
+
+	t.Run("colliding chain orders in the file", func(t *testing.T) {
+		s, cardsRepo := newService()
+		cardsRepo.On("GetStaticByExternalID", mock.Anything, "karaoke-2").Return(model.CardStatic{}, model.ErrNoSuchCard)
+		cardsRepo.On("GetStatic", mock.Anything, []string{"karaoke-2"}).Return(model.CardsStatic{}, nil)
+		twice := file + strings.Replace(file[strings.Index(file, "- external_id: karaoke"):], "karaoke", "karaoke-2", 1)
+
+		report, err := s.ImportStatic(ctx, strings.NewReader(twice), model.FormatYAML, model.ImportUpsert, true)
+		require.ErrorIs(t, err, model.ErrInvalidImport)
+		require.Len(t, report.Rows, 3)
+		assert.Equal(t, "chain_order", report.Rows[2].Violations[0].Field)
+	})
+}
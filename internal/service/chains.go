@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type ChainsRepository interface {
+	GetAll(ctx context.Context) (model.Chains, error)
+	Set(ctx context.Context, chain model.Chain) error
+	Delete(ctx context.Context, name string) error
+}
+
+// ChainCardsRepository gives the const cards, chains are made of them.
+type ChainCardsRepository interface {
+	GetStaticByPool(ctx context.Context, pool string) (model.CardsStatic, error)
+}
+
+type ChainsService struct {
+	repo      ChainsRepository
+	cardsRepo ChainCardsRepository
+}
+
+func NewChainsService(repo ChainsRepository, cardsRepo ChainCardsRepository) *ChainsService {
+	return &ChainsService{repo: repo, cardsRepo: cardsRepo}
+}
+
+func (s *ChainsService) GetAll(ctx context.Context) (model.Chains, error) {
+	return s.repo.GetAll(ctx)
+}
+
+// Set replaces the requirements of the chain, they are rejected with a
+// *model.ValidationError if they refer to unknown chains or form a cycle.
+func (s *ChainsService) Set(ctx context.Context, chain model.Chain) (model.Chain, error) {
+	chain.Name = strings.TrimSpace(chain.Name)
+	for i := range chain.Requires {
+		chain.Requires[i] = strings.TrimSpace(chain.Requires[i])
+	}
+
+	cards, err := s.cardsRepo.GetStaticByPool(ctx, model.PoolConst)
+	if err != nil {
+		return model.Chain{}, err
+	}
+	chains, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return model.Chain{}, err
+	}
+
+	if err := chain.Validate(cards.ChainNames(), chains); err != nil {
+		return model.Chain{}, err
+	}
+	if err := s.repo.Set(ctx, chain); err != nil {
+		return model.Chain{}, err
+	}
+	return chain, nil
+}
+
+// Delete removes the requirements of a chain, so it unlocks for everybody.
+func (s *ChainsService) Delete(ctx context.Context, name string) error {
+	return s.repo.Delete(ctx, name)
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+	"github.com/Andrei-Raev/xp-loyalty/internal/model/mocks"
+)
+
+func TestChainsService_Set(t *testing.T) {
+	ctx := context.Background()
+	cards := model.CardsStatic{
+		{ID: "1", Pool: model.PoolConst, ChainName: "first steps"},
+		{ID: "2", Pool: model.PoolConst, ChainName: "regular"},
+	}
+
+	newService := func(chains model.Chains) (*ChainsService, *mocks.ChainsRepositoryMock) {
+		cardsRepo := new(mocks.CardsRepositoryMock)
+		cardsRepo.On("GetStaticByPool", mock.Anything, model.PoolConst).Return(cards, nil)
+		repo := newChainsRepo(chains)
+		return NewChainsService(repo, cardsRepo), repo
+	}
+
+	t.Run("requirements", func(t *testing.T) {
+		s, repo := newService(nil)
+		repo.On("Set", mock.Anything, model.Chain{Name: "regular", Requires: []string{"first steps"}, MinLevel: 2}).Return(nil).Once()
+
+		_, err := s.Set(ctx, model.Chain{Name: "regular", Requires: []string{" first steps"}, MinLevel: 2})
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		s, repo := newService(model.Chains{{Name: "regular", Requires: []string{"first steps"}}})
+
+		_, err := s.Set(ctx, model.Chain{Name: "first steps", Requires: []string{"regular"}})
+		var verr *model.ValidationError
+		require.ErrorAs(t, err, &verr)
+		assert.Equal(t, "requires", verr.Violations[0].Field)
+		repo.AssertNotCalled(t, "Set", mock.Anything, mock.Anything)
+	})
+}
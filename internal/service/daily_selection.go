@@ -0,0 +1,148 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+	"github.com/Andrei-Raev/xp-loyalty/pkg/config"
+)
+
+const (
+	DailySelectionUniform  = "uniform"
+	DailySelectionPersonal = "personal"
+
+	defaultRecentDays  = 3
+	defaultHistoryDays = 30
+)
+
+// DailyDraw is what the cards of a user in a rotating pool are picked from.
+// Issued are the cards of the pool issued to the user since HistoryDays of the
+// selector, UserCards are the cards of the pool the user has. Selectors pick
+// with Rand only, so a draw with the same seed picks the same cards.
+type DailyDraw struct {
+	Cards      model.CardsStatic
+	Num        int
+	UniqueGoal bool
+	Now        time.Time
+	Issued     []model.DailyIssue
+	UserCards  model.Cards
+	Rand       *rand.Rand
+}
+
+// DrawSeed returns the seed of the draw of the user in the rotating pool for
+// the period starting at start.
+func DrawSeed(secret, pool, username string, start time.Time) int64 {
+	msg := start.Format("2006-01-02") + "|" + username
+	// daily seeds stay as they were before the other pools
+	if pool != model.PoolDaily {
+		msg = pool + "|" + msg
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(msg))
+	return int64(binary.BigEndian.Uint64(mac.Sum(nil)))
+}
+
+// DailySelector picks the daily cards of a user, it returns no cards when there
+// are not enough of them.
+type DailySelector interface {
+	Select(draw DailyDraw) []model.CardStatic
+	// HistoryDays is how many days of history Select looks at, with zero the
+	// draw has no history.
+	HistoryDays() int
+}
+
+// NewDailySelector returns the selector configured by cfg.
+func NewDailySelector(cfg config.DailySelection) (DailySelector, error) {
+	switch cfg.Strategy {
+	case "", DailySelectionUniform:
+		return UniformSelector{}, nil
+	case DailySelectionPersonal:
+		if cfg.RecentDays < 0 || cfg.HistoryDays < 0 {
+			return nil, fmt.Errorf("error daily selection: recent_days and history_days must not be negative")
+		}
+
+		s := PersonalSelector{RecentDays: cfg.RecentDays, History: cfg.HistoryDays}
+		if s.RecentDays == 0 {
+			s.RecentDays = defaultRecentDays
+		}
+		if s.History == 0 {
+			s.History = defaultHistoryDays
+		}
+		return s, nil
+	default:
+		return nil, fmt.Errorf("error daily selection: unknown strategy %q", cfg.Strategy)
+	}
+}
+
+// UniformSelector picks daily cards by their weights only.
+type UniformSelector struct{}
+
+func (UniformSelector) Select(draw DailyDraw) []model.CardStatic {
+	return draw.Cards.Random(draw.Rand, draw.Num, draw.UniqueGoal)
+}
+
+func (UniformSelector) HistoryDays() int {
+	return 0
+}
+
+// PersonalSelector skips cards issued to the user in the last RecentDays and
+// scales the weights of cards by how often the user completed their goal in
+// the last History days. Goals the user never got count as half completed, so
+// they still show up. Recent cards are picked again only if there are not
+// enough other cards.
+type PersonalSelector struct {
+	RecentDays int
+	History    int
+}
+
+func (s PersonalSelector) HistoryDays() int {
+	if s.RecentDays > s.History {
+		return s.RecentDays
+	}
+	return s.History
+}
+
+func (s PersonalSelector) Select(draw DailyDraw) []model.CardStatic {
+	recentSince := draw.Now.AddDate(0, 0, -s.RecentDays)
+	historySince := draw.Now.AddDate(0, 0, -s.History)
+
+	recent := make(map[string]bool)
+	issued := make(map[string]int)
+	for _, i := range draw.Issued {
+		if !i.IssuedAt.Before(recentSince) {
+			recent[i.StaticID] = true
+		}
+		if !i.IssuedAt.Before(historySince) {
+			issued[i.Goal]++
+		}
+	}
+
+	done := make(map[string]int)
+	for _, c := range draw.UserCards {
+		if c.Done > 0 && !c.CompletedAt.Before(historySince) {
+			done[c.Static.Goal]++
+		}
+	}
+
+	weight := func(c model.CardStatic) float64 {
+		completion := float64(done[c.Goal]+1) / float64(issued[c.Goal]+2)
+		return float64(c.SelectionWeight()) * completion
+	}
+
+	fresh := make(model.CardsStatic, 0, len(draw.Cards))
+	for _, c := range draw.Cards {
+		if !recent[c.ID] {
+			fresh = append(fresh, c)
+		}
+	}
+	if cards := fresh.RandomWeighted(draw.Rand, draw.Num, draw.UniqueGoal, weight); len(cards) != 0 {
+		return cards
+	}
+	return draw.Cards.RandomWeighted(draw.Rand, draw.Num, draw.UniqueGoal, weight)
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+	"github.com/Andrei-Raev/xp-loyalty/internal/model/mocks"
+	"github.com/Andrei-Raev/xp-loyalty/pkg/config"
+)
+
+func TestNewDailySelector(t *testing.T) {
+	s, err := NewDailySelector(config.DailySelection{})
+	require.NoError(t, err)
+	assert.Equal(t, UniformSelector{}, s)
+
+	s, err = NewDailySelector(config.DailySelection{Strategy: DailySelectionPersonal, RecentDays: 7})
+	require.NoError(t, err)
+	assert.Equal(t, PersonalSelector{RecentDays: 7, History: defaultHistoryDays}, s)
+
+	_, err = NewDailySelector(config.DailySelection{Strategy: DailySelectionPersonal, RecentDays: -1})
+	assert.Error(t, err)
+	_, err = NewDailySelector(config.DailySelection{Strategy: "lucky"})
+	assert.Error(t, err)
+}
+
+func TestPersonalSelector_SkipsRecentCards(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	s := PersonalSelector{RecentDays: 3, History: 30}
+	draw := DailyDraw{
+		Cards: model.CardsStatic{
+			{ID: "1", Goal: model.GoalBuyFood},
+			{ID: "2", Goal: model.GoalBuyFood},
+			{ID: "3", Goal: model.GoalBuyDrink},
+		},
+		Num:  1,
+		Now:  now,
+		Rand: rand.New(rand.NewSource(1)),
+		Issued: []model.DailyIssue{
+			{StaticID: "1", Goal: model.GoalBuyFood, IssuedAt: now.AddDate(0, 0, -1)},
+			{StaticID: "3", Goal: model.GoalBuyDrink, IssuedAt: now.AddDate(0, 0, -2)},
+			{StaticID: "2", Goal: model.GoalBuyFood, IssuedAt: now.AddDate(0, 0, -5)},
+		},
+	}
+	for i := 0; i < 50; i++ {
+		cards := s.Select(draw)
+		require.Len(t, cards, 1)
+		assert.Equal(t, "2", cards[0].ID)
+	}
+
+	// recent cards are better than none
+	draw.Num = 2
+	assert.Len(t, s.Select(draw), 2)
+}
+
+func TestPersonalSelector_FavoursCompletedGoals(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	s := PersonalSelector{RecentDays: 1, History: 30}
+	draw := DailyDraw{
+		Cards: model.CardsStatic{
+			{ID: "1", Goal: model.GoalBuyFood},
+			{ID: "2", Goal: model.GoalPlayMore},
+		},
+		Num:  1,
+		Now:  now,
+		Rand: rand.New(rand.NewSource(1)),
+	}
+	for d := 2; d < 12; d++ {
+		issuedAt := now.AddDate(0, 0, -d)
+		draw.Issued = append(draw.Issued,
+			model.DailyIssue{StaticID: "1", Goal: model.GoalBuyFood, IssuedAt: issuedAt},
+			model.DailyIssue{StaticID: "2", Goal: model.GoalPlayMore, IssuedAt: issuedAt},
+		)
+		draw.UserCards = append(draw.UserCards, model.Card{
+			Static:      model.CardStatic{ID: "2", Goal: model.GoalPlayMore},
+			Done:        1,
+			CompletedAt: issuedAt,
+		})
+	}
+
+	picked := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		picked[s.Select(draw)[0].ID]++
+	}
+	assert.Greater(t, picked["2"], 5*picked["1"])
+	assert.NotZero(t, picked["1"])
+}
+
+func TestCardsService_RotatePoolsPersonal(t *testing.T) {
+	static := model.CardsStatic{{ID: "1", Goal: model.GoalBuyFood}, {ID: "2", Goal: model.GoalBuyDrink}}
+	issued := []model.DailyIssue{{Username: "user", StaticID: "1", Goal: model.GoalBuyFood, IssuedAt: time.Now().Add(-time.Hour)}}
+
+	cardsRepo := new(mocks.CardsRepositoryMock)
+	cardsRepo.On("GetStaticByPool", mock.Anything, model.PoolDaily).Return(static, nil)
+	cardsRepo.On("GetDailyIssues", mock.Anything, "user", mock.Anything).Return(issued, nil)
+	cardsRepo.On("GetCardsByOwnerPool", mock.Anything, "user", model.PoolDaily).Return(model.Cards{}, nil)
+	cardsRepo.On("DeleteUsersPendingCards", mock.Anything, "user", model.PoolDaily).Return(nil)
+	cardsRepo.On("Create", mock.Anything, mock.MatchedBy(func(c model.Card) bool {
+		return c.Static.ID == "2"
+	})).Return(nil).Once()
+	cardsRepo.On("AddDailyIssues", mock.Anything, mock.Anything).Return(nil).Once()
+	s := &CardsService{
+		cardsRepo: cardsRepo,
+		selector:  PersonalSelector{RecentDays: 3, History: 30},
+		daily:     config.User{DailyCardsNum: 1},
+	}
+
+	users := []model.User{{CredentialsSecure: model.CredentialsSecure{Username: "user"}}}
+	updated, err := s.RotatePools(context.Background(), users)
+	require.NoError(t, err)
+	assert.Len(t, updated, 1)
+	cardsRepo.AssertExpectations(t)
+}
+
+func TestDrawSeed(t *testing.T) {
+	day := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	seed := DrawSeed("secret", model.PoolDaily, "user", day)
+
+	assert.Equal(t, seed, DrawSeed("secret", model.PoolDaily, "user", day))
+	assert.NotEqual(t, seed, DrawSeed("secret", model.PoolDaily, "other", day))
+	assert.NotEqual(t, seed, DrawSeed("secret", model.PoolDaily, "user", day.AddDate(0, 0, 1)))
+	assert.NotEqual(t, seed, DrawSeed("other secret", model.PoolDaily, "user", day))
+	assert.NotEqual(t, seed, DrawSeed("secret", model.PoolWeekly, "user", day))
+}
+
+func TestCardsService_ReplayDailyCards(t *testing.T) {
+	static := model.CardsStatic{
+		{ID: "1", Goal: model.GoalBuyFood},
+		{ID: "2", Goal: model.GoalBuyDrink},
+		{ID: "3", Goal: model.GoalPlayMore},
+		{ID: "4", Goal: model.GoalSocialActivity},
+	}
+	var created []model.Card
+	var issued []model.DailyIssue
+
+	cardsRepo := new(mocks.CardsRepositoryMock)
+	cardsRepo.On("GetStaticByPool", mock.Anything, model.PoolDaily).Return(static, nil).Once()
+	cardsRepo.On("DeleteUsersPendingCards", mock.Anything, "user", model.PoolDaily).Return(nil)
+	cardsRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		created = append(created, args.Get(1).(model.Card))
+	})
+	cardsRepo.On("AddDailyIssues", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		issued = append(issued, args.Get(1).([]model.DailyIssue)...)
+	})
+	s := &CardsService{cardsRepo: cardsRepo, daily: config.User{
+		DailyCardsNum:  2,
+		DailySelection: config.DailySelection{SeedSecret: "secret"},
+	}}
+
+	users := []model.User{{CredentialsSecure: model.CredentialsSecure{Username: "user"}}}
+	updated, err := s.RotatePools(context.Background(), users)
+	require.NoError(t, err)
+	require.Len(t, created, 2)
+	day := model.DailyStart(updated[0].LastDailyCardsUpdate, time.Local, 0)
+	assert.Equal(t, DrawSeed("secret", model.PoolDaily, "user", day), created[0].Seed)
+
+	// the catalog comes back in another order
+	shuffled := model.CardsStatic{static[3], static[1], static[2], static[0]}
+	cardsRepo.On("GetStaticByPool", mock.Anything, model.PoolDaily).Return(shuffled, nil).Once()
+	cardsRepo.On("GetDailyIssues", mock.Anything, "user", day).Return(issued, nil)
+
+	replay, err := s.ReplayDailyCards(context.Background(), users[0], day)
+	require.NoError(t, err)
+	assert.Equal(t, day, replay.Day)
+	assert.Equal(t, created[0].Seed, replay.Seed)
+	assert.Equal(t, issued, replay.Issued)
+	require.Len(t, replay.Cards, 2)
+	assert.Equal(t, created[0].Static.ID, replay.Cards[0].ID)
+	assert.Equal(t, created[1].Static.ID, replay.Cards[1].ID)
+}
+
+func TestCardsService_RotatePoolsResetHour(t *testing.T) {
+	now := time.Now().UTC()
+	start := model.DailyStart(now, time.UTC, now.Hour())
+
+	cardsRepo := new(mocks.CardsRepositoryMock)
+	cardsRepo.On("GetStaticByPool", mock.Anything, model.PoolDaily).Return(model.CardsStatic{{ID: "1"}}, nil)
+	cardsRepo.On("DeleteUsersPendingCards", mock.Anything, "venue", model.PoolDaily).Return(nil).Once()
+	cardsRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+	cardsRepo.On("AddDailyIssues", mock.Anything, mock.Anything).Return(nil).Once()
+	s := &CardsService{cardsRepo: cardsRepo, daily: config.User{DailyCardsNum: 1, Timezone: "UTC", ResetHour: now.Hour()}}
+
+	users := []model.User{
+		{CredentialsSecure: model.CredentialsSecure{Username: "venue"}, LastDailyCardsUpdate: start.Add(-time.Minute)},
+		{CredentialsSecure: model.CredentialsSecure{Username: "updated"}, LastDailyCardsUpdate: start},
+		{CredentialsSecure: model.CredentialsSecure{Username: "tokyo"}, LastDailyCardsUpdate: start.Add(-time.Minute), Timezone: "Asia/Tokyo"},
+	}
+	updated, err := s.RotatePools(context.Background(), users)
+	require.NoError(t, err)
+	require.Len(t, updated, 1)
+	assert.Equal(t, "venue", updated[0].Username)
+	cardsRepo.AssertExpectations(t)
+}
+
+func TestCardsService_RotatePoolsWeekly(t *testing.T) {
+	now := time.Now().UTC()
+	week := model.WeeklyStart(now, time.UTC, now.Weekday(), now.Hour())
+
+	cardsRepo := new(mocks.CardsRepositoryMock)
+	cardsRepo.On("GetStaticByPool", mock.Anything, model.PoolDaily).Return(model.CardsStatic{{ID: "1"}}, nil)
+	cardsRepo.On("GetStaticByPool", mock.Anything, model.PoolWeekly).Return(model.CardsStatic{{ID: "2"}, {ID: "3"}}, nil)
+	cardsRepo.On("DeleteUsersPendingCards", mock.Anything, "user", model.PoolWeekly).Return(nil).Once()
+	cardsRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Twice()
+	cardsRepo.On("AddDailyIssues", mock.Anything, mock.MatchedBy(func(issues []model.DailyIssue) bool {
+		return len(issues) == 2 && issues[0].Pool == model.PoolWeekly
+	})).Return(nil).Once()
+	s := &CardsService{cardsRepo: cardsRepo, daily: config.User{
+		DailyCardsNum:  1,
+		WeeklyCardsNum: 2,
+		WeeklyResetDay: now.Weekday().String(),
+		Timezone:       "UTC",
+		ResetHour:      now.Hour(),
+	}}
+
+	// the day is the same, the week is new
+	users := []model.User{{
+		CredentialsSecure:     model.CredentialsSecure{Username: "user"},
+		LastDailyCardsUpdate:  now,
+		LastWeeklyCardsUpdate: week.Add(-time.Minute),
+	}}
+	updated, err := s.RotatePools(context.Background(), users)
+	require.NoError(t, err)
+	require.Len(t, updated, 1)
+	assert.Equal(t, now, updated[0].LastDailyCardsUpdate)
+	assert.False(t, updated[0].LastWeeklyCardsUpdate.Before(week))
+	cardsRepo.AssertExpectations(t)
+}
+
+func TestCardsService_RotatePoolsEmptyWeekly(t *testing.T) {
+	cardsRepo := new(mocks.CardsRepositoryMock)
+	cardsRepo.On("GetStaticByPool", mock.Anything, model.PoolDaily).Return(model.CardsStatic{{ID: "1"}}, nil)
+	cardsRepo.On("GetStaticByPool", mock.Anything, model.PoolWeekly).Return(model.CardsStatic{}, nil)
+	cardsRepo.On("DeleteUsersPendingCards", mock.Anything, mock.Anything, model.PoolDaily).Return(nil).Twice()
+	cardsRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Twice()
+	cardsRepo.On("AddDailyIssues", mock.Anything, mock.Anything).Return(nil).Twice()
+	s := &CardsService{cardsRepo: cardsRepo, daily: config.User{DailyCardsNum: 1, WeeklyCardsNum: 2}}
+
+	users := []model.User{
+		{CredentialsSecure: model.CredentialsSecure{Username: "first"}},
+		{CredentialsSecure: model.CredentialsSecure{Username: "second"}},
+	}
+	updated, err := s.RotatePools(context.Background(), users)
+	assert.ErrorIs(t, err, model.ErrNoRandomCards)
+	require.Len(t, updated, 2)
+	for _, u := range updated {
+		assert.False(t, u.LastDailyCardsUpdate.IsZero())
+		assert.True(t, u.LastWeeklyCardsUpdate.IsZero())
+	}
+
+	// the saved users are not rotated again
+	updated, err = s.RotatePools(context.Background(), updated)
+	assert.ErrorIs(t, err, model.ErrNoRandomCards)
+	assert.Empty(t, updated)
+	cardsRepo.AssertExpectations(t)
+	cardsRepo.AssertNotCalled(t, "DeleteUsersPendingCards", mock.Anything, mock.Anything, model.PoolWeekly)
+}
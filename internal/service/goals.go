@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type GoalsRepository interface {
+	Create(ctx context.Context, goal model.Goal) (string, error)
+	GetAll(ctx context.Context) (model.Goals, error)
+	GetByName(ctx context.Context, name string) (model.Goal, error)
+	UpdateTitle(ctx context.Context, name, title string) error
+	Delete(ctx context.Context, name string) error
+}
+
+// GoalCardsRepository tells whether static cards still refer to a goal.
+type GoalCardsRepository interface {
+	CountStaticByGoal(ctx context.Context, goal string) (int, error)
+}
+
+type GoalsService struct {
+	repo      GoalsRepository
+	cardsRepo GoalCardsRepository
+}
+
+func NewGoalsService(repo GoalsRepository, cardsRepo GoalCardsRepository) *GoalsService {
+	return &GoalsService{repo: repo, cardsRepo: cardsRepo}
+}
+
+// SeedDefaults creates model.DefaultGoals when there are no goals yet.
+func (s *GoalsService) SeedDefaults(ctx context.Context) error {
+	goals, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	if len(goals) != 0 {
+		return nil
+	}
+
+	for _, g := range model.DefaultGoals {
+		if _, err := s.Create(ctx, g); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *GoalsService) Create(ctx context.Context, goal model.Goal) (model.Goal, error) {
+	goal.Name = strings.ToLower(strings.TrimSpace(goal.Name))
+	if err := goal.Validate(); err != nil {
+		return model.Goal{}, err
+	}
+	if goal.Title == "" {
+		goal.Title = goal.Name
+	}
+
+	_, err := s.repo.GetByName(ctx, goal.Name)
+	switch {
+	case errors.Is(err, model.ErrGoalNotFound):
+	case err == nil:
+		return model.Goal{}, model.ErrGoalExists
+	default:
+		return model.Goal{}, err
+	}
+
+	goal.CreatedAt = time.Now()
+	goal.ID, err = s.repo.Create(ctx, goal)
+	if err != nil {
+		return model.Goal{}, err
+	}
+	return goal, nil
+}
+
+func (s *GoalsService) GetAll(ctx context.Context) (model.Goals, error) {
+	return s.repo.GetAll(ctx)
+}
+
+// UpdateTitle renames the goal for people, its name stays as cards refer to it.
+func (s *GoalsService) UpdateTitle(ctx context.Context, name, title string) error {
+	return s.repo.UpdateTitle(ctx, name, title)
+}
+
+// Delete removes a goal no static card refers to.
+func (s *GoalsService) Delete(ctx context.Context, name string) error {
+	n, err := s.cardsRepo.CountStaticByGoal(ctx, name)
+	if err != nil {
+		return err
+	}
+	if n != 0 {
+		return model.ErrGoalInUse
+	}
+	return s.repo.Delete(ctx, name)
+}
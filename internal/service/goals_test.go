@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+	"github.com/Andrei-Raev/xp-loyalty/internal/model/mocks"
+)
+
+func TestGoalsService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("create", func(t *testing.T) {
+		repo := new(mocks.GoalsRepositoryMock)
+		repo.On("GetByName", mock.Anything, "karaoke").Return(model.Goal{}, model.ErrGoalNotFound)
+		repo.On("Create", mock.Anything, mock.MatchedBy(func(g model.Goal) bool {
+			return g.Name == "karaoke" && g.Title == "karaoke" && !g.CreatedAt.IsZero()
+		})).Return("1", nil).Once()
+		s := NewGoalsService(repo, nil)
+
+		goal, err := s.Create(ctx, model.Goal{Name: " Karaoke "})
+		require.NoError(t, err)
+		require.Equal(t, "1", goal.ID)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("create existing", func(t *testing.T) {
+		repo := new(mocks.GoalsRepositoryMock)
+		repo.On("GetByName", mock.Anything, "merch").Return(model.Goal{ID: "1", Name: "merch"}, nil)
+		s := NewGoalsService(repo, nil)
+
+		_, err := s.Create(ctx, model.Goal{Name: "merch"})
+		require.ErrorIs(t, err, model.ErrGoalExists)
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		s := NewGoalsService(new(mocks.GoalsRepositoryMock), nil)
+
+		_, err := s.Create(ctx, model.Goal{Name: "live music"})
+		require.ErrorIs(t, err, model.ErrInvalidGoalName)
+	})
+
+	t.Run("delete goal in use", func(t *testing.T) {
+		cardsRepo := new(mocks.CardsRepositoryMock)
+		cardsRepo.On("CountStaticByGoal", mock.Anything, model.GoalBuyFood).Return(2, nil)
+		s := NewGoalsService(new(mocks.GoalsRepositoryMock), cardsRepo)
+
+		require.ErrorIs(t, s.Delete(ctx, model.GoalBuyFood), model.ErrGoalInUse)
+	})
+
+	t.Run("seed only empty storage", func(t *testing.T) {
+		repo := new(mocks.GoalsRepositoryMock)
+		repo.On("GetAll", mock.Anything).Return(model.Goals{{Name: "karaoke"}}, nil)
+		s := NewGoalsService(repo, nil)
+
+		require.NoError(t, s.SeedDefaults(ctx))
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+	"github.com/Andrei-Raev/xp-loyalty/pkg/config"
+)
+
+const (
+	throttleScopeUsername = "username"
+	throttleScopeIP       = "ip"
+)
+
+type LoginAttemptsRepository interface {
+	Get(ctx context.Context, key string) (model.LoginAttempts, error)
+	// AddFailure counts a failure at now in a single write, failures of a key
+	// whose last failure is before since are forgotten first. It returns the
+	// attempts after the write.
+	AddFailure(ctx context.Context, key string, now, since time.Time) (model.LoginAttempts, error)
+	// Lock locks the key until the given time unless it is locked for longer.
+	Lock(ctx context.Context, key string, until time.Time) error
+	Delete(ctx context.Context, key string) error
+}
+
+type BlockedSignInCounter interface {
+	CountBlockedSignIn(scope string)
+}
+
+type LoginThrottleService struct {
+	repo             LoginAttemptsRepository
+	counter          BlockedSignInCounter
+	usernameAttempts int
+	ipAttempts       int
+	baseDelay        time.Duration
+	maxDelay         time.Duration
+	resetAfter       time.Duration
+}
+
+func NewLoginThrottleService(repo LoginAttemptsRepository, counter BlockedSignInCounter, cfg config.Lockout) *LoginThrottleService {
+	s := &LoginThrottleService{
+		repo:             repo,
+		counter:          counter,
+		usernameAttempts: cfg.UsernameAttempts,
+		ipAttempts:       cfg.IPAttempts,
+		baseDelay:        cfg.BaseDelay.Duration,
+		maxDelay:         cfg.MaxDelay.Duration,
+		resetAfter:       cfg.ResetAfter.Duration,
+	}
+
+	if s.usernameAttempts == 0 {
+		s.usernameAttempts = 5
+	}
+	if s.ipAttempts == 0 {
+		s.ipAttempts = 20
+	}
+	if s.baseDelay == 0 {
+		s.baseDelay = 30 * time.Second
+	}
+	if s.maxDelay == 0 {
+		s.maxDelay = time.Hour
+	}
+	if s.resetAfter == 0 {
+		s.resetAfter = 24 * time.Hour
+	}
+	return s
+}
+
+type throttleKey struct {
+	scope    string
+	key      string
+	attempts int
+}
+
+func (s *LoginThrottleService) keys(username, ip string) []throttleKey {
+	return []throttleKey{
+		{scope: throttleScopeUsername, key: "username:" + username, attempts: s.usernameAttempts},
+		{scope: throttleScopeIP, key: "ip:" + ip, attempts: s.ipAttempts},
+	}
+}
+
+// Check returns model.ErrTooManyAttempts and the time left if the username or
+// the IP is locked.
+func (s *LoginThrottleService) Check(ctx context.Context, username, ip string) (time.Duration, error) {
+	now := time.Now()
+	for _, k := range s.keys(username, ip) {
+		attempts, err := s.repo.Get(ctx, k.key)
+		if err != nil {
+			return 0, err
+		}
+
+		if now.Before(attempts.LockedUntil) {
+			s.counter.CountBlockedSignIn(k.scope)
+			return attempts.LockedUntil.Sub(now), model.ErrTooManyAttempts
+		}
+	}
+	return 0, nil
+}
+
+// Fail records a failed sign in for both the username and the IP. Failures are
+// counted atomically, so parallel guesses can't get past the limit.
+func (s *LoginThrottleService) Fail(ctx context.Context, username, ip string) error {
+	now := time.Now()
+	for _, k := range s.keys(username, ip) {
+		attempts, err := s.repo.AddFailure(ctx, k.key, now, now.Add(-s.resetAfter))
+		if err != nil {
+			return err
+		}
+
+		if attempts.Failures >= k.attempts {
+			if err := s.repo.Lock(ctx, k.key, now.Add(s.delay(attempts.Failures-k.attempts))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Succeed forgets failures of the username. Failures of the IP are kept, so one
+// known password can't be used to unlock guessing of others from the same IP.
+func (s *LoginThrottleService) Succeed(ctx context.Context, username string) error {
+	return s.repo.Delete(ctx, "username:"+username)
+}
+
+func (s *LoginThrottleService) delay(step int) time.Duration {
+	delay := s.baseDelay
+	for i := 0; i < step && delay < s.maxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > s.maxDelay {
+		return s.maxDelay
+	}
+	return delay
+}
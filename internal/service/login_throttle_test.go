@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+	"github.com/Andrei-Raev/xp-loyalty/internal/model/mocks"
+	"github.com/Andrei-Raev/xp-loyalty/pkg/config"
+)
+
+type blockedCounterStub struct {
+	scopes []string
+}
+
+func (c *blockedCounterStub) CountBlockedSignIn(scope string) {
+	c.scopes = append(c.scopes, scope)
+}
+
+func TestLoginThrottleService(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Lockout{
+		UsernameAttempts: 3,
+		IPAttempts:       10,
+		BaseDelay:        config.Duration{Duration: time.Minute},
+		MaxDelay:         config.Duration{Duration: 3 * time.Minute},
+		ResetAfter:       config.Duration{Duration: time.Hour},
+	}
+
+	t.Run("delay doubles up to max", func(t *testing.T) {
+		s := NewLoginThrottleService(nil, nil, cfg)
+		require.Equal(t, time.Minute, s.delay(0))
+		require.Equal(t, 2*time.Minute, s.delay(1))
+		require.Equal(t, 3*time.Minute, s.delay(2))
+		require.Equal(t, 3*time.Minute, s.delay(100))
+	})
+
+	t.Run("failure over the limit locks username", func(t *testing.T) {
+		repo := new(mocks.LoginAttemptsRepositoryMock)
+		repo.On("AddFailure", mock.Anything, "username:user", mock.Anything, mock.MatchedBy(func(since time.Time) bool {
+			return time.Since(since) > 59*time.Minute
+		})).Return(model.LoginAttempts{Key: "username:user", Failures: 3}, nil).Once()
+		repo.On("AddFailure", mock.Anything, "ip:1.1.1.1", mock.Anything, mock.Anything).
+			Return(model.LoginAttempts{Key: "ip:1.1.1.1", Failures: 1}, nil).Once()
+		repo.On("Lock", mock.Anything, "username:user", mock.MatchedBy(func(until time.Time) bool {
+			return time.Until(until) > 59*time.Second && time.Until(until) <= time.Minute
+		})).Return(nil).Once()
+
+		s := NewLoginThrottleService(repo, nil, cfg)
+		require.NoError(t, s.Fail(ctx, "user", "1.1.1.1"))
+		repo.AssertExpectations(t)
+		repo.AssertNotCalled(t, "Lock", mock.Anything, "ip:1.1.1.1", mock.Anything)
+	})
+
+	t.Run("every failure past the limit doubles the lock", func(t *testing.T) {
+		repo := new(mocks.LoginAttemptsRepositoryMock)
+		repo.On("AddFailure", mock.Anything, "username:user", mock.Anything, mock.Anything).
+			Return(model.LoginAttempts{Key: "username:user", Failures: 4}, nil).Once()
+		repo.On("AddFailure", mock.Anything, "ip:1.1.1.1", mock.Anything, mock.Anything).
+			Return(model.LoginAttempts{Key: "ip:1.1.1.1", Failures: 1}, nil).Once()
+		repo.On("Lock", mock.Anything, "username:user", mock.MatchedBy(func(until time.Time) bool {
+			return time.Until(until) > 119*time.Second
+		})).Return(nil).Once()
+
+		s := NewLoginThrottleService(repo, nil, cfg)
+		require.NoError(t, s.Fail(ctx, "user", "1.1.1.1"))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("success keeps failures of the ip", func(t *testing.T) {
+		repo := new(mocks.LoginAttemptsRepositoryMock)
+		repo.On("Delete", mock.Anything, "username:user").Return(nil).Once()
+
+		s := NewLoginThrottleService(repo, nil, cfg)
+		require.NoError(t, s.Succeed(ctx, "user"))
+		repo.AssertExpectations(t)
+		repo.AssertNotCalled(t, "Delete", mock.Anything, "ip:1.1.1.1")
+	})
+
+	t.Run("locked ip is blocked", func(t *testing.T) {
+		repo := new(mocks.LoginAttemptsRepositoryMock)
+		repo.On("Get", mock.Anything, "username:user").Return(model.LoginAttempts{}, nil)
+		repo.On("Get", mock.Anything, "ip:1.1.1.1").Return(model.LoginAttempts{
+			LockedUntil: time.Now().Add(time.Minute),
+		}, nil)
+
+		counter := new(blockedCounterStub)
+		s := NewLoginThrottleService(repo, counter, cfg)
+		retryAfter, err := s.Check(ctx, "user", "1.1.1.1")
+		require.ErrorIs(t, err, model.ErrTooManyAttempts)
+		require.Greater(t, retryAfter, 59*time.Second)
+		require.Equal(t, []string{throttleScopeIP}, counter.scopes)
+	})
+}
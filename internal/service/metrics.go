@@ -6,7 +6,8 @@ import (
 )
 
 type MetricsService struct {
-	httpRequestCounters *prometheus.CounterVec
+	httpRequestCounters   *prometheus.CounterVec
+	blockedSignInCounters *prometheus.CounterVec
 }
 
 func NewServiceMetrics() *MetricsService {
@@ -14,9 +15,17 @@ func NewServiceMetrics() *MetricsService {
 		Name: "winte_http_request_counters",
 		Help: "The total number of request",
 	}, []string{"method", "path"})
-	return &MetricsService{httpRequestCounters}
+	blockedSignInCounters := promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "winte_blocked_sign_in_counters",
+		Help: "The total number of sign in attempts blocked by lockout",
+	}, []string{"scope"})
+	return &MetricsService{httpRequestCounters, blockedSignInCounters}
 }
 
 func (s MetricsService) CountRequest(method, path string) {
 	s.httpRequestCounters.WithLabelValues(method, path).Inc()
 }
+
+func (s MetricsService) CountBlockedSignIn(scope string) {
+	s.blockedSignInCounters.WithLabelValues(scope).Inc()
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+	"github.com/Andrei-Raev/xp-loyalty/pkg/config"
+)
+
+const oidcDiscoveryPath = "/.well-known/openid-configuration"
+
+// oidcJWKSRefetchInterval limits how often an unknown key id makes the signing
+// keys be fetched again, tokens with made up key ids must not flood the provider.
+const oidcJWKSRefetchInterval = time.Minute
+
+// OIDCService signs staff in with the OpenID Connect authorization code flow
+// with PKCE. Provider metadata and signing keys are fetched on first use.
+type OIDCService struct {
+	cfg    config.OIDC
+	roles  map[string]model.Role
+	client *http.Client
+
+	mu       sync.Mutex
+	provider *oidcProvider
+	keys     map[string]*rsa.PublicKey
+	keysAt   time.Time
+}
+
+type oidcProvider struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+func NewOIDCService(cfg config.OIDC) (*OIDCService, error) {
+	roles := make(map[string]model.Role, len(cfg.Roles))
+	for claim, r := range cfg.Roles {
+		role := model.Role(r)
+		if role != model.RoleAdmin && role != model.RoleModerator && role != model.RoleCashier {
+			return nil, fmt.Errorf("oidc role %q must map to a staff role", claim)
+		}
+		roles[claim] = role
+	}
+
+	if cfg.UsernameClaim == "" {
+		cfg.UsernameClaim = "preferred_username"
+	}
+
+	return &OIDCService{
+		cfg:    cfg,
+		roles:  roles,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (s *OIDCService) Enabled() bool {
+	return s.cfg.Issuer != ""
+}
+
+// Login returns the provider URL to redirect to, together with the state,
+// nonce and code verifier the callback has to present.
+func (s *OIDCService) Login(ctx context.Context) (model.OIDCLogin, error) {
+	provider, err := s.getProvider(ctx)
+	if err != nil {
+		return model.OIDCLogin{}, err
+	}
+
+	var login model.OIDCLogin
+	for _, v := range []*string{&login.State, &login.Nonce, &login.Verifier} {
+		if *v, err = newRandomToken(); err != nil {
+			return model.OIDCLogin{}, err
+		}
+	}
+
+	challenge := sha256.Sum256([]byte(login.Verifier))
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", s.cfg.ClientID)
+	q.Set("redirect_uri", s.cfg.RedirectURL)
+	q.Set("scope", strings.Join(append([]string{"openid"}, s.cfg.Scopes...), " "))
+	q.Set("state", login.State)
+	q.Set("nonce", login.Nonce)
+	q.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	q.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(provider.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	login.URL = provider.AuthorizationEndpoint + sep + q.Encode()
+	return login, nil
+}
+
+// Exchange redeems the authorization code and verifies the returned ID token.
+func (s *OIDCService) Exchange(ctx context.Context, code string, login model.OIDCLogin) (model.Identity, error) {
+	provider, err := s.getProvider(ctx)
+	if err != nil {
+		return model.Identity{}, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", s.cfg.RedirectURL)
+	form.Set("code_verifier", login.Verifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, provider.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return model.Identity{}, fmt.Errorf("error oidc Exchange(): %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(s.cfg.ClientID), url.QueryEscape(s.cfg.ClientSecret))
+
+	var token struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := s.do(req, &token); err != nil {
+		return model.Identity{}, fmt.Errorf("error oidc Exchange(): %w", err)
+	}
+
+	return s.verifyIDToken(ctx, provider, token.IDToken, login.Nonce)
+}
+
+func (s *OIDCService) verifyIDToken(ctx context.Context, provider *oidcProvider, idToken, nonce string) (model.Identity, error) {
+	token, err := jwt.ParseWithClaims(idToken, jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if token.Method != jwt.SigningMethodRS256 {
+			return nil, model.ErrUnexpectedSigningMethod
+		}
+		kid, _ := token.Header["kid"].(string)
+		return s.getKey(ctx, provider, kid)
+	})
+	if err != nil {
+		return model.Identity{}, fmt.Errorf("%w: %s", model.ErrInvalidIDToken, err)
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return model.Identity{}, model.ErrInvalidIDToken
+	}
+
+	if !claims.VerifyIssuer(provider.Issuer, true) ||
+		!claims.VerifyAudience(s.cfg.ClientID, true) ||
+		!claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return model.Identity{}, model.ErrInvalidIDToken
+	}
+
+	if n, _ := claims["nonce"].(string); n == "" || n != nonce {
+		return model.Identity{}, fmt.Errorf("%w: nonce mismatch", model.ErrInvalidIDToken)
+	}
+
+	subject, _ := claims["sub"].(string)
+	username, _ := claims[s.cfg.UsernameClaim].(string)
+	if subject == "" || username == "" {
+		return model.Identity{}, fmt.Errorf("%w: no %s claim", model.ErrInvalidIDToken, s.cfg.UsernameClaim)
+	}
+
+	role, ok := s.role(claims[s.cfg.RoleClaim])
+	if !ok {
+		return model.Identity{}, model.ErrWrongRole
+	}
+
+	return model.Identity{
+		Issuer:   provider.Issuer,
+		Subject:  subject,
+		Username: username,
+		Role:     role,
+	}, nil
+}
+
+// role maps the role claim, of several mapped values the widest role wins.
+func (s *OIDCService) role(claim interface{}) (model.Role, bool) {
+	var values []string
+	switch v := claim.(type) {
+	case string:
+		values = append(values, v)
+	case []interface{}:
+		for _, e := range v {
+			if str, ok := e.(string); ok {
+				values = append(values, str)
+			}
+		}
+	}
+
+	var role model.Role
+	for _, v := range values {
+		r, ok := s.roles[v]
+		if ok && (role == 0 || r.Includes(role)) {
+			role = r
+		}
+	}
+	return role, role != 0
+}
+
+func (s *OIDCService) getProvider(ctx context.Context) (*oidcProvider, error) {
+	if !s.Enabled() {
+		return nil, model.ErrOIDCDisabled
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.provider != nil {
+		return s.provider, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(s.cfg.Issuer, "/")+oidcDiscoveryPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error oidc getProvider(): %w", err)
+	}
+
+	provider := new(oidcProvider)
+	if err := s.do(req, provider); err != nil {
+		return nil, fmt.Errorf("error oidc getProvider(): %w", err)
+	}
+
+	if provider.Issuer != s.cfg.Issuer {
+		return nil, fmt.Errorf("error oidc getProvider(): issuer %q does not match %q", provider.Issuer, s.cfg.Issuer)
+	}
+
+	s.provider = provider
+	return provider, nil
+}
+
+// getKey returns the signing key with kid, keys are fetched again when the
+// provider has rotated them, but not more often than oidcJWKSRefetchInterval.
+func (s *OIDCService) getKey(ctx context.Context, provider *oidcProvider, kid string) (*rsa.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	if !s.keysAt.IsZero() && time.Since(s.keysAt) < oidcJWKSRefetchInterval {
+		return nil, model.ErrUnknownSigningKey
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, provider.JWKSURI, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error oidc getKey(): %w", err)
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := s.do(req, &jwks); err != nil {
+		return nil, fmt.Errorf("error oidc getKey(): %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			continue
+		}
+
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	s.keys = keys
+	s.keysAt = time.Now()
+
+	key, ok := keys[kid]
+	if !ok {
+		return nil, model.ErrUnknownSigningKey
+	}
+	return key, nil
+}
+
+func (s *OIDCService) do(req *http.Request, dest interface{}) error {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s: %s", req.Method, req.URL.Path, resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(dest); err != nil {
+		return err
+	}
+	return nil
+}
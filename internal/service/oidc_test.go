@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+	"github.com/Andrei-Raev/xp-loyalty/internal/model/mocks"
+	"github.com/Andrei-Raev/xp-loyalty/pkg/config"
+)
+
+// mockIdP is a minimal OpenID provider, the next token endpoint call returns
+// an ID token with claims.
+type mockIdP struct {
+	*httptest.Server
+	key    *rsa.PrivateKey
+	kid    string
+	claims jwt.MapClaims
+	// jwksFetches counts the requests for the signing keys.
+	jwksFetches int
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	idp := &mockIdP{key: key, kid: "idp-key"}
+	mux := http.NewServeMux()
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Close)
+
+	mux.HandleFunc(oidcDiscoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(oidcProvider{
+			Issuer:                idp.URL,
+			AuthorizationEndpoint: idp.URL + "/authorize",
+			TokenEndpoint:         idp.URL + "/token",
+			JWKSURI:               idp.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		idp.jwksFetches++
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "idp-key",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "client" || pass != "secret" || r.FormValue("code") != "code" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, idp.claims)
+		token.Header["kid"] = idp.kid
+		idToken, err := token.SignedString(key)
+		require.NoError(t, err)
+		_ = json.NewEncoder(w).Encode(map[string]string{"id_token": idToken})
+	})
+
+	return idp
+}
+
+func TestOIDCService(t *testing.T) {
+	ctx := context.Background()
+	idp := newMockIdP(t)
+
+	s, err := NewOIDCService(config.OIDC{
+		Issuer:       idp.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost/api/auth/oidc/callback",
+		RoleClaim:    "groups",
+		Roles: map[string]int{
+			"admins":     int(model.RoleAdmin),
+			"moderators": int(model.RoleModerator),
+		},
+	})
+	require.NoError(t, err)
+
+	login, err := s.Login(ctx)
+	require.NoError(t, err)
+	u, err := url.Parse(login.URL)
+	require.NoError(t, err)
+	require.Equal(t, "/authorize", u.Path)
+	require.Equal(t, login.State, u.Query().Get("state"))
+	require.Equal(t, "S256", u.Query().Get("code_challenge_method"))
+
+	claims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":                idp.URL,
+			"aud":                "client",
+			"sub":                "42",
+			"exp":                time.Now().Add(time.Minute).Unix(),
+			"nonce":              login.Nonce,
+			"preferred_username": "alice",
+			"groups":             []string{"staff", "admins", "moderators"},
+		}
+	}
+
+	t.Run("widest mapped role", func(t *testing.T) {
+		idp.claims = claims()
+
+		identity, err := s.Exchange(ctx, "code", login)
+		require.NoError(t, err)
+		require.Equal(t, model.Identity{Issuer: idp.URL, Subject: "42", Username: "alice", Role: model.RoleModerator}, identity)
+	})
+
+	t.Run("no mapped role", func(t *testing.T) {
+		idp.claims = claims()
+		idp.claims["groups"] = []string{"staff"}
+
+		_, err := s.Exchange(ctx, "code", login)
+		require.ErrorIs(t, err, model.ErrWrongRole)
+	})
+
+	t.Run("wrong nonce", func(t *testing.T) {
+		idp.claims = claims()
+		idp.claims["nonce"] = "other"
+
+		_, err := s.Exchange(ctx, "code", login)
+		require.ErrorIs(t, err, model.ErrInvalidIDToken)
+	})
+
+	t.Run("wrong audience", func(t *testing.T) {
+		idp.claims = claims()
+		idp.claims["aud"] = "other-client"
+
+		_, err := s.Exchange(ctx, "code", login)
+		require.ErrorIs(t, err, model.ErrInvalidIDToken)
+	})
+
+	t.Run("unknown key id refetches keys once per interval", func(t *testing.T) {
+		t.Cleanup(func() { idp.kid = "idp-key" })
+		idp.kid = "rotated"
+		fetches := idp.jwksFetches
+
+		for i := 0; i < 3; i++ {
+			idp.claims = claims()
+			_, err := s.Exchange(ctx, "code", login)
+			require.ErrorIs(t, err, model.ErrInvalidIDToken)
+		}
+		require.Equal(t, fetches, idp.jwksFetches)
+
+		s.keysAt = time.Now().Add(-oidcJWKSRefetchInterval)
+		idp.claims = claims()
+		_, err := s.Exchange(ctx, "code", login)
+		require.ErrorIs(t, err, model.ErrInvalidIDToken)
+		require.Equal(t, fetches+1, idp.jwksFetches)
+	})
+
+	t.Run("user role is not mappable", func(t *testing.T) {
+		_, err := NewOIDCService(config.OIDC{Roles: map[string]int{"everyone": int(model.RoleUser)}})
+		require.Error(t, err)
+	})
+}
+
+func TestAuthService_ProvisionExternal(t *testing.T) {
+	ctx := context.Background()
+	identity := model.Identity{Issuer: "https://idp", Subject: "42", Username: "alice", Role: model.RoleAdmin}
+
+	t.Run("created on first sign in", func(t *testing.T) {
+		repo := new(mocks.CredentialsRepositoryMock)
+		repo.On("GetByIdentity", mock.Anything, "https://idp", "42").Return(model.Credentials{}, model.ErrUserNotFound)
+		repo.On("GetByUsername", mock.Anything, "alice").Return(model.Credentials{}, model.ErrUserNotFound)
+		repo.On("Create", mock.Anything, mock.MatchedBy(func(c model.Credentials) bool {
+			return c.Issuer == "https://idp" && c.Subject == "42" && c.Role == model.RoleAdmin && c.Password == ""
+		})).Return("1", nil)
+
+		s, err := NewAuthService(repo, nil, nil, testAuthConfig("new"))
+		require.NoError(t, err)
+
+		credentials, created, err := s.ProvisionExternal(ctx, identity)
+		require.NoError(t, err)
+		require.True(t, created)
+		require.Equal(t, "1", credentials.ID)
+	})
+
+	t.Run("local account is not taken over", func(t *testing.T) {
+		repo := new(mocks.CredentialsRepositoryMock)
+		repo.On("GetByIdentity", mock.Anything, "https://idp", "42").Return(model.Credentials{}, model.ErrUserNotFound)
+		repo.On("GetByUsername", mock.Anything, "alice").Return(model.Credentials{
+			CredentialsSecure: model.CredentialsSecure{ID: "1", Username: "alice", Role: model.RoleUser},
+		}, nil)
+
+		s, err := NewAuthService(repo, nil, nil, testAuthConfig("new"))
+		require.NoError(t, err)
+
+		_, _, err = s.ProvisionExternal(ctx, identity)
+		require.ErrorIs(t, err, model.ErrUserExists)
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("account of another subject is not taken over", func(t *testing.T) {
+		repo := new(mocks.CredentialsRepositoryMock)
+		repo.On("GetByIdentity", mock.Anything, "https://idp", "42").Return(model.Credentials{}, model.ErrUserNotFound)
+		repo.On("GetByUsername", mock.Anything, "alice").Return(model.Credentials{
+			CredentialsSecure: model.CredentialsSecure{ID: "1", Username: "alice", Role: model.RoleModerator},
+			Issuer:            "https://idp",
+			Subject:           "7",
+		}, nil)
+
+		s, err := NewAuthService(repo, nil, nil, testAuthConfig("new"))
+		require.NoError(t, err)
+
+		_, _, err = s.ProvisionExternal(ctx, identity)
+		require.ErrorIs(t, err, model.ErrUserExists)
+		repo.AssertNotCalled(t, "UpdateRole", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("renamed identity keeps its account and the role of the provider wins", func(t *testing.T) {
+		repo := new(mocks.CredentialsRepositoryMock)
+		repo.On("GetByIdentity", mock.Anything, "https://idp", "42").Return(model.Credentials{
+			CredentialsSecure: model.CredentialsSecure{ID: "1", Username: "alice.old", Role: model.RoleModerator},
+			Issuer:            "https://idp",
+			Subject:           "42",
+		}, nil)
+		repo.On("UpdateRole", mock.Anything, "1", model.RoleAdmin).Return(nil).Once()
+
+		s, err := NewAuthService(repo, nil, nil, testAuthConfig("new"))
+		require.NoError(t, err)
+
+		credentials, created, err := s.ProvisionExternal(ctx, identity)
+		require.NoError(t, err)
+		require.False(t, created)
+		require.Equal(t, "alice.old", credentials.Username)
+		require.Equal(t, model.RoleAdmin, credentials.Role)
+		repo.AssertExpectations(t)
+	})
+}
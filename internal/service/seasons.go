@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+type SeasonsRepository interface {
+	GetAll(ctx context.Context) (model.Seasons, error)
+	Set(ctx context.Context, season model.Season) error
+	Delete(ctx context.Context, name string) error
+}
+
+// SeasonCardsRepository counts the season cards, seasons in use can't be deleted.
+type SeasonCardsRepository interface {
+	CountStaticBySeason(ctx context.Context, season string) (int, error)
+}
+
+type SeasonsService struct {
+	repo      SeasonsRepository
+	cardsRepo SeasonCardsRepository
+}
+
+func NewSeasonsService(repo SeasonsRepository, cardsRepo SeasonCardsRepository) *SeasonsService {
+	return &SeasonsService{repo: repo, cardsRepo: cardsRepo}
+}
+
+func (s *SeasonsService) GetAll(ctx context.Context) (model.Seasons, error) {
+	return s.repo.GetAll(ctx)
+}
+
+// Set creates the season or replaces its dates, invalid dates are rejected
+// with a *model.ValidationError.
+func (s *SeasonsService) Set(ctx context.Context, season model.Season) (model.Season, error) {
+	season.Name = strings.TrimSpace(season.Name)
+
+	if err := season.Validate(); err != nil {
+		return model.Season{}, err
+	}
+	if err := s.repo.Set(ctx, season); err != nil {
+		return model.Season{}, err
+	}
+	return season, nil
+}
+
+// Delete removes the season, seasons with cards, archived ones too, can't be
+// deleted.
+func (s *SeasonsService) Delete(ctx context.Context, name string) error {
+	n, err := s.cardsRepo.CountStaticBySeason(ctx, name)
+	if err != nil {
+		return err
+	}
+	if n != 0 {
+		return model.ErrSeasonInUse
+	}
+
+	return s.repo.Delete(ctx, name)
+}
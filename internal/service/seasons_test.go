@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+	"github.com/Andrei-Raev/xp-loyalty/internal/model/mocks"
+)
+
+func TestSeasonsService(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	cardsRepo := new(mocks.CardsRepositoryMock)
+	// winter has an archived card only
+	cardsRepo.On("CountStaticBySeason", mock.Anything, "winter").Return(1, nil)
+	cardsRepo.On("CountStaticBySeason", mock.Anything, "summer").Return(0, nil)
+	repo := new(mocks.SeasonsRepositoryMock)
+	s := NewSeasonsService(repo, cardsRepo)
+
+	t.Run("set", func(t *testing.T) {
+		winter := model.Season{Name: "winter", StartsAt: start, EndsAt: start.AddDate(0, 3, 0)}
+		repo.On("Set", mock.Anything, winter).Return(nil).Once()
+
+		_, err := s.Set(ctx, model.Season{Name: " winter ", StartsAt: winter.StartsAt, EndsAt: winter.EndsAt})
+		require.NoError(t, err)
+
+		_, err = s.Set(ctx, model.Season{Name: "summer", StartsAt: start, EndsAt: start})
+		var verr *model.ValidationError
+		assert.ErrorAs(t, err, &verr)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		repo.On("Delete", mock.Anything, "summer").Return(nil).Once()
+
+		assert.ErrorIs(t, s.Delete(ctx, "winter"), model.ErrSeasonInUse)
+		assert.NoError(t, s.Delete(ctx, "summer"))
+		repo.AssertExpectations(t)
+	})
+}
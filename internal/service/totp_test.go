@@ -0,0 +1,35 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTOTP(t *testing.T) {
+	// RFC 6238 test vectors for SHA-1, truncated to 6 digits
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	tests := []struct {
+		time int64
+		code string
+	}{
+		{time: 59, code: "287082"},
+		{time: 1111111109, code: "081804"},
+		{time: 1234567890, code: "005924"},
+		{time: 2000000000, code: "279037"},
+	}
+
+	for _, tt := range tests {
+		code, err := totpCode(secret, tt.time/totpPeriod)
+		require.NoError(t, err)
+		require.Equal(t, tt.code, code)
+	}
+
+	now := time.Unix(1111111109, 0)
+	step, ok := validateTOTP(secret, "081804", now, 0)
+	require.True(t, ok)
+
+	_, ok = validateTOTP(secret, "081804", now, step)
+	require.False(t, ok, "code can't be reused")
+}
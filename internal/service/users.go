@@ -28,7 +28,7 @@ func NewUserService(userRepo UserRepository, awardsRepo AwardsRepo, imagesRepo I
 	}
 }
 
-func (s UserService) Create(ctx context.Context, id, username, avatarURL, nickname string, role model.Role) error {
+func (s UserService) Create(ctx context.Context, id, username, avatarURL, nickname, timezone string, role model.Role) error {
 	u := model.User{
 		CredentialsSecure: model.CredentialsSecure{
 			ID:       id,
@@ -40,11 +40,28 @@ func (s UserService) Create(ctx context.Context, id, username, avatarURL, nickna
 		XPoints:              0,
 		RegistrationTime:     time.Now(),
 		LastDailyCardsUpdate: time.Now().Add(-24 * time.Hour),
+		Timezone:             timezone,
 	}
 
 	return s.userRepo.Create(ctx, u)
 }
 
+// SetTimezone sets the timezone of the user, empty timezone stands for the
+// venue one. Cards days of the user start in it from now on.
+func (s UserService) SetTimezone(ctx context.Context, username, timezone string) error {
+	if err := model.ValidateTimezone(timezone); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	user.Timezone = timezone
+	return s.userRepo.Update(ctx, user)
+}
+
 func (s UserService) Prizes(ctx context.Context, username string) ([]model.UserPrize, error) {
 	allPrizes, err := s.imagesRepo.GetPrizes(ctx)
 	if err != nil {
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Andrei-Raev/xp-loyalty/internal/model"
+)
+
+func TestUserService_SetTimezone(t *testing.T) {
+	ctx := context.Background()
+	user := model.User{CredentialsSecure: model.CredentialsSecure{Username: "user"}}
+
+	t.Run("set", func(t *testing.T) {
+		usersRepo := newUsersRepo(user)
+		usersRepo.On("Update", mock.Anything, mock.MatchedBy(func(u model.User) bool {
+			return u.Username == "user" && u.Timezone == "Asia/Yekaterinburg"
+		})).Return(nil).Once()
+		s := NewUserService(usersRepo, nil, nil)
+
+		require.NoError(t, s.SetTimezone(ctx, "user", "Asia/Yekaterinburg"))
+		usersRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown timezone", func(t *testing.T) {
+		usersRepo := newUsersRepo(user)
+		s := NewUserService(usersRepo, nil, nil)
+
+		require.ErrorIs(t, s.SetTimezone(ctx, "user", "Mars/Olympus"), model.ErrUnknownTimezone)
+		usersRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("no user record", func(t *testing.T) {
+		s := NewUserService(newUsersRepo(), nil, nil)
+		require.ErrorIs(t, s.SetTimezone(ctx, "moderator", ""), model.ErrUserNotFound)
+	})
+}
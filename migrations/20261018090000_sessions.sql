@@ -0,0 +1,14 @@
+-- +goose Up
+
+-- sessions
+CREATE TABLE session (
+    id SERIAL PRIMARY KEY,
+    username VARCHAR(30) NOT NULL,
+    refresh_token VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS session;
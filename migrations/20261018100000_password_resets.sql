@@ -0,0 +1,15 @@
+-- +goose Up
+
+-- password resets
+CREATE TABLE password_reset (
+    id SERIAL PRIMARY KEY,
+    username VARCHAR(30) NOT NULL,
+    token VARCHAR(64) NOT NULL UNIQUE,
+    created_by VARCHAR(30) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS password_reset;
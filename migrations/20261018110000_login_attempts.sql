@@ -0,0 +1,12 @@
+-- +goose Up
+
+-- failed sign in attempts per username and client ip
+CREATE TABLE login_attempt (
+    key VARCHAR(100) PRIMARY KEY,
+    failures INTEGER NOT NULL,
+    last_failure TIMESTAMPTZ NOT NULL,
+    locked_until TIMESTAMPTZ NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS login_attempt;
@@ -0,0 +1,15 @@
+-- +goose Up
+
+-- two-factor authentication
+ALTER TABLE creds
+    ADD COLUMN totp_secret VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN totp_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN recovery_codes TEXT NOT NULL DEFAULT '',
+    ADD COLUMN totp_last_step BIGINT NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE creds
+    DROP COLUMN IF EXISTS totp_secret,
+    DROP COLUMN IF EXISTS totp_enabled,
+    DROP COLUMN IF EXISTS recovery_codes,
+    DROP COLUMN IF EXISTS totp_last_step;
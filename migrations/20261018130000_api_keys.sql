@@ -0,0 +1,17 @@
+-- +goose Up
+
+-- api keys
+CREATE TABLE api_key (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(20) NOT NULL,
+    key VARCHAR(64) NOT NULL UNIQUE,
+    permissions TEXT NOT NULL,
+    created_by VARCHAR(30) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    last_used_at TIMESTAMPTZ,
+    revoked BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS api_key;
@@ -0,0 +1,9 @@
+-- +goose Up
+
+-- accounts of an identity provider
+ALTER TABLE creds
+    ADD COLUMN issuer VARCHAR(200) NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE creds
+    DROP COLUMN IF EXISTS issuer;
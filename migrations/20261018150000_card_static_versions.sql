@@ -0,0 +1,11 @@
+-- +goose Up
+
+-- static card versions
+ALTER TABLE card_static
+    ADD COLUMN version INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+-- +goose Down
+ALTER TABLE card_static
+    DROP COLUMN IF EXISTS version,
+    DROP COLUMN IF EXISTS updated_at;
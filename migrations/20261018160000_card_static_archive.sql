@@ -0,0 +1,11 @@
+-- +goose Up
+
+-- archived static cards are not issued anymore
+ALTER TABLE card_static
+    ADD COLUMN archived BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN archived_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE card_static
+    DROP COLUMN IF EXISTS archived,
+    DROP COLUMN IF EXISTS archived_at;
@@ -0,0 +1,19 @@
+-- +goose Up
+
+-- goals, cards refer to them by name
+CREATE TABLE goal (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(30) NOT NULL UNIQUE,
+    title VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO goal (name, title)
+VALUES
+        ('food', 'Food'),
+        ('drink', 'Drink'),
+        ('play', 'Play'),
+        ('social', 'Social');
+
+-- +goose Down
+DROP TABLE IF EXISTS goal;
@@ -0,0 +1,9 @@
+-- +goose Up
+
+-- stable key of imported static cards
+ALTER TABLE card_static
+    ADD COLUMN external_id VARCHAR(100) UNIQUE;
+
+-- +goose Down
+ALTER TABLE card_static
+    DROP COLUMN IF EXISTS external_id;
@@ -0,0 +1,17 @@
+-- +goose Up
+
+-- streak cards, milestone awards are keyed by the day they are granted on
+ALTER TABLE card_static
+    ADD COLUMN streak_days INTEGER,
+    ADD COLUMN streak_grace_days INTEGER;
+
+ALTER TABLE award
+    ADD COLUMN streak_day INTEGER;
+
+-- +goose Down
+ALTER TABLE award
+    DROP COLUMN IF EXISTS streak_day;
+
+ALTER TABLE card_static
+    DROP COLUMN IF EXISTS streak_days,
+    DROP COLUMN IF EXISTS streak_grace_days;
@@ -0,0 +1,14 @@
+-- +goose Up
+
+-- steps of checklist cards, the award of the card is kept in award
+CREATE TABLE card_static_step (
+    id SERIAL PRIMARY KEY,
+    card_static_id INTEGER REFERENCES card_static(id),
+    position INTEGER NOT NULL,
+    step_id VARCHAR(100) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    UNIQUE (card_static_id, step_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS card_static_step;
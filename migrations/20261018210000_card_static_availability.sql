@@ -0,0 +1,17 @@
+-- +goose Up
+
+-- availability windows, weekdays are a bit mask with bit 0 for sunday
+ALTER TABLE card_static
+    ADD COLUMN starts_at TIMESTAMPTZ,
+    ADD COLUMN ends_at TIMESTAMPTZ,
+    ADD COLUMN weekdays INTEGER,
+    ADD COLUMN from_hour INTEGER,
+    ADD COLUMN to_hour INTEGER;
+
+-- +goose Down
+ALTER TABLE card_static
+    DROP COLUMN IF EXISTS starts_at,
+    DROP COLUMN IF EXISTS ends_at,
+    DROP COLUMN IF EXISTS weekdays,
+    DROP COLUMN IF EXISTS from_hour,
+    DROP COLUMN IF EXISTS to_hour;
@@ -0,0 +1,11 @@
+-- +goose Up
+
+-- completion caps and cooldowns of repeatable cards
+ALTER TABLE card_static
+    ADD COLUMN max_completions INTEGER,
+    ADD COLUMN cooldown_seconds INTEGER;
+
+-- +goose Down
+ALTER TABLE card_static
+    DROP COLUMN IF EXISTS max_completions,
+    DROP COLUMN IF EXISTS cooldown_seconds;
@@ -0,0 +1,17 @@
+-- +goose Up
+
+-- unlock requirements of const chains, chains are named by card_static.chain_name
+CREATE TABLE chain (
+    name VARCHAR(255) PRIMARY KEY,
+    min_level INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE chain_requirement (
+    chain_name VARCHAR(255) NOT NULL REFERENCES chain(name) ON DELETE CASCADE,
+    requires VARCHAR(255) NOT NULL,
+    PRIMARY KEY (chain_name, requires)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS chain_requirement;
+DROP TABLE IF EXISTS chain;
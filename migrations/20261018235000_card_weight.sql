@@ -0,0 +1,9 @@
+-- +goose Up
+
+-- weights of cards picked at random, 0 is the default weight
+ALTER TABLE card_static
+    ADD COLUMN weight INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE card_static
+    DROP COLUMN IF EXISTS weight;
@@ -0,0 +1,9 @@
+-- +goose Up
+
+-- timezones users get their daily cards in, empty is the venue timezone
+ALTER TABLE usr
+    ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE usr
+    DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,24 @@
+-- +goose Up
+
+-- seasonal card pools, cards of the season pool name their season in card_static.season
+CREATE TABLE season (
+    name VARCHAR(255) PRIMARY KEY,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL
+);
+
+ALTER TABLE card_static
+    ADD COLUMN season VARCHAR(255);
+
+-- weekly cards are replaced apart from the daily ones
+ALTER TABLE usr
+    ADD COLUMN last_weekly_cards_update TIMESTAMPTZ NOT NULL DEFAULT '1970-01-01';
+
+-- +goose Down
+ALTER TABLE usr
+    DROP COLUMN IF EXISTS last_weekly_cards_update;
+
+ALTER TABLE card_static
+    DROP COLUMN IF EXISTS season;
+
+DROP TABLE IF EXISTS season;
@@ -0,0 +1,13 @@
+-- +goose Up
+
+-- accounts of an identity provider are matched by the issuer and the subject
+ALTER TABLE creds
+    ADD COLUMN subject VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX creds_issuer_subject ON creds (issuer, subject) WHERE subject <> '';
+
+-- +goose Down
+DROP INDEX IF EXISTS creds_issuer_subject;
+
+ALTER TABLE creds
+    DROP COLUMN IF EXISTS subject;
@@ -4,15 +4,21 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 )
 
 type Config struct {
-	ServerPort        string `json:"server_port"`
-	ModeratorUsername string `json:"moderator_username"`
-	ModeratorPassword string `json:"moderator_password"`
-	Mongo             Mongo  `json:"mongo"`
-	SQL               SQL    `json:"sql"`
-	User              User   `json:"user"`
+	ServerPort string `json:"server_port"`
+	// TrustedProxies are the addresses or CIDRs of reverse proxies whose
+	// X-Forwarded-For is believed, with none the client IP is the peer address.
+	TrustedProxies    []string `json:"trusted_proxies"`
+	ModeratorUsername string   `json:"moderator_username"`
+	ModeratorPassword string   `json:"moderator_password"`
+	Mongo             Mongo    `json:"mongo"`
+	SQL               SQL      `json:"sql"`
+	User              User     `json:"user"`
+	Auth              Auth     `json:"auth"`
 }
 
 type Mongo struct {
@@ -28,9 +34,134 @@ type SQL struct {
 	Database string `json:"database"`
 }
 
+// User configures daily and weekly cards. Daily cards are replaced every day
+// at ResetHour in the timezone of the user, users without one are in Timezone
+// of the venue, the server timezone if it is empty. Weekly cards are replaced
+// on WeeklyResetDay (monday if empty) at the same hour, with zero
+// WeeklyCardsNum there are none.
 type User struct {
-	DailyCardsNum int  `json:"daily_cards_num"`
-	UniqueGoals   bool `json:"unique_goals"`
+	DailyCardsNum  int            `json:"daily_cards_num"`
+	UniqueGoals    bool           `json:"unique_goals"`
+	DailySelection DailySelection `json:"daily_selection"`
+	Timezone       string         `json:"timezone"`
+	ResetHour      int            `json:"reset_hour"`
+	WeeklyCardsNum int            `json:"weekly_cards_num"`
+	WeeklyResetDay string         `json:"weekly_reset_day"`
+}
+
+// Location returns the venue timezone.
+func (u User) Location() (*time.Location, error) {
+	if u.Timezone == "" {
+		return time.Local, nil
+	}
+
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("error loading venue timezone: %w", err)
+	}
+	return loc, nil
+}
+
+// WeeklyReset returns the weekday weekly cards are replaced on.
+func (u User) WeeklyReset() (time.Weekday, error) {
+	if u.WeeklyResetDay == "" {
+		return time.Monday, nil
+	}
+
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(u.WeeklyResetDay, d.String()) {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("error unknown weekly reset day %q", u.WeeklyResetDay)
+}
+
+// DailySelection configures how daily cards are picked. Strategy is "uniform"
+// (the default) or "personal", which skips cards issued to the user in the last
+// RecentDays and favours the goals they completed in the last HistoryDays.
+// Draws are seeded by SeedSecret, the username and the date, so they can be
+// replayed, the secret keeps users from predicting their cards.
+type DailySelection struct {
+	Strategy    string `json:"strategy"`
+	RecentDays  int    `json:"recent_days"`
+	HistoryDays int    `json:"history_days"`
+	SeedSecret  string `json:"seed_secret"`
+}
+
+type Auth struct {
+	PasswordCost    int          `json:"password_cost"`
+	TokenTTL        Duration     `json:"token_ttl"`
+	RefreshTokenTTL Duration     `json:"refresh_token_ttl"`
+	ResetTokenTTL   Duration     `json:"reset_token_ttl"`
+	Issuer          string       `json:"issuer"`
+	Audience        string       `json:"audience"`
+	SigningKeyID    string       `json:"signing_key_id"`
+	SigningKeys     []SigningKey `json:"signing_keys"`
+	Lockout         Lockout      `json:"lockout"`
+	TwoFactor       TwoFactor    `json:"two_factor"`
+	OIDC            OIDC         `json:"oidc"`
+}
+
+// OIDC configures staff sign in through an OpenID Connect provider, it is
+// disabled without Issuer. Roles maps values of RoleClaim (a string or a list
+// of strings such as groups) to model roles, users without a mapped role can't
+// sign in. UsernameClaim defaults to preferred_username.
+type OIDC struct {
+	Issuer        string         `json:"issuer"`
+	ClientID      string         `json:"client_id"`
+	ClientSecret  string         `json:"client_secret"`
+	RedirectURL   string         `json:"redirect_url"`
+	Scopes        []string       `json:"scopes"`
+	UsernameClaim string         `json:"username_claim"`
+	RoleClaim     string         `json:"role_claim"`
+	Roles         map[string]int `json:"roles"`
+}
+
+// TwoFactor configures TOTP. Accounts with one of RequiredRoles have to enroll
+// before their first sign in completes, for other roles enrollment is optional.
+// Issuer is the account label shown by authenticator apps.
+type TwoFactor struct {
+	Issuer        string   `json:"issuer"`
+	RequiredRoles []int    `json:"required_roles"`
+	ChallengeTTL  Duration `json:"challenge_ttl"`
+}
+
+// Lockout configures sign in throttling. UsernameAttempts (IPAttempts) failed
+// sign ins lock the username (IP) for BaseDelay, every further failure doubles
+// the lock up to MaxDelay. Failures are forgotten after ResetAfter without new ones.
+type Lockout struct {
+	UsernameAttempts int      `json:"username_attempts"`
+	IPAttempts       int      `json:"ip_attempts"`
+	BaseDelay        Duration `json:"base_delay"`
+	MaxDelay         Duration `json:"max_delay"`
+	ResetAfter       Duration `json:"reset_after"`
+}
+
+// SigningKey is a JWT HMAC key. Every key in the list is accepted for
+// verification, only the one with Auth.SigningKeyID is used to sign new tokens.
+type SigningKey struct {
+	ID  string `json:"id"`
+	Key string `json:"key"`
+}
+
+// Duration is a time.Duration decoded from a string such as "15m" or "24h".
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("error decoding duration: %w", err)
+	}
+
+	duration, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("error decoding duration: %w", err)
+	}
+
+	d.Duration = duration
+	return nil
 }
 
 func New(path string) (*Config, error) {
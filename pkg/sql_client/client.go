@@ -7,7 +7,8 @@ import (
 
 	_ "github.com/jackc/pgx/v4/stdlib"
 	"github.com/jmoiron/sqlx"
-	"github.com/wintermonth2298/xp-loyalty/pkg/config"
+
+	"github.com/Andrei-Raev/xp-loyalty/pkg/config"
 )
 
 func New(ctx context.Context, cfg config.SQL) (*sqlx.DB, error) {